@@ -0,0 +1,90 @@
+// Package actions makes changes to the system on the user's behalf:
+// firewall rules, signals, socket teardown, and link, address, route and
+// neighbor edits. Each change is an Action that can be previewed before
+// it is applied.
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/data/sources"
+)
+
+// ErrNoCheck is returned by DryRun when the backend has no mode that
+// validates a change without applying it.
+var ErrNoCheck = errors.New("no check mode; nothing was applied")
+
+// Action is a change to the system. It is shown in a confirmation modal,
+// with the exact rules or commands it applies, before it runs.
+type Action struct {
+	Title     string
+	Details   []string       // what the change affects
+	Preview   []string       // the rules or commands applied, verbatim
+	Namespace data.Namespace // where it runs, zero for nettui's own namespace
+	Revert    *Action        // undoes the change, nil if it cannot be undone
+
+	apply func() error
+	check func() error // validates the change without applying it, nil if unsupported
+}
+
+// Apply makes the change inside the action's namespace.
+func (a Action) Apply() error {
+	if a.apply == nil {
+		return errors.New("nothing to apply")
+	}
+	return inNamespace(a.Namespace, a.apply)
+}
+
+// DryRun validates the change without applying it, where the backend
+// can. It returns ErrNoCheck when it cannot.
+func (a Action) DryRun() error {
+	if a.check == nil {
+		return ErrNoCheck
+	}
+	return inNamespace(a.Namespace, a.check)
+}
+
+// CanDryRun reports whether DryRun can validate the action.
+func (a Action) CanDryRun() bool {
+	return a.check != nil
+}
+
+// inNamespace runs fn inside ns and returns its error.
+func inNamespace(ns data.Namespace, fn func() error) error {
+	var err error
+	if nsErr := sources.RunInNamespace(ns, func() { err = fn() }); nsErr != nil {
+		return nsErr
+	}
+	return err
+}
+
+// run executes a command with stdin, if not empty, returning what it
+// printed to stdout. A failing command's error includes what it printed to
+// stderr.
+func run(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return stdout.String(), fmt.Errorf("%s: %v: %s", commandLine(name, args...), err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// commandLine renders a command for a preview.
+func commandLine(name string, args ...string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
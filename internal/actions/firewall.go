@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// ManagedName names the pf anchor, nftables table and iptables chain that
+// hold the rules nettui adds, so they stay apart from the host's own.
+const ManagedName = "nettui"
+
+// Firewall adds and removes rules in the nettui-managed part of a
+// firewall backend.
+type Firewall interface {
+	// Name names the backend: pf, nftables or iptables.
+	Name() string
+	// Rule returns the action that blocks, or with allow set allows, all
+	// traffic to and from addr.
+	Rule(addr net.IP, allow bool, ns data.Namespace) Action
+	// List returns the rules nettui manages in ns.
+	List(ns data.Namespace) ([]ManagedRule, error)
+}
+
+// ManagedRule is a rule in the nettui-managed anchor or chain.
+type ManagedRule struct {
+	Rule   string // as the backend lists it
+	Remove Action
+}
+
+// DetectFirewall picks the backend whose tool is installed: pf where
+// pfctl is, otherwise nftables, otherwise iptables. rules are the loaded
+// pf rules, used to find an anchor the main ruleset evaluates.
+func DetectFirewall(rules []data.FirewallRule) (Firewall, error) {
+	switch {
+	case hasTool("pfctl"):
+		return newPF(rules), nil
+	case hasTool("nft"):
+		return nftables{}, nil
+	case hasTool("iptables"):
+		return iptables{}, nil
+	}
+	return nil, errors.New("no supported firewall found (pfctl, nft or iptables)")
+}
+
+func hasTool(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// ruleTitle describes a block or allow rule for the confirmation modal.
+func ruleTitle(addr net.IP, allow bool) string {
+	if allow {
+		return fmt.Sprintf("Allow %s", addr)
+	}
+	return fmt.Sprintf("Block %s", addr)
+}
+
+// managedList collects rules and their removal actions.
+func managedList(rules []string, remove func(string) Action) []ManagedRule {
+	list := make([]ManagedRule, 0, len(rules))
+	for _, r := range rules {
+		list = append(list, ManagedRule{Rule: r, Remove: remove(r)})
+	}
+	return list
+}
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// scratchNamespace creates a network namespace for the duration of t, so
+// firewall changes never touch the host's rules.
+func scratchNamespace(t *testing.T) data.Namespace {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("needs root to create a network namespace")
+	}
+	name := fmt.Sprintf("nettui-test-%d", os.Getpid())
+	if out, err := exec.Command("ip", "netns", "add", name).CombinedOutput(); err != nil {
+		t.Skipf("ip netns add: %v: %s", err, out)
+	}
+	t.Cleanup(func() { _ = exec.Command("ip", "netns", "del", name).Run() })
+	return data.Namespace{Name: name, Path: "/run/netns/" + name}
+}
+
+// TestManagedRules adds block and allow rules with each installed Linux
+// backend, lists them and removes them again.
+func TestManagedRules(t *testing.T) {
+	for _, fw := range []Firewall{nftables{}, iptables{}} {
+		t.Run(fw.Name(), func(t *testing.T) {
+			tool := "nft"
+			if fw.Name() == "iptables" {
+				tool = "iptables"
+			}
+			if !hasTool(tool) {
+				t.Skipf("%s not installed", tool)
+			}
+			ns := scratchNamespace(t)
+
+			for _, a := range []Action{
+				fw.Rule(net.ParseIP("203.0.113.5"), false, ns),
+				fw.Rule(net.ParseIP("2001:db8::5"), true, ns),
+			} {
+				if a.CanDryRun() {
+					if err := a.DryRun(); err != nil {
+						t.Fatalf("dry run %s: %v", a.Title, err)
+					}
+				}
+				if err := a.Apply(); err != nil {
+					t.Fatalf("apply %s: %v", a.Title, err)
+				}
+			}
+
+			rules, err := fw.List(ns)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(rules) != 4 {
+				t.Fatalf("listed %d rules, want 4: %v", len(rules), rules)
+			}
+			for _, want := range []string{"203.0.113.5", "2001:db8::5"} {
+				found := false
+				for _, r := range rules {
+					found = found || strings.Contains(r.Rule, want)
+				}
+				if !found {
+					t.Errorf("no rule for %s in %v", want, rules)
+				}
+			}
+
+			for _, r := range rules {
+				if err := r.Remove.Apply(); err != nil {
+					t.Fatalf("remove %q: %v", r.Rule, err)
+				}
+			}
+			if rules, err = fw.List(ns); err != nil || len(rules) != 0 {
+				t.Fatalf("after removal listed %v, %v; want none", rules, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"net"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// iptablesChain is the chain holding nettui's rules, jumped to from the
+// top of INPUT and OUTPUT.
+var iptablesChain = strings.ToUpper(ManagedName)
+
+// iptables manages rules in the NETTUI chain of the filter table, with
+// ip6tables for IPv6 addresses. iptables has no check mode, so its
+// actions have no dry run.
+type iptables struct{}
+
+func (iptables) Name() string { return "iptables" }
+
+func (iptables) Rule(addr net.IP, allow bool, ns data.Namespace) Action {
+	tool, target := iptablesTool(addr), "DROP"
+	if allow {
+		target = "ACCEPT"
+	}
+	rules := [][]string{
+		{"-A", iptablesChain, "-s", addr.String(), "-j", target},
+		{"-A", iptablesChain, "-d", addr.String(), "-j", target},
+	}
+	a := Action{
+		Title:     ruleTitle(addr, allow),
+		Details:   []string{"Appended to the " + iptablesChain + " chain, jumped to from INPUT and OUTPUT"},
+		Namespace: ns,
+	}
+	for _, setup := range iptablesSetup() {
+		a.Preview = append(a.Preview, commandLine(tool, setup...)+"   (if missing)")
+	}
+	for _, r := range rules {
+		a.Preview = append(a.Preview, commandLine(tool, r...))
+	}
+	a.apply = func() error {
+		if err := ensureIptablesChain(tool); err != nil {
+			return err
+		}
+		for _, r := range rules {
+			if _, err := run("", tool, r...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return a
+}
+
+func (iptables) List(ns data.Namespace) ([]ManagedRule, error) {
+	var specs []string
+	err := inNamespace(ns, func() error {
+		for _, tool := range []string{"iptables", "ip6tables"} {
+			if !hasTool(tool) {
+				continue
+			}
+			out, err := run("", tool, "-S", iptablesChain)
+			if err != nil {
+				if strings.Contains(err.Error(), "No chain") {
+					continue // no chain yet: nothing added
+				}
+				return err
+			}
+			for _, line := range strings.Split(out, "\n") {
+				if strings.HasPrefix(line, "-A ") {
+					specs = append(specs, tool+" "+strings.TrimSpace(line))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return managedList(specs, func(spec string) Action {
+		fields := strings.Fields(spec)
+		tool, args := fields[0], append([]string{"-D"}, fields[2:]...)
+		return Action{
+			Title:     "Remove iptables rule",
+			Details:   []string{"From the " + iptablesChain + " chain"},
+			Preview:   []string{spec, "", "removed with: " + commandLine(tool, args...)},
+			Namespace: ns,
+			apply: func() error {
+				_, err := run("", tool, args...)
+				return err
+			},
+		}
+	}), nil
+}
+
+func iptablesTool(addr net.IP) string {
+	if addr.To4() == nil {
+		return "ip6tables"
+	}
+	return "iptables"
+}
+
+// iptablesSetup lists the commands creating the chain and the jumps to it.
+func iptablesSetup() [][]string {
+	return [][]string{
+		{"-N", iptablesChain},
+		{"-I", "INPUT", "-j", iptablesChain},
+		{"-I", "OUTPUT", "-j", iptablesChain},
+	}
+}
+
+// ensureIptablesChain creates the chain and the jumps to it unless they
+// exist.
+func ensureIptablesChain(tool string) error {
+	if _, err := run("", tool, "-S", iptablesChain); err != nil {
+		if _, err := run("", tool, "-N", iptablesChain); err != nil {
+			return err
+		}
+	}
+	for _, hook := range []string{"INPUT", "OUTPUT"} {
+		if _, err := run("", tool, "-C", hook, "-j", iptablesChain); err == nil {
+			continue
+		}
+		if _, err := run("", tool, "-I", hook, "-j", iptablesChain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// SetLinkUp returns the action bringing iface up, or down unless up is
+// set, through rtnetlink. Its revert restores the current state.
+func SetLinkUp(iface data.Interface, up bool, ns data.Namespace) (Action, error) {
+	state, verb := "down", "Bring down"
+	if up {
+		state, verb = "up", "Bring up"
+	}
+	a := Action{
+		Title:     fmt.Sprintf("%s %s", verb, iface.Name),
+		Preview:   []string{commandLine("ip", "link", "set", "dev", iface.Name, state)},
+		Namespace: ns,
+		apply: func() error {
+			link, err := netlink.LinkByName(iface.Name)
+			if err != nil {
+				return err
+			}
+			if up {
+				return rtnlError("LinkSetUp", netlink.LinkSetUp(link))
+			}
+			return rtnlError("LinkSetDown", netlink.LinkSetDown(link))
+		},
+	}
+	if !up {
+		a.Details = []string{fmt.Sprintf("Traffic through %s stops, including remote sessions over it", iface.Name)}
+	}
+	if iface.Up != up {
+		revert, _ := SetLinkUp(iface, iface.Up, ns)
+		a.Revert = &revert
+	}
+	return a, nil
+}
+
+// SetMTU returns the action changing the MTU of iface, given as typed in.
+// Its revert restores the current MTU.
+func SetMTU(iface data.Interface, input string, ns data.Namespace) (Action, error) {
+	mtu, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || mtu < 68 || mtu > 65536 {
+		return Action{}, fmt.Errorf("MTU must be a number from 68 to 65536, not %q", input)
+	}
+	a := mtuAction(iface, mtu, ns)
+	a.Details = []string{fmt.Sprintf("Currently %d", iface.MTU)}
+	if iface.MTU > 0 && iface.MTU != mtu {
+		revert := mtuAction(iface, iface.MTU, ns)
+		a.Revert = &revert
+	}
+	return a, nil
+}
+
+// mtuAction sets the MTU of iface.
+func mtuAction(iface data.Interface, mtu int, ns data.Namespace) Action {
+	return Action{
+		Title:     fmt.Sprintf("Set MTU of %s to %d", iface.Name, mtu),
+		Preview:   []string{commandLine("ip", "link", "set", "dev", iface.Name, "mtu", strconv.Itoa(mtu))},
+		Namespace: ns,
+		apply: func() error {
+			link, err := netlink.LinkByName(iface.Name)
+			if err != nil {
+				return err
+			}
+			return rtnlError("LinkSetMTU", netlink.LinkSetMTU(link, mtu))
+		},
+	}
+}
+
+// AddAddress returns the action adding an address in CIDR notation, like
+// "10.0.0.5/24", to iface. Its revert removes it again.
+func AddAddress(iface data.Interface, cidr string, ns data.Namespace) (Action, error) {
+	cidr = strings.TrimSpace(cidr)
+	addr, err := netlink.ParseAddr(cidr)
+	if err != nil {
+		return Action{}, fmt.Errorf("address must be in CIDR notation, like 10.0.0.5/24: %v", err)
+	}
+	a := addressAction(iface, addr, true, ns)
+	revert := addressAction(iface, addr, false, ns)
+	a.Revert = &revert
+	return a, nil
+}
+
+// RemoveAddress returns the action removing addr from iface. Its revert
+// adds it back, as a static address: lifetimes and flags of a dynamic
+// address are not restored.
+func RemoveAddress(iface data.Interface, addr data.Address, ns data.Namespace) (Action, error) {
+	parsed, err := netlink.ParseAddr(fmt.Sprintf("%s/%d", addr.IP, addr.PrefixLen))
+	if err != nil {
+		return Action{}, err
+	}
+	a := addressAction(iface, parsed, false, ns)
+	if addr.ValidLifetime != 0 && addr.ValidLifetime != data.Forever {
+		a.Details = append(a.Details, "A dynamic address; DHCP or SLAAC may add it back")
+	}
+	revert := addressAction(iface, parsed, true, ns)
+	a.Revert = &revert
+	return a, nil
+}
+
+// addressAction adds or removes addr on iface.
+func addressAction(iface data.Interface, addr *netlink.Addr, add bool, ns data.Namespace) Action {
+	verb, title := "del", fmt.Sprintf("Remove %s from %s", addr.IPNet, iface.Name)
+	if add {
+		verb, title = "add", fmt.Sprintf("Add %s to %s", addr.IPNet, iface.Name)
+	}
+	return Action{
+		Title:     title,
+		Preview:   []string{commandLine("ip", "addr", verb, addr.IPNet.String(), "dev", iface.Name)},
+		Namespace: ns,
+		apply: func() error {
+			link, err := netlink.LinkByName(iface.Name)
+			if err != nil {
+				return err
+			}
+			if add {
+				return rtnlError("AddrAdd", netlink.AddrAdd(link, addr))
+			}
+			return rtnlError("AddrDel", netlink.AddrDel(link, addr))
+		},
+	}
+}
+
+// rtnlError names the rtnetlink call that failed and explains the common
+// errors.
+func rtnlError(call string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, unix.EPERM), errors.Is(err, unix.EACCES):
+		return fmt.Errorf("%s: %w (needs CAP_NET_ADMIN; run nettui as root)", call, err)
+	case errors.Is(err, unix.EEXIST):
+		return fmt.Errorf("%s: %w (it already exists)", call, err)
+	case errors.Is(err, unix.ESRCH), errors.Is(err, unix.ENOENT), errors.Is(err, unix.EADDRNOTAVAIL):
+		return fmt.Errorf("%s: %w (it no longer exists)", call, err)
+	case errors.Is(err, unix.ENETUNREACH):
+		return fmt.Errorf("%s: %w (the gateway is not on a directly connected network)", call, err)
+	}
+	return fmt.Errorf("%s: %w", call, err)
+}
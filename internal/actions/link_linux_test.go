@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"net"
+	"os/exec"
+	"testing"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+)
+
+// TestLinkChanges applies each link action to a veth interface in a
+// scratch namespace, then its revert, checking the link after each.
+func TestLinkChanges(t *testing.T) {
+	ns := scratchNamespace(t)
+	if out, err := exec.Command("ip", "-n", ns.Name, "link", "add", "v0", "type", "veth", "peer", "name", "v1").CombinedOutput(); err != nil {
+		t.Skipf("ip link add: %v: %s", err, out)
+	}
+	link := func() netlink.Link {
+		t.Helper()
+		var l netlink.Link
+		if err := inNamespace(ns, func() (err error) {
+			l, err = netlink.LinkByName("v0")
+			return err
+		}); err != nil {
+			t.Fatalf("LinkByName: %v", err)
+		}
+		return l
+	}
+	hasAddr := func(cidr string) bool {
+		t.Helper()
+		var found bool
+		if err := inNamespace(ns, func() error {
+			l, err := netlink.LinkByName("v0")
+			if err != nil {
+				return err
+			}
+			addrs, err := netlink.AddrList(l, netlink.FAMILY_ALL)
+			for _, a := range addrs {
+				found = found || a.IPNet.String() == cidr
+			}
+			return err
+		}); err != nil {
+			t.Fatalf("AddrList: %v", err)
+		}
+		return found
+	}
+	apply := func(a Action, err error) Action {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if err := a.Apply(); err != nil {
+			t.Fatalf("apply %s: %v", a.Title, err)
+		}
+		return a
+	}
+	revert := func(a Action) {
+		t.Helper()
+		if a.Revert == nil {
+			t.Fatalf("%s has no revert", a.Title)
+		}
+		if err := a.Revert.Apply(); err != nil {
+			t.Fatalf("revert %s: %v", a.Title, err)
+		}
+	}
+	iface := data.Interface{Name: "v0", MTU: 1500}
+
+	a := apply(SetLinkUp(iface, true, ns))
+	if link().Attrs().Flags&net.FlagUp == 0 {
+		t.Errorf("%s: link is down", a.Title)
+	}
+	revert(a)
+	if link().Attrs().Flags&net.FlagUp != 0 {
+		t.Errorf("revert %s: link is up", a.Title)
+	}
+
+	a = apply(SetMTU(iface, "1400", ns))
+	if mtu := link().Attrs().MTU; mtu != 1400 {
+		t.Errorf("%s: MTU %d", a.Title, mtu)
+	}
+	revert(a)
+	if mtu := link().Attrs().MTU; mtu != 1500 {
+		t.Errorf("revert %s: MTU %d", a.Title, mtu)
+	}
+	if _, err := SetMTU(iface, "9", ns); err == nil {
+		t.Error("MTU 9 accepted")
+	}
+
+	for _, cidr := range []string{"10.9.8.7/24", "fd00::7/64"} {
+		a = apply(AddAddress(iface, cidr, ns))
+		if !hasAddr(cidr) {
+			t.Errorf("%s: address missing", a.Title)
+		}
+		revert(a)
+		if hasAddr(cidr) {
+			t.Errorf("revert %s: address still present", a.Title)
+		}
+	}
+	if _, err := AddAddress(iface, "10.9.8.7", ns); err == nil {
+		t.Error("address without prefix length accepted")
+	}
+
+	apply(AddAddress(iface, "10.9.8.7/24", ns))
+	a = apply(RemoveAddress(iface, data.Address{IP: "10.9.8.7", PrefixLen: 24}, ns))
+	if hasAddr("10.9.8.7/24") {
+		t.Errorf("%s: address still present", a.Title)
+	}
+	revert(a)
+	if !hasAddr("10.9.8.7/24") {
+		t.Errorf("revert %s: address missing", a.Title)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !linux
+
+package actions
+
+import (
+	"errors"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// errNoRtnetlink is returned for interface, route and neighbor changes
+// outside Linux.
+var errNoRtnetlink = errors.New("interface, route and neighbor changes need Linux's rtnetlink")
+
+// SetLinkUp is Linux-only.
+func SetLinkUp(iface data.Interface, up bool, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// SetMTU is Linux-only.
+func SetMTU(iface data.Interface, input string, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// AddAddress is Linux-only.
+func AddAddress(iface data.Interface, cidr string, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// RemoveAddress is Linux-only.
+func RemoveAddress(iface data.Interface, addr data.Address, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
@@ -0,0 +1,193 @@
+//go:build linux
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// maxListedNeighbors bounds the entries a flush lists as deleted.
+const maxListedNeighbors = 10
+
+// AddStaticNeighbor returns the action adding a permanent neighbor entry
+// typed in as "10.0.0.9 02:42:ac:11:00:02", on dev unless the input ends
+// with "dev" and an interface. An existing entry for the address is
+// replaced; the revert restores it, or deletes the new entry if there
+// was none.
+func AddStaticNeighbor(input, dev string, ns data.Namespace) (Action, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 4 && fields[2] == "dev" {
+		dev = fields[3]
+	} else if len(fields) != 2 {
+		return Action{}, fmt.Errorf("entry must be an address and a MAC, optionally followed by dev and an interface, not %q", input)
+	}
+	ip := net.ParseIP(fields[0])
+	if ip == nil {
+		return Action{}, fmt.Errorf("%q is not an IP address", fields[0])
+	}
+	mac, err := net.ParseMAC(fields[1])
+	if err != nil {
+		return Action{}, fmt.Errorf("%q is not a MAC address", fields[1])
+	}
+	if dev == "" {
+		return Action{}, errors.New("entry needs an interface: add dev and its name")
+	}
+
+	n := netlink.Neigh{Family: ipFamily(ip), IP: ip, HardwareAddr: mac, State: netlink.NUD_PERMANENT}
+	var old *netlink.Neigh
+	err = inNamespace(ns, func() error {
+		link, err := netlink.LinkByName(dev)
+		if err != nil {
+			return fmt.Errorf("interface %s: %w", dev, err)
+		}
+		n.LinkIndex = link.Attrs().Index
+		neighs, err := netlink.NeighList(n.LinkIndex, n.Family)
+		if err != nil {
+			return rtnlError("NeighList", err)
+		}
+		for i, e := range neighs {
+			if e.IP.Equal(ip) && len(e.HardwareAddr) > 0 {
+				old = &neighs[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Action{}, err
+	}
+
+	a := neighAction("replace", n, dev, ns)
+	a.Title = fmt.Sprintf("Add static neighbor %s at %s on %s", ip, mac, dev)
+	var revert Action
+	if old != nil {
+		a.Details = []string{fmt.Sprintf("Replaces the entry at %s (%s)", old.HardwareAddr, neighStateName(old.State))}
+		revert = neighAction("replace", *old, dev, ns)
+	} else {
+		revert = neighAction("del", n, dev, ns)
+	}
+	a.Revert = &revert
+	return a, nil
+}
+
+// FlushStaleNeighbors returns the action deleting the neighbor entries in
+// the stale state, as `ip neigh flush nud stale` does. Its revert adds
+// them back as stale entries.
+func FlushStaleNeighbors(ns data.Namespace) (Action, error) {
+	var stale []netlink.Neigh
+	names := make(map[int]string)
+	err := inNamespace(ns, func() error {
+		neighs, err := netlink.NeighList(0, netlink.FAMILY_ALL)
+		if err != nil {
+			return rtnlError("NeighList", err)
+		}
+		for _, n := range neighs {
+			if n.State&netlink.NUD_STALE == 0 || n.IP == nil {
+				continue
+			}
+			stale = append(stale, n)
+			if _, ok := names[n.LinkIndex]; !ok {
+				if link, err := netlink.LinkByIndex(n.LinkIndex); err == nil {
+					names[n.LinkIndex] = link.Attrs().Name
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Action{}, err
+	}
+	if len(stale) == 0 {
+		return Action{}, errors.New("no stale neighbor entries")
+	}
+
+	a := Action{
+		Title:     fmt.Sprintf("Flush %d stale neighbor entries", len(stale)),
+		Details:   []string{fmt.Sprintf("Entries deleted (%d):", len(stale))},
+		Preview:   []string{commandLine("ip", "neigh", "flush", "nud", "stale")},
+		Namespace: ns,
+		apply: func() error {
+			for _, n := range stale {
+				err := netlink.NeighDel(&n)
+				// Entries the kernel dropped itself since are fine.
+				if err != nil && !errors.Is(err, unix.ENOENT) {
+					return rtnlError("NeighDel", err)
+				}
+			}
+			return nil
+		},
+		check: needNetAdmin,
+	}
+	revert := Action{
+		Title:     fmt.Sprintf("Restore %d stale neighbor entries", len(stale)),
+		Namespace: ns,
+		apply: func() error {
+			for _, n := range stale {
+				if err := netlink.NeighSet(&n); err != nil {
+					return rtnlError("NeighSet", err)
+				}
+			}
+			return nil
+		},
+		check: needNetAdmin,
+	}
+	for i, n := range stale {
+		if i < maxListedNeighbors {
+			a.Details = append(a.Details, fmt.Sprintf("  %s at %s on %s", n.IP, n.HardwareAddr, names[n.LinkIndex]))
+		} else if i == maxListedNeighbors {
+			a.Details = append(a.Details, fmt.Sprintf("  … and %d more", len(stale)-i))
+		}
+		revert.Preview = append(revert.Preview, neighAction("replace", n, names[n.LinkIndex], ns).Preview...)
+	}
+	a.Revert = &revert
+	return a, nil
+}
+
+// neighAction replaces or deletes n, on the interface named dev.
+func neighAction(verb string, n netlink.Neigh, dev string, ns data.Namespace) Action {
+	args := []string{"neigh", verb, n.IP.String()}
+	title := fmt.Sprintf("Delete neighbor %s on %s", n.IP, dev)
+	if verb == "replace" {
+		args = append(args, "lladdr", n.HardwareAddr.String())
+		title = fmt.Sprintf("Set neighbor %s to %s on %s", n.IP, n.HardwareAddr, dev)
+	}
+	args = append(args, "dev", dev)
+	if verb == "replace" {
+		args = append(args, "nud", neighStateName(n.State))
+	}
+	return Action{
+		Title:     title,
+		Preview:   []string{commandLine("ip", args...)},
+		Namespace: ns,
+		apply: func() error {
+			if verb == "del" {
+				return rtnlError("NeighDel", netlink.NeighDel(&n))
+			}
+			return rtnlError("NeighSet", netlink.NeighSet(&n))
+		},
+		check: needNetAdmin,
+	}
+}
+
+// neighStateName names a NUD_* state as ip's nud argument takes it.
+func neighStateName(state int) string {
+	switch {
+	case state&netlink.NUD_PERMANENT != 0:
+		return "permanent"
+	case state&netlink.NUD_NOARP != 0:
+		return "noarp"
+	case state&netlink.NUD_REACHABLE != 0:
+		return "reachable"
+	case state&netlink.NUD_DELAY != 0:
+		return "delay"
+	case state&netlink.NUD_PROBE != 0:
+		return "probe"
+	}
+	return "stale"
+}
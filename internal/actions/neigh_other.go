@@ -0,0 +1,15 @@
+//go:build !linux
+
+package actions
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// AddStaticNeighbor is Linux-only.
+func AddStaticNeighbor(input, dev string, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// FlushStaleNeighbors is Linux-only.
+func FlushStaleNeighbors(ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
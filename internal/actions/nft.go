@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// nftHandleRe matches a rule line of nft -a list output, like
+// "ip saddr 203.0.113.5 drop # handle 4".
+var nftHandleRe = regexp.MustCompile(`^(.*\S)\s+# handle (\d+)$`)
+
+// nftables manages rules in the "inet nettui" table, whose input and
+// output chains run just before the default filter priority. An accept
+// there ends evaluation only within the table, so allow rules do not
+// override drops in other tables.
+type nftables struct{}
+
+// nftTable declares the managed table and chains, leaving them as they
+// are if they exist.
+const nftTable = `table inet ` + ManagedName + ` {
+	chain input {
+		type filter hook input priority -10; policy accept;
+	}
+	chain output {
+		type filter hook output priority -10; policy accept;
+	}
+}
+`
+
+func (nftables) Name() string { return "nftables" }
+
+func (nftables) Rule(addr net.IP, allow bool, ns data.Namespace) Action {
+	family, verdict := "ip", "drop"
+	if addr.To4() == nil {
+		family = "ip6"
+	}
+	if allow {
+		verdict = "accept"
+	}
+	script := nftTable +
+		fmt.Sprintf("add rule inet %s input %s saddr %s %s\n", ManagedName, family, addr, verdict) +
+		fmt.Sprintf("add rule inet %s output %s daddr %s %s\n", ManagedName, family, addr, verdict)
+	a := Action{
+		Title:     ruleTitle(addr, allow),
+		Details:   []string{"Added to nftables table inet " + ManagedName},
+		Preview:   append(strings.Split(strings.TrimSuffix(script, "\n"), "\n"), "", "loaded with: nft -f -"),
+		Namespace: ns,
+	}
+	if allow {
+		a.Details = append(a.Details, "Allow cannot override drops in other tables, such as the distribution's firewall")
+	}
+	a.apply = func() error {
+		_, err := run(script, "nft", "-f", "-")
+		return err
+	}
+	a.check = func() error {
+		_, err := run(script, "nft", "-c", "-f", "-")
+		return err
+	}
+	return a
+}
+
+func (n nftables) List(ns data.Namespace) ([]ManagedRule, error) {
+	var out string
+	err := inNamespace(ns, func() error {
+		var err error
+		out, err = run("", "nft", "-a", "list", "table", "inet", ManagedName)
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "No such file or directory") {
+			return nil, nil // no table yet: nothing added
+		}
+		return nil, err
+	}
+
+	var rules []ManagedRule
+	chain := ""
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if f := strings.Fields(line); len(f) >= 2 && f[0] == "chain" {
+			chain = f[1]
+			continue
+		}
+		m := nftHandleRe.FindStringSubmatch(line)
+		if m == nil || chain == "" || strings.HasPrefix(line, "table ") {
+			continue
+		}
+		args := []string{"delete", "rule", "inet", ManagedName, chain, "handle", m[2]}
+		rule := chain + ": " + m[1]
+		rules = append(rules, ManagedRule{
+			Rule: rule,
+			Remove: Action{
+				Title:     "Remove nftables rule",
+				Details:   []string{"From table inet " + ManagedName},
+				Preview:   []string{rule, "", "removed with: " + commandLine("nft", args...)},
+				Namespace: ns,
+				apply: func() error {
+					_, err := run("", "nft", args...)
+					return err
+				},
+			},
+		})
+	}
+	return rules, nil
+}
@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// pf manages rules in a pf anchor. pfctl replaces an anchor's rules as a
+// whole, so each change reloads the anchor with a rule added or removed.
+type pf struct {
+	anchor string
+	called bool // the main ruleset evaluates the anchor
+}
+
+// newPF picks the anchor: "com.apple/nettui" where the main ruleset
+// evaluates every com.apple child anchor, as macOS's pf.conf does, and
+// "nettui" otherwise.
+func newPF(rules []data.FirewallRule) pf {
+	p := pf{anchor: ManagedName}
+	for _, r := range rules {
+		if r.Anchor != "" || r.Action != "anchor" {
+			continue
+		}
+		switch r.AnchorCall {
+		case "com.apple/*":
+			return pf{anchor: "com.apple/" + ManagedName, called: true}
+		case ManagedName, ManagedName + "/*":
+			p.called = true
+		}
+	}
+	return p
+}
+
+func (p pf) Name() string { return "pf" }
+
+func (p pf) Rule(addr net.IP, allow bool, ns data.Namespace) Action {
+	verb := "block drop quick"
+	if allow {
+		verb = "pass quick"
+	}
+	rules := []string{
+		fmt.Sprintf("%s from %s to any", verb, addr),
+		fmt.Sprintf("%s from any to %s", verb, addr),
+	}
+	a := Action{
+		Title:     ruleTitle(addr, allow),
+		Details:   []string{"Loaded into pf anchor " + p.anchor},
+		Preview:   append(rules, "", "loaded with: "+commandLine("pfctl", "-a", p.anchor, "-f", "-")),
+		Namespace: ns,
+	}
+	if !p.called {
+		a.Details = append(a.Details, fmt.Sprintf("The main ruleset does not evaluate this anchor; add `anchor %q` to pf.conf for the rules to take effect", p.anchor))
+	}
+	a.apply = func() error { return p.load(false, func(cur []string) []string { return append(cur, rules...) }) }
+	a.check = func() error { return p.load(true, func(cur []string) []string { return append(cur, rules...) }) }
+	return a
+}
+
+func (p pf) List(ns data.Namespace) ([]ManagedRule, error) {
+	var rules []string
+	err := inNamespace(ns, func() error {
+		var err error
+		rules, err = p.rules()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return managedList(rules, func(rule string) Action {
+		a := Action{
+			Title:     "Remove pf rule",
+			Details:   []string{"From pf anchor " + p.anchor},
+			Preview:   []string{rule, "", "reloaded with: " + commandLine("pfctl", "-a", p.anchor, "-f", "-")},
+			Namespace: ns,
+		}
+		without := func(cur []string) []string {
+			kept := cur[:0]
+			for _, r := range cur {
+				if r != rule {
+					kept = append(kept, r)
+				}
+			}
+			return kept
+		}
+		a.apply = func() error { return p.load(false, without) }
+		a.check = func() error { return p.load(true, without) }
+		return a
+	}), nil
+}
+
+// rules lists the anchor's rules as pfctl prints them.
+func (p pf) rules() ([]string, error) {
+	out, err := run("", "pfctl", "-a", p.anchor, "-sr")
+	if err != nil {
+		return nil, err
+	}
+	var rules []string
+	for _, line := range strings.Split(out, "\n") {
+		if r := strings.TrimSpace(line); r != "" {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// load replaces the anchor's rules with edit applied to the current ones,
+// or with check set only parses them.
+func (p pf) load(check bool, edit func([]string) []string) error {
+	cur, err := p.rules()
+	if err != nil {
+		return err
+	}
+	args := []string{"-a", p.anchor, "-f", "-"}
+	if check {
+		args = append([]string{"-n"}, args...)
+	}
+	rules := edit(cur)
+	if len(rules) == 0 {
+		if check {
+			return nil
+		}
+		_, err := run("", "pfctl", "-a", p.anchor, "-F", "rules")
+		return err
+	}
+	_, err = run(strings.Join(rules, "\n")+"\n", "pfctl", args...)
+	return err
+}
@@ -0,0 +1,427 @@
+//go:build linux
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// AddRoute returns the action adding a route typed in as `ip route add`
+// takes it, like "10.20.0.0/16 via 192.168.1.1 dev eth0 metric 100". Its
+// revert deletes the route again.
+func AddRoute(input string, ns data.Namespace) (Action, error) {
+	r, err := parseRoute(input, ns)
+	if err != nil {
+		return Action{}, err
+	}
+	a := routeAction("add", r, ns)
+	revert := routeAction("del", r, ns)
+	a.Revert = &revert
+	return a, nil
+}
+
+// DeleteRoute returns the action deleting route from the main table, with
+// the metric, protocol and source it was installed with. Its revert adds
+// it back as it was.
+func DeleteRoute(route data.Route, ns data.Namespace) (Action, error) {
+	var found *netlink.Route
+	err := inNamespace(ns, func() error {
+		var err error
+		found, err = findRoute(route)
+		return err
+	})
+	if err != nil {
+		return Action{}, err
+	}
+	*found = restorable(*found)
+	a := routeAction("del", *found, ns)
+	if found.Protocol == unix.RTPROT_KERNEL {
+		a.Details = append(a.Details, "Added by the kernel for an address; removing the address removes it for good")
+	}
+	if isDefaultRoute(found.Dst) {
+		a.Details = append(a.Details, "Traffic to destinations without a more specific route stops")
+	}
+	a.check = func() error {
+		if _, err := findRoute(route); err != nil {
+			return err
+		}
+		return needNetAdmin()
+	}
+	revert := routeAction("add", *found, ns)
+	a.Revert = &revert
+	return a, nil
+}
+
+// ChangeGateway returns the action pointing a default route at a new
+// gateway, typed in as "192.168.1.254" or "192.168.1.254 dev eth1". The
+// selected route is replaced if it is a default route of the gateway's
+// address family; otherwise the one of that family with the lowest metric
+// is. The replacement keeps the route's metric; its revert restores it.
+func ChangeGateway(input string, selected data.Route, ns data.Namespace) (Action, error) {
+	fields := strings.Fields(input)
+	if len(fields) != 1 && (len(fields) != 3 || fields[1] != "dev") {
+		return Action{}, fmt.Errorf("gateway must be an address, optionally followed by dev and an interface, not %q", input)
+	}
+	gw := net.ParseIP(fields[0])
+	if gw == nil {
+		return Action{}, fmt.Errorf("%q is not an IP address", fields[0])
+	}
+	r := netlink.Route{Family: ipFamily(gw), Gw: gw}
+
+	var old *netlink.Route
+	err := inNamespace(ns, func() error {
+		if len(fields) == 3 {
+			link, err := netlink.LinkByName(fields[2])
+			if err != nil {
+				return fmt.Errorf("interface %s: %w", fields[2], err)
+			}
+			r.LinkIndex = link.Attrs().Index
+		}
+		if selected.IsDefault() {
+			found, err := findRoute(selected)
+			if err != nil {
+				return err
+			}
+			if routeFamily(*found) == r.Family {
+				old = found
+				return nil
+			}
+		}
+		routes, err := netlink.RouteList(nil, r.Family)
+		if err != nil {
+			return rtnlError("RouteList", err)
+		}
+		for i, rt := range routes {
+			if isDefaultRoute(rt.Dst) && (old == nil || rt.Priority < old.Priority) {
+				old = &routes[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Action{}, err
+	}
+
+	if old == nil {
+		a := routeAction("add", r, ns)
+		a.Details = []string{"There is no default route yet"}
+		a.check = gatewayCheck(gw, r.LinkIndex)
+		revert := routeAction("del", r, ns)
+		a.Revert = &revert
+		return a, nil
+	}
+	*old = restorable(*old)
+	r.Priority, r.Table = old.Priority, old.Table
+	a := routeAction("replace", r, ns)
+	if old.Gw != nil {
+		a.Details = []string{"Currently via " + old.Gw.String()}
+	}
+	a.check = gatewayCheck(gw, r.LinkIndex)
+	revert := routeAction("replace", *old, ns)
+	a.Revert = &revert
+	return a, nil
+}
+
+// routeAction adds, deletes or replaces r. Link indexes are those of ns.
+func routeAction(verb string, r netlink.Route, ns data.Namespace) Action {
+	args := routeArgs(r, ns)
+	title := fmt.Sprintf("Add route %s", args[0])
+	switch verb {
+	case "del":
+		title = fmt.Sprintf("Delete route %s", args[0])
+	case "replace":
+		title = fmt.Sprintf("Replace route %s", args[0])
+	}
+	if r.Gw != nil {
+		title += " via " + r.Gw.String()
+	}
+	ip := []string{"route", verb}
+	if isDefaultRoute(r.Dst) && routeFamily(r) == netlink.FAMILY_V6 {
+		// ip cannot tell the family of "default" from the destination.
+		ip = []string{"-6", "route", verb}
+	}
+	return Action{
+		Title:     title,
+		Preview:   []string{commandLine("ip", append(ip, args...)...)},
+		Namespace: ns,
+		apply: func() error {
+			switch verb {
+			case "del":
+				return rtnlError("RouteDel", netlink.RouteDel(&r))
+			case "replace":
+				return rtnlError("RouteReplace", netlink.RouteReplace(&r))
+			}
+			return rtnlError("RouteAdd", netlink.RouteAdd(&r))
+		},
+		check: needNetAdmin,
+	}
+}
+
+// routeArgs renders r as `ip route` arguments, destination first.
+func routeArgs(r netlink.Route, ns data.Namespace) []string {
+	dst := "default"
+	if !isDefaultRoute(r.Dst) {
+		dst = r.Dst.String()
+	}
+	args := []string{dst}
+	if r.Gw != nil {
+		args = append(args, "via", r.Gw.String())
+	}
+	if r.LinkIndex > 0 {
+		args = append(args, "dev", linkName(r.LinkIndex, ns))
+	}
+	for _, hop := range r.MultiPath {
+		args = append(args, "nexthop")
+		if hop.Gw != nil {
+			args = append(args, "via", hop.Gw.String())
+		}
+		if hop.LinkIndex > 0 {
+			args = append(args, "dev", linkName(hop.LinkIndex, ns))
+		}
+	}
+	if r.Flags&unix.RTNH_F_ONLINK != 0 {
+		args = append(args, "onlink")
+	}
+	if r.Protocol != 0 && r.Protocol != unix.RTPROT_BOOT {
+		args = append(args, "proto", r.Protocol.String())
+	}
+	if r.Scope != netlink.SCOPE_UNIVERSE {
+		args = append(args, "scope", r.Scope.String())
+	}
+	if r.Src != nil {
+		args = append(args, "src", r.Src.String())
+	}
+	if r.Priority > 0 {
+		args = append(args, "metric", strconv.Itoa(r.Priority))
+	}
+	if r.Table != 0 && r.Table != unix.RT_TABLE_MAIN {
+		args = append(args, "table", strconv.Itoa(r.Table))
+	}
+	return args
+}
+
+// parseRoute parses a route typed in as `ip route add` takes it: a
+// destination, then any of via, dev and metric.
+func parseRoute(input string, ns data.Namespace) (netlink.Route, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields)%2 == 0 {
+		return netlink.Route{}, fmt.Errorf("route must be a destination followed by via, dev or metric, like 10.20.0.0/16 via 192.168.1.1, not %q", input)
+	}
+	var r netlink.Route
+	if fields[0] != "default" {
+		dst := fields[0]
+		if !strings.Contains(dst, "/") {
+			if ip := net.ParseIP(dst); ip != nil && ip.To4() != nil {
+				dst += "/32"
+			} else {
+				dst += "/128"
+			}
+		}
+		_, ipnet, err := net.ParseCIDR(dst)
+		if err != nil {
+			return netlink.Route{}, fmt.Errorf("destination must be default or an address with a prefix length, like 10.20.0.0/16, not %q", fields[0])
+		}
+		r.Dst = ipnet
+		r.Family = ipFamily(ipnet.IP)
+	}
+	var dev string
+	for i := 1; i < len(fields); i += 2 {
+		switch val := fields[i+1]; fields[i] {
+		case "via":
+			r.Gw = net.ParseIP(val)
+			if r.Gw == nil {
+				return netlink.Route{}, fmt.Errorf("gateway %q is not an IP address", val)
+			}
+		case "dev":
+			dev = val
+		case "metric":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				return netlink.Route{}, fmt.Errorf("metric %q is not a number", val)
+			}
+			r.Priority = n
+		default:
+			return netlink.Route{}, fmt.Errorf("unknown keyword %q; use via, dev or metric", fields[i])
+		}
+	}
+	switch {
+	case r.Gw == nil && dev == "":
+		return netlink.Route{}, errors.New("route needs a gateway (via) or an interface (dev)")
+	case r.Dst == nil:
+		r.Family = netlink.FAMILY_V4
+		if r.Gw != nil {
+			r.Family = ipFamily(r.Gw)
+		}
+	case r.Gw != nil && ipFamily(r.Gw) != r.Family:
+		return netlink.Route{}, errors.New("gateway and destination must be of the same address family")
+	}
+	if r.Gw == nil {
+		// As ip does for routes through an interface only.
+		r.Scope = netlink.SCOPE_LINK
+	}
+	if dev != "" {
+		err := inNamespace(ns, func() error {
+			link, err := netlink.LinkByName(dev)
+			if err != nil {
+				return fmt.Errorf("interface %s: %w", dev, err)
+			}
+			r.LinkIndex = link.Attrs().Index
+			return nil
+		})
+		if err != nil {
+			return netlink.Route{}, err
+		}
+	}
+	return r, nil
+}
+
+// findRoute looks up the main table route listed as route, as the
+// collector reads it, in the namespace of the calling thread.
+func findRoute(route data.Route) (*netlink.Route, error) {
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, rtnlError("RouteList", err)
+	}
+	for i, r := range routes {
+		gw, index := r.Gw, r.LinkIndex
+		if len(r.MultiPath) > 0 {
+			gw, index = r.MultiPath[0].Gw, r.MultiPath[0].LinkIndex
+		}
+		dst, mask := "default", ""
+		if !isDefaultRoute(r.Dst) {
+			dst, mask = r.Dst.IP.String(), net.IP(r.Dst.Mask).String()
+		}
+		if dst != route.Destination || mask != route.Netmask || gw.String() != ipOrNil(route.Gateway) || r.Priority != route.Metric {
+			continue
+		}
+		if route.Interface != "" {
+			link, err := netlink.LinkByIndex(index)
+			if err != nil || link.Attrs().Name != route.Interface {
+				continue
+			}
+		}
+		return &routes[i], nil
+	}
+	return nil, fmt.Errorf("route to %s is gone", route.Destination)
+}
+
+// restorable returns r as listed, without the next hop flags the kernel
+// reports but refuses when the route is added back.
+func restorable(r netlink.Route) netlink.Route {
+	r.Flags &= unix.RTNH_F_ONLINK
+	hops := make([]*netlink.NexthopInfo, len(r.MultiPath))
+	for i, hop := range r.MultiPath {
+		h := *hop
+		h.Flags &= unix.RTNH_F_ONLINK
+		hops[i] = &h
+	}
+	if len(hops) > 0 {
+		r.MultiPath = hops
+	}
+	return r
+}
+
+// gatewayCheck checks that nettui may change routes and that gw is on a
+// network directly connected to one of the interfaces, or to the one with
+// linkIndex if not 0, as the kernel requires of a gateway.
+func gatewayCheck(gw net.IP, linkIndex int) func() error {
+	return func() error {
+		if linkIndex > 0 {
+			// A route lookup restricted to the interface would assume any
+			// address is on link, so look for a connected route covering gw.
+			routes, err := netlink.RouteListFiltered(ipFamily(gw), &netlink.Route{LinkIndex: linkIndex}, netlink.RT_FILTER_OIF)
+			if err != nil {
+				return rtnlError("RouteList", err)
+			}
+			for _, r := range routes {
+				if r.Gw == nil && r.Dst != nil && r.Dst.Contains(gw) {
+					return needNetAdmin()
+				}
+			}
+			name := strconv.Itoa(linkIndex)
+			if link, err := netlink.LinkByIndex(linkIndex); err == nil {
+				name = link.Attrs().Name
+			}
+			return fmt.Errorf("gateway %s is not on a network directly connected to %s", gw, name)
+		}
+		routes, err := netlink.RouteGet(gw)
+		if err != nil {
+			return rtnlError("RouteGet", err)
+		}
+		if len(routes) == 0 || routes[0].Gw != nil {
+			return fmt.Errorf("gateway %s is not on a directly connected network", gw)
+		}
+		return needNetAdmin()
+	}
+}
+
+// needNetAdmin fails unless nettui holds CAP_NET_ADMIN.
+func needNetAdmin() error {
+	if !hasCapability(capNetAdmin) {
+		return errors.New("needs CAP_NET_ADMIN; run nettui as root")
+	}
+	return nil
+}
+
+// isDefaultRoute reports whether dst, as rtnetlink reports it, is the
+// default route.
+func isDefaultRoute(dst *net.IPNet) bool {
+	if dst == nil {
+		return true
+	}
+	ones, _ := dst.Mask.Size()
+	return ones == 0
+}
+
+// routeFamily returns the address family of r, from its destination or
+// gateway where set.
+func routeFamily(r netlink.Route) int {
+	switch {
+	case r.Dst != nil:
+		return ipFamily(r.Dst.IP)
+	case r.Gw != nil:
+		return ipFamily(r.Gw)
+	case len(r.MultiPath) > 0 && r.MultiPath[0].Gw != nil:
+		return ipFamily(r.MultiPath[0].Gw)
+	}
+	return r.Family
+}
+
+// ipFamily returns the netlink family of ip.
+func ipFamily(ip net.IP) int {
+	if ip.To4() != nil {
+		return netlink.FAMILY_V4
+	}
+	return netlink.FAMILY_V6
+}
+
+// ipOrNil normalizes a listed address for comparison with net.IP's
+// String, which renders a missing address as "<nil>".
+func ipOrNil(s string) string {
+	if s == "" {
+		return "<nil>"
+	}
+	return s
+}
+
+// linkName returns the name of the interface with index in ns, or the
+// index if it cannot be found.
+func linkName(index int, ns data.Namespace) string {
+	name := strconv.Itoa(index)
+	_ = inNamespace(ns, func() error {
+		link, err := netlink.LinkByIndex(index)
+		if err == nil {
+			name = link.Attrs().Name
+		}
+		return err
+	})
+	return name
+}
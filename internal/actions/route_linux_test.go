@@ -0,0 +1,204 @@
+package actions
+
+import (
+	"net"
+	"os/exec"
+	"testing"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+)
+
+// TestRouteAndNeighborChanges applies each route and neighbor action in a
+// scratch namespace with a veth interface on 10.9.8.1/24, then its revert,
+// checking the tables after each.
+func TestRouteAndNeighborChanges(t *testing.T) {
+	ns := scratchNamespace(t)
+	for _, args := range [][]string{
+		{"link", "add", "v0", "type", "veth", "peer", "name", "v1"},
+		{"addr", "add", "10.9.8.1/24", "dev", "v0"},
+		{"link", "set", "v0", "up"},
+	} {
+		if out, err := exec.Command("ip", append([]string{"-n", ns.Name}, args...)...).CombinedOutput(); err != nil {
+			t.Skipf("ip %v: %v: %s", args, err, out)
+		}
+	}
+	apply := func(a Action, err error) Action {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if err := a.DryRun(); err != nil {
+			t.Fatalf("dry run %s: %v", a.Title, err)
+		}
+		if err := a.Apply(); err != nil {
+			t.Fatalf("apply %s: %v", a.Title, err)
+		}
+		return a
+	}
+	revert := func(a Action) {
+		t.Helper()
+		if a.Revert == nil {
+			t.Fatalf("%s has no revert", a.Title)
+		}
+		if err := a.Revert.Apply(); err != nil {
+			t.Fatalf("revert %s: %v", a.Title, err)
+		}
+	}
+	gateway := func(dst string) string {
+		t.Helper()
+		var gw string
+		_ = inNamespace(ns, func() error {
+			routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+			for _, r := range routes {
+				if (dst == "default" && isDefaultRoute(r.Dst)) || (r.Dst != nil && r.Dst.String() == dst) {
+					gw = r.Gw.String()
+				}
+			}
+			return err
+		})
+		return gw
+	}
+	metrics := func(dst string) []int {
+		t.Helper()
+		var prios []int
+		_ = inNamespace(ns, func() error {
+			routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+			for _, r := range routes {
+				if r.Dst != nil && r.Dst.String() == dst {
+					prios = append(prios, r.Priority)
+				}
+			}
+			return err
+		})
+		return prios
+	}
+	neighbor := func(ip string) (mac string, state int) {
+		t.Helper()
+		_ = inNamespace(ns, func() error {
+			neighs, err := netlink.NeighList(0, netlink.FAMILY_V4)
+			for _, n := range neighs {
+				if n.IP.String() == ip {
+					mac, state = n.HardwareAddr.String(), n.State
+				}
+			}
+			return err
+		})
+		return mac, state
+	}
+
+	a := apply(AddRoute("10.20.0.0/16 via 10.9.8.2 dev v0 metric 5", ns))
+	if want := "ip route add 10.20.0.0/16 via 10.9.8.2 dev v0 metric 5"; a.Preview[0] != want {
+		t.Errorf("preview %q, want %q", a.Preview[0], want)
+	}
+	if gw := gateway("10.20.0.0/16"); gw != "10.9.8.2" {
+		t.Errorf("%s: gateway %s", a.Title, gw)
+	}
+	revert(a)
+	if gw := gateway("10.20.0.0/16"); gw != "" {
+		t.Errorf("revert %s: route still via %s", a.Title, gw)
+	}
+	if _, err := AddRoute("10.20.0.0/16 via fd00::1", ns); err == nil {
+		t.Error("gateway of another family accepted")
+	}
+
+	apply(AddRoute("10.30.0.0/16 via 10.9.8.2", ns))
+	a = apply(DeleteRoute(data.Route{Destination: "10.30.0.0", Netmask: "255.255.0.0", Gateway: "10.9.8.2", Interface: "v0"}, ns))
+	if gw := gateway("10.30.0.0/16"); gw != "" {
+		t.Errorf("%s: route still via %s", a.Title, gw)
+	}
+	revert(a)
+	if gw := gateway("10.30.0.0/16"); gw != "10.9.8.2" {
+		t.Errorf("revert %s: gateway %q", a.Title, gw)
+	}
+
+	apply(AddRoute("10.40.0.0/16 via 10.9.8.2 metric 10", ns))
+	apply(AddRoute("10.40.0.0/16 via 10.9.8.2 metric 20", ns))
+	a = apply(DeleteRoute(data.Route{Destination: "10.40.0.0", Netmask: "255.255.0.0", Gateway: "10.9.8.2", Interface: "v0", Metric: 20}, ns))
+	if got := metrics("10.40.0.0/16"); len(got) != 1 || got[0] != 10 {
+		t.Errorf("%s: metrics left %v, want [10]", a.Title, got)
+	}
+	revert(a)
+	if got := metrics("10.40.0.0/16"); len(got) != 2 {
+		t.Errorf("revert %s: metrics %v, want [10 20]", a.Title, got)
+	}
+
+	apply(AddRoute("default via 10.9.8.2", ns))
+	a = apply(ChangeGateway("10.9.8.3", data.Route{}, ns))
+	if gw := gateway("default"); gw != "10.9.8.3" {
+		t.Errorf("%s: gateway %s", a.Title, gw)
+	}
+	revert(a)
+	if gw := gateway("default"); gw != "10.9.8.2" {
+		t.Errorf("revert %s: gateway %s", a.Title, gw)
+	}
+
+	// A selected default route is replaced rather than the lowest-metric one.
+	apply(AddRoute("default via 10.9.8.2 metric 50", ns))
+	a = apply(ChangeGateway("10.9.8.4", data.Route{Destination: "default", Gateway: "10.9.8.2", Interface: "v0", Metric: 50}, ns))
+	defaults := func() map[int]string {
+		t.Helper()
+		gws := make(map[int]string)
+		_ = inNamespace(ns, func() error {
+			routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+			for _, r := range routes {
+				if isDefaultRoute(r.Dst) {
+					gws[r.Priority] = r.Gw.String()
+				}
+			}
+			return err
+		})
+		return gws
+	}
+	if got := defaults(); got[0] != "10.9.8.2" || got[50] != "10.9.8.4" {
+		t.Errorf("%s: default gateways by metric %v", a.Title, got)
+	}
+	revert(a)
+	if got := defaults(); got[0] != "10.9.8.2" || got[50] != "10.9.8.2" {
+		t.Errorf("revert %s: default gateways by metric %v", a.Title, got)
+	}
+
+	if a, err := ChangeGateway("192.0.2.1", data.Route{}, ns); err != nil || a.DryRun() == nil {
+		t.Errorf("dry run passed for an unreachable gateway (build error %v)", err)
+	}
+	if a, err := ChangeGateway("10.9.8.3 dev v1", data.Route{}, ns); err != nil || a.DryRun() == nil {
+		t.Errorf("dry run passed for a gateway not connected to v1 (build error %v)", err)
+	}
+	if a, err := ChangeGateway("10.9.8.3 dev v0", data.Route{}, ns); err != nil || a.DryRun() != nil {
+		t.Errorf("dry run failed for a gateway connected to v0 (build error %v)", err)
+	}
+
+	v6Default := netlink.Route{Dst: &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}, Gw: net.ParseIP("fd00::1")}
+	if got, want := routeAction("add", v6Default, ns).Preview[0], "ip -6 route add default via fd00::1"; got != want {
+		t.Errorf("preview %q, want %q", got, want)
+	}
+
+	a = apply(AddStaticNeighbor("10.9.8.9 02:00:00:00:00:09", "v0", ns))
+	if mac, state := neighbor("10.9.8.9"); mac != "02:00:00:00:00:09" || state != netlink.NUD_PERMANENT {
+		t.Errorf("%s: %s in state %#x", a.Title, mac, state)
+	}
+	revert(a)
+	if mac, _ := neighbor("10.9.8.9"); mac != "" {
+		t.Errorf("revert %s: entry at %s remains", a.Title, mac)
+	}
+
+	stale := netlink.Neigh{IP: net.ParseIP("10.9.8.7"), HardwareAddr: net.HardwareAddr{2, 0, 0, 0, 0, 7}, State: netlink.NUD_STALE, Family: netlink.FAMILY_V4}
+	if err := inNamespace(ns, func() error {
+		link, err := netlink.LinkByName("v0")
+		if err != nil {
+			return err
+		}
+		stale.LinkIndex = link.Attrs().Index
+		return netlink.NeighSet(&stale)
+	}); err != nil {
+		t.Fatalf("add stale entry: %v", err)
+	}
+	a = apply(FlushStaleNeighbors(ns))
+	if mac, _ := neighbor("10.9.8.7"); mac != "" {
+		t.Errorf("%s: entry at %s remains", a.Title, mac)
+	}
+	revert(a)
+	if mac, state := neighbor("10.9.8.7"); mac != "02:00:00:00:00:07" || state&netlink.NUD_STALE == 0 {
+		t.Errorf("revert %s: %q in state %#x", a.Title, mac, state)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package actions
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// AddRoute is Linux-only.
+func AddRoute(input string, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// DeleteRoute is Linux-only.
+func DeleteRoute(route data.Route, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
+
+// ChangeGateway is Linux-only.
+func ChangeGateway(input string, selected data.Route, ns data.Namespace) (Action, error) {
+	return Action{}, errNoRtnetlink
+}
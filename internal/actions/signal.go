@@ -0,0 +1,111 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/unix"
+)
+
+// maxListedSockets bounds the sockets a signal action lists as affected.
+const maxListedSockets = 10
+
+// Signal is a signal offered for sending to a process.
+type Signal struct {
+	Sig  syscall.Signal
+	Desc string
+}
+
+// Name returns the signal's name, like "SIGTERM".
+func (s Signal) Name() string {
+	return unix.SignalName(s.Sig)
+}
+
+// Signals lists the signals offered, most common first.
+var Signals = []Signal{
+	{unix.SIGTERM, "ask the process to exit"},
+	{unix.SIGKILL, "kill the process immediately"},
+	{unix.SIGINT, "interrupt, as Ctrl+C does"},
+	{unix.SIGHUP, "hang up; many daemons reload their configuration"},
+	{unix.SIGQUIT, "quit, dumping core where enabled"},
+	{unix.SIGUSR1, "user-defined signal 1"},
+	{unix.SIGUSR2, "user-defined signal 2"},
+	{unix.SIGSTOP, "pause the process"},
+	{unix.SIGCONT, "resume a paused process"},
+}
+
+// SignalProcess returns the action sending sig to proc, listing the
+// sockets it owns as affected. The dry run checks that the signal may be
+// sent without sending it.
+func SignalProcess(proc data.Process, sig Signal, sockets []data.Socket) Action {
+	short := strings.TrimPrefix(sig.Name(), "SIG")
+	a := Action{
+		Title:   fmt.Sprintf("Send %s to PID %d (%s)", sig.Name(), proc.PID, proc.Name),
+		Preview: []string{commandLine("kill", "-"+short, fmt.Sprintf("%d", proc.PID))},
+	}
+	if proc.Command != "" {
+		a.Details = append(a.Details, "Command: "+proc.Command)
+	}
+	if proc.User != "" {
+		a.Details = append(a.Details, "User: "+proc.User)
+	}
+	switch {
+	case len(sockets) == 0:
+		a.Details = append(a.Details, "Owns no TCP or UDP sockets")
+	default:
+		a.Details = append(a.Details, fmt.Sprintf("Sockets affected (%d):", len(sockets)))
+		for i, s := range sockets {
+			if i == maxListedSockets {
+				a.Details = append(a.Details, fmt.Sprintf("  … and %d more", len(sockets)-i))
+				break
+			}
+			a.Details = append(a.Details, strings.TrimSpace("  "+s.Tuple()+" "+s.State))
+		}
+	}
+
+	send := func(sig syscall.Signal) error {
+		if err := sameProcess(proc); err != nil {
+			return err
+		}
+		if err := unix.Kill(int(proc.PID), sig); err != nil {
+			return signalError(a.Preview[0], err)
+		}
+		return nil
+	}
+	a.apply = func() error { return send(sig.Sig) }
+	// Signal 0 checks the process exists and may be signalled.
+	a.check = func() error { return send(0) }
+	return a
+}
+
+// sameProcess checks that proc's PID still belongs to the process seen at
+// the last refresh, not one that reused it since.
+func sameProcess(proc data.Process) error {
+	if proc.StartTime.IsZero() {
+		return nil
+	}
+	p, err := process.NewProcess(proc.PID)
+	if err != nil {
+		return fmt.Errorf("PID %d has exited", proc.PID)
+	}
+	created, err := p.CreateTime()
+	if err == nil && created != proc.StartTime.UnixMilli() {
+		return fmt.Errorf("PID %d has exited and been reused by another process", proc.PID)
+	}
+	return nil
+}
+
+// signalError explains why kill failed.
+func signalError(cmd string, err error) error {
+	switch {
+	case errors.Is(err, unix.EPERM):
+		return fmt.Errorf("%s: %w (the process belongs to another user; run nettui as root)", cmd, err)
+	case errors.Is(err, unix.ESRCH):
+		return fmt.Errorf("%s: %w (the process has exited)", cmd, err)
+	}
+	return fmt.Errorf("%s: %w", cmd, err)
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package actions
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// capNetAdmin is the bit of CAP_NET_ADMIN in the capability sets.
+const capNetAdmin = 12
+
+// DestroySocket returns the action tearing down a TCP socket with the
+// sock_diag SOCK_DESTROY operation, as `ss -K` does: its peer gets a
+// reset and its owner an error on its next call, but the process keeps
+// running. The dry run looks the socket up and checks for CAP_NET_ADMIN.
+func DestroySocket(sock data.Socket, ns data.Namespace) (Action, error) {
+	if !strings.HasPrefix(sock.Proto, "tcp") {
+		return Action{}, fmt.Errorf("only TCP sockets can be closed, not %s", sock.Proto)
+	}
+	req, err := inetDiagReq(sock)
+	if err != nil {
+		return Action{}, err
+	}
+	a := Action{
+		Title:     "Close TCP socket " + sock.Tuple(),
+		Preview:   []string{fmt.Sprintf("ss -K %s", ssFilter(sock))},
+		Namespace: ns,
+	}
+	if sock.State != "" {
+		a.Details = append(a.Details, "State: "+sock.State)
+	}
+	if sock.PID > 0 {
+		a.Details = append(a.Details, fmt.Sprintf("Owner: PID %d (%s), which keeps running", sock.PID, sock.Process))
+	}
+	a.Details = append(a.Details, "The peer receives a reset")
+	a.apply = func() error {
+		r := nl.NewNetlinkRequest(nl.SOCK_DESTROY, unix.NLM_F_ACK)
+		r.AddRawData(req)
+		if _, err := r.Execute(unix.NETLINK_INET_DIAG, 0); err != nil {
+			return destroyError(err)
+		}
+		return nil
+	}
+	a.check = func() error {
+		r := nl.NewNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, 0)
+		r.AddRawData(req)
+		if _, err := r.Execute(unix.NETLINK_INET_DIAG, nl.SOCK_DIAG_BY_FAMILY); err != nil {
+			return destroyError(err)
+		}
+		if !hasCapability(capNetAdmin) {
+			return destroyError(unix.EPERM)
+		}
+		return nil
+	}
+	return a, nil
+}
+
+// inetDiagReq encodes a struct inet_diag_req_v2 naming the socket.
+func inetDiagReq(sock data.Socket) ([]byte, error) {
+	local := net.ParseIP(strings.SplitN(sock.LocalAddr, "%", 2)[0])
+	remote := net.ParseIP(strings.SplitN(sock.RemoteAddr, "%", 2)[0])
+	if local == nil {
+		return nil, fmt.Errorf("bad local address %q", sock.LocalAddr)
+	}
+	if remote == nil {
+		remote = net.IPv4zero
+		if local.To4() == nil {
+			remote = net.IPv6zero
+		}
+	}
+	family := byte(unix.AF_INET6)
+	src, dst := local.To16(), remote.To16()
+	if sock.Proto == "tcp" {
+		family = unix.AF_INET
+		src, dst = local.To4(), remote.To4()
+		if src == nil || dst == nil {
+			return nil, fmt.Errorf("bad IPv4 addresses %s, %s", sock.LocalAddr, sock.RemoteAddr)
+		}
+	}
+
+	b := make([]byte, 56)
+	b[0] = family
+	b[1] = unix.IPPROTO_TCP
+	binary.NativeEndian.PutUint32(b[4:], 0xffffffff) // all states
+	binary.BigEndian.PutUint16(b[8:], uint16(sock.LocalPort))
+	binary.BigEndian.PutUint16(b[10:], uint16(sock.RemotePort))
+	copy(b[12:28], src)
+	copy(b[28:44], dst)
+	binary.NativeEndian.PutUint32(b[48:], nl.TCPDIAG_NOCOOKIE)
+	binary.NativeEndian.PutUint32(b[52:], nl.TCPDIAG_NOCOOKIE)
+	return b, nil
+}
+
+// ssFilter renders an ss filter selecting the socket.
+func ssFilter(sock data.Socket) string {
+	family := "-4"
+	if sock.Proto == "tcp6" {
+		family = "-6"
+	}
+	f := fmt.Sprintf("%s -t src %s", family, ssEndpoint(sock.LocalAddr, sock.LocalPort))
+	if sock.RemotePort != 0 {
+		f += " dst " + ssEndpoint(sock.RemoteAddr, sock.RemotePort)
+	}
+	return f
+}
+
+func ssEndpoint(addr string, port uint32) string {
+	if strings.Contains(addr, ":") {
+		return fmt.Sprintf("[%s]:%d", addr, port)
+	}
+	return fmt.Sprintf("%s:%d", addr, port)
+}
+
+// destroyError explains the errors SOCK_DESTROY and lookups fail with.
+func destroyError(err error) error {
+	switch {
+	case errors.Is(err, unix.EOPNOTSUPP):
+		return fmt.Errorf("SOCK_DESTROY: %w (the kernel was built without CONFIG_INET_DIAG_DESTROY)", err)
+	case errors.Is(err, unix.EPERM), errors.Is(err, unix.EACCES):
+		return fmt.Errorf("SOCK_DESTROY: %w (needs CAP_NET_ADMIN; run nettui as root)", err)
+	case errors.Is(err, unix.ENOENT):
+		return fmt.Errorf("SOCK_DESTROY: %w (the socket has already closed)", err)
+	}
+	return fmt.Errorf("SOCK_DESTROY: %w", err)
+}
+
+// hasCapability reports whether the effective capability set holds cap.
+func hasCapability(cap uint) bool {
+	status, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if hex, ok := strings.CutPrefix(line, "CapEff:"); ok {
+			eff, err := strconv.ParseUint(strings.TrimSpace(hex), 16, 64)
+			return err == nil && eff&(1<<cap) != 0
+		}
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package actions
+
+import (
+	"errors"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// DestroySocket is Linux-only: other platforms have no way to tear down
+// another process's socket.
+func DestroySocket(sock data.Socket, ns data.Namespace) (Action, error) {
+	return Action{}, errors.New("closing a single connection needs Linux's SOCK_DESTROY")
+}
@@ -0,0 +1,239 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jerryluo/nettui/internal/actions"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	processesTab "github.com/jerryluo/nettui/internal/tabs/processes"
+	socketsTab "github.com/jerryluo/nettui/internal/tabs/sockets"
+)
+
+// listKind says what the list view lists.
+type listKind int
+
+const (
+	listManagedRules listKind = iota
+	listSignals
+	listMenu
+	listChanges
+)
+
+// statusMsg shows msg in the status bar for a few seconds.
+func (m *Model) statusMsg(msg string) tea.Cmd {
+	m.message = msg
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return clearMsgMsg{} })
+}
+
+// confirmAction opens the confirmation modal for a. Nothing runs until
+// the user confirms.
+func (m *Model) confirmAction(a actions.Action) {
+	details := a.Details
+	if a.Namespace.Name != "" && !a.Namespace.Self {
+		details = append([]string{"In namespace " + a.Namespace.Name}, details...)
+	}
+	m.pending = a
+	m.reverting = -1
+	m.confirm.Show(a.Title, details, a.Preview, a.CanDryRun())
+}
+
+// handleConfirmKey handles keys while the confirmation modal is open.
+func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "y":
+		m.confirm.Close()
+		err := m.pending.Apply()
+		if err != nil {
+			m.reverting = -1
+			return m, m.statusMsg("Failed: " + err.Error())
+		}
+		m.recordChange()
+		if m.list.Visible() && m.listKind == listManagedRules {
+			m.loadManagedRules()
+		}
+		cmd := m.statusMsg("Done: " + m.pending.Title)
+		return m, tea.Batch(cmd, func() tea.Msg { return refreshMsg{} })
+	case "d":
+		if err := m.pending.DryRun(); err != nil {
+			m.confirm.SetResult("Dry run failed: "+err.Error(), true)
+		} else {
+			m.confirm.SetResult("Dry run passed; nothing was applied", false)
+		}
+		return m, nil
+	case "esc", "n":
+		m.confirm.Close()
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// blockRemote confirms a rule blocking, or allowing, the selected
+// socket's remote address.
+func (m Model) blockRemote(allow bool) (tea.Model, tea.Cmd) {
+	sockTab, ok := m.tabs[m.activeTab].(*socketsTab.Model)
+	if !ok {
+		return m, nil
+	}
+	sock, ok := sockTab.SelectedSocket()
+	if !ok {
+		return m, nil
+	}
+	addr := net.ParseIP(strings.SplitN(sock.RemoteAddr, "%", 2)[0])
+	switch {
+	case addr == nil || addr.IsUnspecified():
+		return m, m.statusMsg("Socket has no remote address")
+	case addr.IsLoopback():
+		return m, m.statusMsg("Not blocking loopback address " + addr.String())
+	}
+	fw, err := actions.DetectFirewall(m.store.Firewall)
+	if err != nil {
+		return m, m.statusMsg(err.Error())
+	}
+	m.confirmAction(fw.Rule(addr, allow, m.namespaceByName(sock.Namespace)))
+	return m, nil
+}
+
+// destroySocket confirms tearing down the selected TCP socket.
+func (m Model) destroySocket() (tea.Model, tea.Cmd) {
+	sockTab, ok := m.tabs[m.activeTab].(*socketsTab.Model)
+	if !ok {
+		return m, nil
+	}
+	sock, ok := sockTab.SelectedSocket()
+	if !ok {
+		return m, nil
+	}
+	a, err := actions.DestroySocket(sock, m.namespaceByName(sock.Namespace))
+	if err != nil {
+		return m, m.statusMsg(err.Error())
+	}
+	m.confirmAction(a)
+	return m, nil
+}
+
+// showManagedRules opens the list of rules nettui added to the firewall
+// of the namespace in view.
+func (m Model) showManagedRules() (tea.Model, tea.Cmd) {
+	fw, err := actions.DetectFirewall(m.store.Firewall)
+	if err != nil {
+		return m, m.statusMsg(err.Error())
+	}
+	m.managedFW = fw
+	m.managedNS = m.namespaceByName(m.namespaceForTrace())
+	title := fmt.Sprintf("Rules managed by nettui (%s)", fw.Name())
+	if m.managedNS.Name != "" && !m.managedNS.Self {
+		title += " in " + m.managedNS.Name
+	}
+	hint := model.HelpKeyStyle.Render("enter/x") + model.HelpDescStyle.Render(":remove  ") +
+		model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":close")
+	m.listKind = listManagedRules
+	m.list.Show(title, nil, "No rules added yet. Block or allow a remote with B or A on the Sockets tab.", hint)
+	m.loadManagedRules()
+	return m, nil
+}
+
+// loadManagedRules refreshes the managed rules list.
+func (m *Model) loadManagedRules() {
+	list, err := m.managedFW.List(m.managedNS)
+	m.managed = list
+	items := make([]string, len(list))
+	for i, r := range list {
+		items[i] = r.Rule
+	}
+	m.list.SetItems(items)
+	if err != nil {
+		m.list.SetError(err)
+	}
+}
+
+// showSignals opens the list of signals to send to the selected process,
+// or to the owner of the selected socket.
+func (m Model) showSignals() (tea.Model, tea.Cmd) {
+	var pid int32
+	switch tab := m.tabs[m.activeTab].(type) {
+	case *processesTab.Model:
+		pid, _ = tab.SelectedPID()
+	case *socketsTab.Model:
+		sock, ok := tab.SelectedSocket()
+		if !ok {
+			return m, nil
+		}
+		if sock.PID <= 0 {
+			return m, m.statusMsg("Socket owner unknown")
+		}
+		pid = sock.PID
+	default:
+		return m, nil
+	}
+	if pid <= 0 {
+		return m, nil
+	}
+	proc, ok := m.store.ProcessByPID[pid]
+	if !ok {
+		return m, m.statusMsg(fmt.Sprintf("PID %d not found", pid))
+	}
+	m.signalled = *proc
+
+	items := make([]string, len(actions.Signals))
+	for i, sig := range actions.Signals {
+		items[i] = fmt.Sprintf("%-8s %s", sig.Name(), sig.Desc)
+	}
+	hint := model.HelpKeyStyle.Render("enter") + model.HelpDescStyle.Render(":choose  ") +
+		model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":close")
+	m.listKind = listSignals
+	m.list.Show(fmt.Sprintf("Signal PID %d (%s)", proc.PID, proc.Name), items, "", hint)
+	return m, nil
+}
+
+// handleListKey handles keys while the list view is open.
+func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.list.Close()
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case msg.String() == "enter", msg.String() == "x":
+		i := m.list.Cursor()
+		switch m.listKind {
+		case listManagedRules:
+			if i >= 0 && i < len(m.managed) {
+				m.confirmAction(m.managed[i].Remove)
+			}
+		case listSignals:
+			if i >= 0 && i < len(actions.Signals) {
+				m.list.Close()
+				sockets := m.store.SocketsByPID[m.signalled.PID]
+				m.confirmAction(actions.SignalProcess(m.signalled, actions.Signals[i], sockets))
+			}
+		case listMenu:
+			return m, m.chooseMenuItem(i)
+		case listChanges:
+			if i >= 0 && i < len(m.changes) {
+				return m, m.revertChange(i)
+			}
+		}
+		return m, nil
+	}
+	m.list.Update(msg)
+	return m, nil
+}
+
+// namespaceByName finds a namespace in the store. An empty or unknown
+// name is nettui's own.
+func (m Model) namespaceByName(name string) data.Namespace {
+	for _, ns := range m.store.Namespaces {
+		if ns.Name == name && name != "" {
+			return ns
+		}
+	}
+	return data.Namespace{}
+}
@@ -8,17 +8,23 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/actions"
 	"github.com/jerryluo/nettui/internal/data"
 	"github.com/jerryluo/nettui/internal/data/sources"
 	"github.com/jerryluo/nettui/internal/model"
 	"github.com/jerryluo/nettui/internal/tabs"
+	firewallTab "github.com/jerryluo/nettui/internal/tabs/firewall"
+	interfacesTab "github.com/jerryluo/nettui/internal/tabs/interfaces"
+	listenersTab "github.com/jerryluo/nettui/internal/tabs/listeners"
 	processesTab "github.com/jerryluo/nettui/internal/tabs/processes"
 	socketsTab "github.com/jerryluo/nettui/internal/tabs/sockets"
+	statesTab "github.com/jerryluo/nettui/internal/tabs/states"
 	"github.com/jerryluo/nettui/internal/ui"
 	"github.com/jerryluo/nettui/internal/util"
 )
 
 type refreshMsg struct{}
+type tickMsg struct{}
 type clearMsgMsg struct{}
 type clearChordMsg struct{}
 
@@ -30,6 +36,10 @@ type Model struct {
 	collector *sources.Collector
 	store     *data.Store
 	panel     ui.SidePanel
+	trace     ui.TraceView
+	confirm   ui.ConfirmView
+	list      ui.ListView
+	prompt    ui.PromptView
 	layout    ui.Layout
 
 	width    int
@@ -38,6 +48,22 @@ type Model struct {
 	dnsOn    bool
 	message  string // ephemeral status message
 
+	refreshInterval time.Duration // auto-refresh period; 0 refreshes only on demand
+	netns           string        // selected network namespace, see Collector.SetNamespace
+
+	traceNS string // namespace whose firewall rules the trace view walks
+
+	pending   actions.Action                       // action awaiting confirmation
+	listKind  listKind                             // what the list view lists
+	managedFW actions.Firewall                     // backend of the managed rules list
+	managedNS data.Namespace                       // namespace of the managed rules list
+	managed   []actions.ManagedRule                // rules in the managed rules list
+	signalled data.Process                         // process the signal list is for
+	menu      []menuItem                           // entries of the change menu
+	promptFor func(string) (actions.Action, error) // builds the action from the prompt's input
+	changes   []change                             // applied changes that can be undone, oldest first
+	reverting int                                  // index in changes the pending action undoes, -1 if none
+
 	pendingChord rune   // first key of a chord sequence ('g' or 'f')
 	chordHint    string // hint text shown in status bar during chord
 
@@ -53,15 +79,48 @@ func New(tabModels []tabs.Tab, collector *sources.Collector) Model {
 		collector: collector,
 		store:     data.NewStore(),
 		panel:     ui.NewSidePanel(),
+		trace:     ui.NewTraceView(),
+		confirm:   ui.NewConfirmView(),
+		list:      ui.NewListView(),
+		prompt:    ui.NewPromptView(),
+		reverting: -1,
 		warnings:  make(map[model.TabID]bool),
 	}
 	m.panel.Show()
 	return m
 }
 
+// WithRefreshInterval enables periodic data refresh every d. A zero
+// interval leaves refreshing to the r key.
+func (m Model) WithRefreshInterval(d time.Duration) Model {
+	m.refreshInterval = d
+	for _, t := range m.tabs {
+		if ifaceTab, ok := t.(*interfacesTab.Model); ok {
+			ifaceTab.SetAutoRefresh(d > 0)
+		}
+	}
+	return m
+}
+
+// WithNamespace starts nettui in the given network namespace: a name from
+// the host's namespaces, or data.AllNamespaces.
+func (m Model) WithNamespace(name string) Model {
+	m.netns = name
+	m.collector.SetNamespace(name)
+	return m
+}
+
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	return func() tea.Msg { return refreshMsg{} }
+	refresh := func() tea.Msg { return refreshMsg{} }
+	if m.refreshInterval > 0 {
+		return tea.Batch(refresh, m.scheduleTick())
+	}
+	return refresh
+}
+
+func (m Model) scheduleTick() tea.Cmd {
+	return tea.Tick(m.refreshInterval, func(time.Time) tea.Msg { return tickMsg{} })
 }
 
 // Update implements tea.Model.
@@ -73,6 +132,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.recalcLayout()
 		return m, nil
 
+	case tickMsg:
+		updated, cmd := m.Update(refreshMsg{})
+		return updated, tea.Batch(cmd, m.scheduleTick())
+
 	case refreshMsg:
 		result := m.collector.Collect()
 		m.store.Update(result)
@@ -85,6 +148,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.warnings[model.TabUnixSockets] = true
 			m.warnings[model.TabProcesses] = true
 			m.warnings[model.TabFirewall] = true
+			m.warnings[model.TabFlows] = true
 		}
 
 		for _, t := range m.tabs {
@@ -126,6 +190,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKey(msg)
 	}
 
+	// The prompt's cursor blinks on its own messages
+	if m.prompt.Visible() {
+		return m, m.prompt.Update(msg)
+	}
+
 	// Delegate to active tab
 	if int(m.activeTab) < len(m.tabs) {
 		var cmd tea.Cmd
@@ -138,6 +207,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Modal views take all keys while open
+	if m.confirm.Visible() {
+		return m.handleConfirmKey(msg)
+	}
+	if m.prompt.Visible() {
+		return m.handlePromptKey(msg)
+	}
+	if m.list.Visible() {
+		return m.handleListKey(msg)
+	}
+	if m.trace.Visible() {
+		return m.handleTraceKey(msg)
+	}
+
 	// If current tab is filtering, let it handle all keys
 	if m.tabs[m.activeTab].IsFiltering() {
 		var cmd tea.Cmd
@@ -197,6 +280,18 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.activeTab = model.TabFirewall
 		m.updatePanelContent()
 		return m, nil
+	case key.Matches(msg, m.keys.Tab8):
+		m.activeTab = model.TabListeners
+		m.updatePanelContent()
+		return m, nil
+	case key.Matches(msg, m.keys.Tab9):
+		m.activeTab = model.TabStates
+		m.updatePanelContent()
+		return m, nil
+	case key.Matches(msg, m.keys.Tab10):
+		m.activeTab = model.TabFlows
+		m.updatePanelContent()
+		return m, nil
 
 	case key.Matches(msg, m.keys.Enter):
 		m.panel.Toggle()
@@ -208,6 +303,9 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Escape):
+		if ifaceTab, ok := m.tabs[m.activeTab].(*interfacesTab.Model); ok && ifaceTab.CloseChart() {
+			return m, nil
+		}
 		if m.tabs[m.activeTab].HasActiveFilter() {
 			m.tabs[m.activeTab].ClearFilter()
 			return m, nil
@@ -224,7 +322,25 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// On Sockets tab, enter chord mode for go-to selection
 		if m.activeTab == model.TabSockets {
 			m.pendingChord = 'g'
-			m.chordHint = "g→  p:Process  r:Remote"
+			m.chordHint = "g→  p:Process  r:Remote  t:Unit"
+			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
+		}
+		// On Interfaces tab, enter chord mode for related interfaces
+		if m.activeTab == model.TabInterfaces {
+			m.pendingChord = 'g'
+			m.chordHint = "g→  r:Routes  m:Master  p:Parent  e:Peer  a:Sockets  1-9:Sockets on address"
+			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
+		}
+		// On Listeners tab, enter chord mode for target selection
+		if m.activeTab == model.TabListeners {
+			m.pendingChord = 'g'
+			m.chordHint = "g→  s:Sockets  p:Process  f:Firewall rule"
+			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
+		}
+		// On States tab, enter chord mode for target selection
+		if m.activeTab == model.TabStates {
+			m.pendingChord = 'g'
+			m.chordHint = "g→  s:Socket  p:Process"
 			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
 		}
 		// On Unix Sockets tab, enter chord mode for target selection
@@ -249,7 +365,13 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// On Sockets tab, enter chord mode for protocol filtering
 		if m.activeTab == model.TabSockets {
 			m.pendingChord = 'f'
-			m.chordHint = "f→  t:TCP  u:UDP  4:IPv4  6:IPv6  c:clear"
+			m.chordHint = "f→  t:TCP  u:UDP  4:IPv4  6:IPv6  k:Container  c:clear"
+			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
+		}
+		// On Processes tab, enter chord mode for scopes and attribution filters
+		if m.activeTab == model.TabProcesses {
+			m.pendingChord = 'f'
+			m.chordHint = "f→  n:Networked  u:Mine  x:Kernel threads  c:clear  k:Container  t:Unit"
 			return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearChordMsg{} })
 		}
 		return m, nil
@@ -262,6 +384,127 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Refresh):
 		return m, func() tea.Msg { return refreshMsg{} }
 
+	case key.Matches(msg, m.keys.ResetTotals):
+		if m.activeTab != model.TabInterfaces {
+			return m, nil
+		}
+		m.collector.ResetSessionTotals()
+		m.message = "Session totals reset"
+		return m, tea.Batch(
+			func() tea.Msg { return refreshMsg{} },
+			tea.Tick(3*time.Second, func(time.Time) tea.Msg { return clearMsgMsg{} }),
+		)
+
+	case key.Matches(msg, m.keys.Chart):
+		if ifaceTab, ok := m.tabs[m.activeTab].(*interfacesTab.Model); ok {
+			ifaceTab.ToggleChart()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Topology):
+		if ifaceTab, ok := m.tabs[m.activeTab].(*interfacesTab.Model); ok {
+			ifaceTab.ToggleTopology()
+			m.updatePanelContent()
+		}
+		if procTab, ok := m.tabs[m.activeTab].(*processesTab.Model); ok {
+			procTab.ToggleTree()
+			m.updatePanelContent()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Resources):
+		if procTab, ok := m.tabs[m.activeTab].(*processesTab.Model); ok {
+			procTab.ToggleResources()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Collapse), key.Matches(msg, m.keys.CollapseAll), key.Matches(msg, m.keys.ExpandAll):
+		if fwTab, ok := m.tabs[m.activeTab].(*firewallTab.Model); ok && key.Matches(msg, m.keys.Collapse) {
+			fwTab.ToggleTables()
+			m.updatePanelContent()
+			return m, nil
+		}
+		procTab, ok := m.tabs[m.activeTab].(*processesTab.Model)
+		if !ok {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Collapse):
+			procTab.ToggleCollapse()
+		case key.Matches(msg, m.keys.CollapseAll):
+			procTab.CollapseAll()
+		default:
+			procTab.ExpandAll()
+		}
+		m.updatePanelContent()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Group):
+		if g, ok := m.tabs[m.activeTab].(tabs.Grouper); ok {
+			g.CycleGroup()
+			m.updatePanelContent()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Trace):
+		m.traceNS = m.namespaceForTrace()
+		if sockTab, ok := m.tabs[m.activeTab].(*socketsTab.Model); ok {
+			if sock, ok := sockTab.SelectedSocket(); ok {
+				m.traceNS = sock.Namespace
+				m.trace.Show(m.store.TraceRules(m.traceNS, m.store.SocketPacket(sock)))
+				return m, nil
+			}
+		}
+		return m, m.trace.Prompt()
+
+	case key.Matches(msg, m.keys.BlockRemote):
+		return m.blockRemote(false)
+
+	case key.Matches(msg, m.keys.AllowRemote):
+		return m.blockRemote(true)
+
+	case key.Matches(msg, m.keys.ManagedRules):
+		return m.showManagedRules()
+
+	case key.Matches(msg, m.keys.Signal):
+		return m.showSignals()
+
+	case key.Matches(msg, m.keys.DestroySocket):
+		return m.destroySocket()
+
+	case key.Matches(msg, m.keys.Change):
+		return m.showChangeMenu()
+
+	case key.Matches(msg, m.keys.Undo):
+		return m.undo()
+
+	case key.Matches(msg, m.keys.Changes):
+		return m.showChanges()
+
+	case key.Matches(msg, m.keys.Units):
+		if ifaceTab, ok := m.tabs[m.activeTab].(*interfacesTab.Model); ok {
+			ifaceTab.ToggleUnits()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.NextNetNS), key.Matches(msg, m.keys.PrevNetNS):
+		step := 1
+		if key.Matches(msg, m.keys.PrevNetNS) {
+			step = -1
+		}
+		choices := namespaceChoices(m.store.Namespaces)
+		if len(choices) == 1 {
+			m.message = "No other network namespaces"
+			return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg { return clearMsgMsg{} })
+		}
+		m.netns = cycle(choices, m.netns, step)
+		m.collector.SetNamespace(m.netns)
+		m.message = "Namespace: " + namespaceLabel(m.netns)
+		return m, tea.Batch(
+			func() tea.Msg { return refreshMsg{} },
+			tea.Tick(3*time.Second, func(time.Time) tea.Msg { return clearMsgMsg{} }),
+		)
+
 	case key.Matches(msg, m.keys.DNS):
 		m.dnsOn = !m.dnsOn
 		// Propagate DNS state to the sockets tab.
@@ -310,6 +553,92 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// handleTraceKey handles keys while the trace view is open: the packet
+// prompt gets everything but Enter and Escape, and the trace itself
+// scrolls.
+func (m Model) handleTraceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.trace.Editing() {
+		switch msg.Type {
+		case tea.KeyEnter:
+			pkt, err := data.ParsePacket(m.trace.Input())
+			if err != nil {
+				m.trace.SetError(err)
+				return m, nil
+			}
+			m.trace.Show(m.store.TraceRules(m.traceNS, pkt))
+			return m, nil
+		case tea.KeyEsc:
+			m.trace.Close()
+			return m, nil
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		}
+		return m, m.trace.Update(msg)
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.trace.Close()
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case msg.String() == "e":
+		return m, m.trace.Prompt()
+	}
+	return m, m.trace.Update(msg)
+}
+
+// namespaceForTrace returns the namespace whose rules a typed-in packet is
+// traced against: the selected one, or nettui's own when viewing all.
+func (m Model) namespaceForTrace() string {
+	if m.netns != data.AllNamespaces {
+		return m.netns
+	}
+	for _, ns := range m.store.Namespaces {
+		if ns.Self {
+			return ns.Name
+		}
+	}
+	return ""
+}
+
+// namespaceChoices lists the namespace selections n and N cycle through:
+// nettui's own, all of them, then each other namespace by name.
+func namespaceChoices(namespaces []data.Namespace) []string {
+	var names []string
+	for _, ns := range namespaces {
+		if !ns.Self {
+			names = append(names, ns.Name)
+		}
+	}
+	if len(names) == 0 {
+		return []string{""}
+	}
+	return append([]string{"", data.AllNamespaces}, names...)
+}
+
+// cycle returns the choice step places after cur, wrapping around. A
+// selection that is no longer listed starts over from the first choice.
+func cycle(choices []string, cur string, step int) string {
+	for i, c := range choices {
+		if c == cur {
+			return choices[(i+step+len(choices))%len(choices)]
+		}
+	}
+	return choices[0]
+}
+
+// namespaceLabel renders a namespace selection for the status bar.
+func namespaceLabel(netns string) string {
+	switch netns {
+	case "":
+		return "self"
+	case data.AllNamespaces:
+		return "all"
+	}
+	return netns
+}
+
 func (m Model) handleChordSecondKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	chord := m.pendingChord
 	m.pendingChord = 0
@@ -367,6 +696,70 @@ func (m Model) handleGotoChord(k string) (tea.Model, tea.Cmd) {
 			if sockTab.GoToRemotePeer() {
 				m.updatePanelContent()
 			}
+		case "t":
+			if sockTab.FilterBySame("unit") {
+				m.updatePanelContent()
+			}
+		}
+
+	case model.TabInterfaces:
+		ifaceTab, ok := m.tabs[model.TabInterfaces].(*interfacesTab.Model)
+		if !ok {
+			return m, nil
+		}
+		switch k {
+		case "r":
+			ref := ifaceTab.CrossRef()
+			if ref != nil {
+				return m.Update(*ref)
+			}
+		case "m", "p", "e":
+			if ifaceTab.GoToRelated(k) {
+				m.updatePanelContent()
+			}
+		case "a", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			n := 0
+			if k != "a" {
+				n = int(k[0] - '0')
+			}
+			ref := ifaceTab.AddressCrossRef(n)
+			if ref != nil {
+				return m.Update(*ref)
+			}
+		}
+
+	case model.TabListeners:
+		listenTab, ok := m.tabs[model.TabListeners].(*listenersTab.Model)
+		if !ok {
+			return m, nil
+		}
+		var ref *model.CrossRefMsg
+		switch k {
+		case "s":
+			ref = listenTab.CrossRefTo(model.TabSockets)
+		case "p":
+			ref = listenTab.CrossRefTo(model.TabProcesses)
+		case "f":
+			ref = listenTab.CrossRefTo(model.TabFirewall)
+		}
+		if ref != nil {
+			return m.Update(*ref)
+		}
+
+	case model.TabStates:
+		stateTab, ok := m.tabs[model.TabStates].(*statesTab.Model)
+		if !ok {
+			return m, nil
+		}
+		var ref *model.CrossRefMsg
+		switch k {
+		case "s":
+			ref = stateTab.CrossRefTo(model.TabSockets)
+		case "p":
+			ref = stateTab.CrossRefTo(model.TabProcesses)
+		}
+		if ref != nil {
+			return m.Update(*ref)
 		}
 
 	case model.TabUnixSockets:
@@ -381,12 +774,42 @@ func (m Model) handleGotoChord(k string) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleFilterChord(k string) (tea.Model, tea.Cmd) {
+	if m.activeTab == model.TabProcesses {
+		procTab, ok := m.tabs[model.TabProcesses].(*processesTab.Model)
+		if !ok {
+			return m, nil
+		}
+		switch k {
+		case "n":
+			procTab.ToggleScope(processesTab.ScopeNetworked)
+		case "u":
+			procTab.ToggleScope(processesTab.ScopeMine)
+		case "x":
+			procTab.ToggleScope(processesTab.ScopeNoKernel)
+		case "c":
+			procTab.ClearScopes()
+		case "k":
+			if procTab.FilterBySame("container") {
+				m.updatePanelContent()
+			}
+		case "t":
+			if procTab.FilterBySame("unit") {
+				m.updatePanelContent()
+			}
+		}
+		return m, nil
+	}
+
 	sockTab, ok := m.tabs[model.TabSockets].(*socketsTab.Model)
 	if !ok {
 		return m, nil
 	}
 
 	switch k {
+	case "k":
+		if sockTab.FilterBySame("container") {
+			m.updatePanelContent()
+		}
 	case "t":
 		sockTab.ToggleTransportFilter(socketsTab.TransportTCP)
 	case "u":
@@ -437,6 +860,10 @@ func (m *Model) recalcLayout() {
 	if m.layout.PanelOpen {
 		m.panel.SetSize(m.layout.PanelWidth, m.layout.ContentHeight)
 	}
+	m.trace.SetSize(m.width, m.layout.ContentHeight)
+	m.confirm.SetSize(m.width, m.layout.ContentHeight)
+	m.list.SetSize(m.width, m.layout.ContentHeight)
+	m.prompt.SetSize(m.width, m.layout.ContentHeight)
 	for _, t := range m.tabs {
 		t.SetPanelWidth(m.layout.PanelWidth)
 	}
@@ -459,11 +886,20 @@ func (m Model) View() string {
 	var content string
 	tabView := m.tabs[m.activeTab].View()
 
-	if m.layout.PanelOpen {
+	switch {
+	case m.confirm.Visible():
+		content = m.confirm.View()
+	case m.prompt.Visible():
+		content = m.prompt.View()
+	case m.list.Visible():
+		content = m.list.View()
+	case m.trace.Visible():
+		content = m.trace.View()
+	case m.layout.PanelOpen:
 		tabView = lipgloss.NewStyle().Width(m.layout.TableWidth).Render(tabView)
 		panelView := m.panel.View()
 		content = lipgloss.JoinHorizontal(lipgloss.Top, tabView, panelView)
-	} else {
+	default:
 		content = tabView
 	}
 
@@ -479,12 +915,36 @@ func (m Model) View() string {
 		protoFilter = sockTab.ProtoFilterLabel()
 	}
 
+	// Extract scope label from processes tab
+	var scope string
+	if procTab, ok := m.tabs[model.TabProcesses].(*processesTab.Model); ok {
+		scope = procTab.ScopeLabel()
+	}
+
+	// Extract view label (topology or grouping) from the active tab
+	var viewLabel string
+	if ifaceTab, ok := m.tabs[m.activeTab].(*interfacesTab.Model); ok {
+		viewLabel = ifaceTab.TopologyLabel()
+	}
+	if g, ok := m.tabs[m.activeTab].(tabs.Grouper); ok {
+		viewLabel = g.GroupLabel()
+	}
+	if procTab, ok := m.tabs[m.activeTab].(*processesTab.Model); ok && procTab.TreeLabel() != "" {
+		viewLabel = procTab.TreeLabel()
+	}
+
 	// Extract sort label from active tab
 	sortLabel := m.tabs[m.activeTab].SortLabel()
 
 	// Extract nav filter label from active tab
 	navFilter := m.tabs[m.activeTab].NavFilterLabel()
 
+	// Show the namespace only when it is not nettui's own
+	var netnsLabel string
+	if m.netns != "" {
+		netnsLabel = namespaceLabel(m.netns)
+	}
+
 	// Status bar
 	statusBar := ui.RenderStatusBar(ui.StatusBarState{
 		IsRoot:      m.store.IsRoot,
@@ -492,8 +952,11 @@ func (m Model) View() string {
 		Message:     m.message,
 		ChordHint:   m.chordHint,
 		ProtoFilter: protoFilter,
+		Scope:       scope,
+		ViewLabel:   viewLabel,
 		SortLabel:   sortLabel,
 		NavFilter:   navFilter,
+		NetNS:       netnsLabel,
 	}, m.width)
 
 	return lipgloss.JoinVertical(lipgloss.Left, tabBar, content, statusBar)
@@ -511,7 +974,7 @@ func (m Model) helpView() string {
 	}{
 		{"q / Ctrl+C", "Quit"},
 		{"h/l / Tab/Shift+Tab", "Prev / next tab"},
-		{"1-7", "Jump to tab"},
+		{"1-9, 0", "Jump to tab"},
 		{"j/k / arrows", "Navigate rows"},
 		{"d/u", "Page down / up"},
 		{"/", "Filter / search"},
@@ -520,14 +983,42 @@ func (m Model) helpView() string {
 		{"g", "Go to cross-referenced entity"},
 		{"gs/gu", "Go to Sockets/Unix (Processes tab)"},
 		{"gp/gr", "Go to Process/Remote (Sockets tab)"},
+		{"gt", "Go to all sockets of the owner's systemd unit (Sockets tab)"},
 		{"gp", "Go to Process (Unix Sockets tab)"},
+		{"gs/gp/gf", "Go to the service's Sockets/Process/deciding Firewall rule (Listeners tab)"},
+		{"gs/gp", "Go to the entry's local Socket/Process (States tab)"},
+		{"g", "Go to the flow's Process (Flows tab)"},
+		{"gr/gm/gp/ge", "Go to Routes/Master/Parent/Peer (Interfaces tab)"},
+		{"ga/g1-g9", "Go to sockets bound to the interface's addresses (Interfaces tab)"},
 		{"f", "Protocol filter (Sockets tab)"},
 		{"ft/fu/f4/f6/fc", "TCP/UDP/IPv4/IPv6/clear"},
+		{"fn/fu/fx/fc", "Networked only / my processes / hide kernel threads / clear (Processes)"},
+		{"fk", "Only the selected row's container (Sockets/Processes)"},
+		{"ft", "Only the selected process's systemd unit (Processes tab)"},
+		{"G", "Group by container / unit (Sockets/Processes)"},
 		{"s", "Sort by column (chord)"},
 		{"y", "Yank (copy) chord — field to clipboard"},
 		{"yl/yr", "Yank local/remote addr (Sockets)"},
 		{"yp/yn", "Yank PID/process name"},
 		{"yy", "Yank full row summary"},
+		{"z", "Reset session totals (Interfaces tab)"},
+		{"c", "Throughput chart (Interfaces tab)"},
+		{"t", "Topology tree (Interfaces tab) / process tree (Processes tab)"},
+		{"space / - / +", "Collapse or expand subtree / all (process tree)"},
+		{"space", "Expand or collapse table contents in the detail panel (Firewall tab)"},
+		{"R", "CPU, memory, thread, FD and uptime columns (Processes tab)"},
+		{"T", "Firewall trace of the selected socket (Sockets tab) or a typed-in packet"},
+		{"B/A", "Block / allow the selected socket's remote address in nettui's firewall anchor (Sockets tab)"},
+		{"M", "List and remove the firewall rules nettui added"},
+		{"X", "Close the selected TCP socket with SOCK_DESTROY, like ss -K (Sockets tab, Linux)"},
+		{"E", "Bring up/down, add or remove an address, set the MTU (Interfaces tab, Linux)"},
+		{"E", "Add or delete a route, change the default gateway (Routes tab, Linux)"},
+		{"E", "Add a static entry, flush stale entries (ARP tab, Linux)"},
+		{"U", "Undo the last change"},
+		{"L", "Change log of this session, to revert any change"},
+		{"K", "Send SIGTERM, SIGKILL or another signal to the selected process or socket owner (Processes/Sockets)"},
+		{"b", "Toggle bits/bytes (Interfaces tab)"},
+		{"n/N", "Next / previous network namespace"},
 		{"r", "Refresh data"},
 		{"D", "Toggle DNS resolution"},
 		{"?", "Toggle this help"},
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jerryluo/nettui/internal/actions"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	arpTab "github.com/jerryluo/nettui/internal/tabs/arp"
+	interfacesTab "github.com/jerryluo/nettui/internal/tabs/interfaces"
+	routesTab "github.com/jerryluo/nettui/internal/tabs/routes"
+)
+
+// menuItem is an entry of the change menu. Choosing it builds an action
+// from the input typed into a prompt, or straight away when it has none.
+type menuItem struct {
+	label       string
+	prompt      string // title of the prompt asking for input, "" for none
+	placeholder string
+	value       string // input the prompt starts with
+	build       func(input string) (actions.Action, error)
+}
+
+// change is an action applied this session, kept in the change log.
+type change struct {
+	at       time.Time
+	action   actions.Action
+	reverted bool
+}
+
+// showChangeMenu opens the list of changes that can be made to the
+// selected row.
+func (m Model) showChangeMenu() (tea.Model, tea.Cmd) {
+	var title string
+	var items []menuItem
+	switch tab := m.tabs[m.activeTab].(type) {
+	case *interfacesTab.Model:
+		iface, ok := tab.SelectedInterface()
+		if !ok {
+			return m, nil
+		}
+		title = "Change " + iface.Name
+		items = interfaceMenu(iface, m.changeNamespace(iface.Namespace))
+	case *routesTab.Model:
+		route, ok := tab.SelectedRoute()
+		title = "Change routes"
+		items = m.routeMenu(route, ok)
+	case *arpTab.Model:
+		entry, ok := tab.SelectedEntry()
+		title = "Change neighbors"
+		items = arpMenu(entry, ok, m.changeNamespace(entry.Namespace))
+	default:
+		return m, nil
+	}
+
+	m.menu = items
+	labels := make([]string, len(items))
+	for i, it := range items {
+		labels[i] = it.label
+	}
+	hint := model.HelpKeyStyle.Render("enter") + model.HelpDescStyle.Render(":choose  ") +
+		model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":close")
+	m.listKind = listMenu
+	m.list.Show(title, labels, "", hint)
+	return m, nil
+}
+
+// interfaceMenu lists the changes that can be made to iface.
+func interfaceMenu(iface data.Interface, ns data.Namespace) []menuItem {
+	items := []menuItem{
+		{
+			label: "Bring down",
+			build: func(string) (actions.Action, error) { return actions.SetLinkUp(iface, false, ns) },
+		},
+		{
+			label:       fmt.Sprintf("Set MTU… (now %d)", iface.MTU),
+			prompt:      "MTU of " + iface.Name,
+			placeholder: "1500",
+			value:       fmt.Sprintf("%d", iface.MTU),
+			build:       func(in string) (actions.Action, error) { return actions.SetMTU(iface, in, ns) },
+		},
+		{
+			label:       "Add address…",
+			prompt:      "Address to add to " + iface.Name,
+			placeholder: "10.0.0.5/24 or fd00::5/64",
+			build:       func(in string) (actions.Action, error) { return actions.AddAddress(iface, in, ns) },
+		},
+	}
+	if !iface.Up {
+		items[0] = menuItem{
+			label: "Bring up",
+			build: func(string) (actions.Action, error) { return actions.SetLinkUp(iface, true, ns) },
+		}
+	}
+	for _, addr := range iface.Addresses {
+		items = append(items, menuItem{
+			label: fmt.Sprintf("Remove %s/%d", addr.IP, addr.PrefixLen),
+			build: func(string) (actions.Action, error) { return actions.RemoveAddress(iface, addr, ns) },
+		})
+	}
+	return items
+}
+
+// routeMenu lists the route changes, including deleting route if
+// selected is set. Changing the default gateway replaces route when it is
+// a default route.
+func (m Model) routeMenu(route data.Route, selected bool) []menuItem {
+	ns := m.changeNamespace(route.Namespace)
+	gateway := ""
+	for _, r := range m.store.Routes {
+		if r.IsDefault() && r.Gateway != "" && r.Namespace == route.Namespace && (gateway == "" || r == route) {
+			gateway = r.Gateway
+		}
+	}
+	items := []menuItem{
+		{
+			label:       "Add route…",
+			prompt:      "Route to add",
+			placeholder: "10.20.0.0/16 via 192.168.1.1 dev eth0 metric 100",
+			build:       func(in string) (actions.Action, error) { return actions.AddRoute(in, ns) },
+		},
+		{
+			label:       "Change default gateway…",
+			prompt:      "New default gateway",
+			placeholder: "192.168.1.254, optionally followed by dev eth1",
+			value:       gateway,
+			build:       func(in string) (actions.Action, error) { return actions.ChangeGateway(in, route, ns) },
+		},
+	}
+	if selected {
+		label := "Delete route " + route.Destination
+		if route.Gateway != "" {
+			label += " via " + route.Gateway
+		}
+		if route.Interface != "" {
+			label += " dev " + route.Interface
+		}
+		items = append(items, menuItem{
+			label: label,
+			build: func(string) (actions.Action, error) { return actions.DeleteRoute(route, ns) },
+		})
+	}
+	return items
+}
+
+// arpMenu lists the neighbor table changes. A static entry is prefilled
+// from the selected entry, on its interface.
+func arpMenu(entry data.ARPEntry, selected bool, ns data.Namespace) []menuItem {
+	value := ""
+	if selected && entry.MAC != "(incomplete)" {
+		value = entry.IP + " " + entry.MAC
+	}
+	placeholder := "10.0.0.9 02:42:ac:11:00:02 dev eth0"
+	if entry.Interface != "" {
+		placeholder = "10.0.0.9 02:42:ac:11:00:02 (on " + entry.Interface + ")"
+	}
+	return []menuItem{
+		{
+			label:       "Add static entry…",
+			prompt:      "Static neighbor entry",
+			placeholder: placeholder,
+			value:       value,
+			build: func(in string) (actions.Action, error) {
+				return actions.AddStaticNeighbor(in, entry.Interface, ns)
+			},
+		},
+		{
+			label: "Flush stale entries",
+			build: func(string) (actions.Action, error) { return actions.FlushStaleNeighbors(ns) },
+		},
+	}
+}
+
+// changeNamespace returns the namespace a change to a row labelled with
+// the namespace name runs in: the one in view if the row is unlabelled.
+func (m Model) changeNamespace(name string) data.Namespace {
+	if name == "" {
+		name = m.namespaceForTrace()
+	}
+	return m.namespaceByName(name)
+}
+
+// chooseMenuItem runs the menu entry at i, opening its prompt first if it
+// has one.
+func (m *Model) chooseMenuItem(i int) tea.Cmd {
+	if i < 0 || i >= len(m.menu) {
+		return nil
+	}
+	it := m.menu[i]
+	m.list.Close()
+	if it.prompt != "" {
+		m.promptFor = it.build
+		return m.prompt.Show(it.prompt, it.placeholder, it.value)
+	}
+	a, err := it.build("")
+	if err != nil {
+		return m.statusMsg(err.Error())
+	}
+	m.confirmAction(a)
+	return nil
+}
+
+// handlePromptKey handles keys while the prompt is open.
+func (m Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Escape):
+		m.prompt.Close()
+		return m, nil
+	case msg.String() == "enter":
+		a, err := m.promptFor(m.prompt.Value())
+		if err != nil {
+			m.prompt.SetError(err)
+			return m, nil
+		}
+		m.prompt.Close()
+		m.confirmAction(a)
+		return m, nil
+	}
+	return m, m.prompt.Update(msg)
+}
+
+// recordChange logs the applied pending action, or marks the change it
+// reverted as undone.
+func (m *Model) recordChange() {
+	if m.reverting >= 0 {
+		m.changes[m.reverting].reverted = true
+		m.reverting = -1
+	} else {
+		m.changes = append(m.changes, change{at: time.Now(), action: m.pending})
+	}
+	if m.list.Visible() && m.listKind == listChanges {
+		m.loadChanges()
+	}
+}
+
+// undo confirms reverting the last change not undone yet.
+func (m Model) undo() (tea.Model, tea.Cmd) {
+	for i := len(m.changes) - 1; i >= 0; i-- {
+		if !m.changes[i].reverted && m.changes[i].action.Revert != nil {
+			return m, m.revertChange(i)
+		}
+	}
+	return m, m.statusMsg("Nothing to undo")
+}
+
+// revertChange confirms reverting the change at i.
+func (m *Model) revertChange(i int) tea.Cmd {
+	c := m.changes[i]
+	switch {
+	case c.reverted:
+		return m.statusMsg("Already undone: " + c.action.Title)
+	case c.action.Revert == nil:
+		return m.statusMsg("Cannot be undone: " + c.action.Title)
+	}
+	m.confirmAction(*c.action.Revert)
+	m.reverting = i
+	return nil
+}
+
+// showChanges opens the change log.
+func (m Model) showChanges() (tea.Model, tea.Cmd) {
+	hint := model.HelpKeyStyle.Render("enter/x") + model.HelpDescStyle.Render(":revert  ") +
+		model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":close")
+	m.listKind = listChanges
+	m.list.Show("Changes this session", nil, "No changes made yet.", hint)
+	m.loadChanges()
+	return m, nil
+}
+
+// loadChanges refreshes the change log list, newest last.
+func (m *Model) loadChanges() {
+	items := make([]string, len(m.changes))
+	for i, c := range m.changes {
+		items[i] = c.at.Format("15:04:05") + "  " + c.action.Title
+		switch {
+		case c.reverted:
+			items[i] += "  (undone)"
+		case c.action.Revert == nil:
+			items[i] += "  (cannot be undone)"
+		}
+	}
+	m.list.SetItems(items)
+}
@@ -14,6 +14,9 @@ type KeyMap struct {
 	Tab5      key.Binding
 	Tab6      key.Binding
 	Tab7      key.Binding
+	Tab8      key.Binding
+	Tab9      key.Binding
+	Tab10     key.Binding
 	Up        key.Binding
 	Down      key.Binding
 	Filter    key.Binding
@@ -28,6 +31,26 @@ type KeyMap struct {
 	PageDown    key.Binding
 	ProtoFilter key.Binding
 	Sort        key.Binding
+	ResetTotals key.Binding
+	Chart       key.Binding
+	Units       key.Binding
+	Topology    key.Binding
+	Group       key.Binding
+	Collapse    key.Binding
+	CollapseAll key.Binding
+	ExpandAll   key.Binding
+	Resources   key.Binding
+	Trace       key.Binding
+	BlockRemote  key.Binding
+	AllowRemote  key.Binding
+	ManagedRules key.Binding
+	Signal       key.Binding
+	DestroySocket key.Binding
+	Change       key.Binding
+	Undo         key.Binding
+	Changes      key.Binding
+	NextNetNS   key.Binding
+	PrevNetNS   key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings.
@@ -52,6 +75,9 @@ func DefaultKeyMap() KeyMap {
 		Tab5: key.NewBinding(key.WithKeys("5"), key.WithHelp("5", "Processes")),
 		Tab6: key.NewBinding(key.WithKeys("6"), key.WithHelp("6", "ARP")),
 		Tab7: key.NewBinding(key.WithKeys("7"), key.WithHelp("7", "Firewall")),
+		Tab8: key.NewBinding(key.WithKeys("8"), key.WithHelp("8", "Listeners")),
+		Tab9: key.NewBinding(key.WithKeys("9"), key.WithHelp("9", "States")),
+		Tab10: key.NewBinding(key.WithKeys("0"), key.WithHelp("0", "Flows")),
 		Up: key.NewBinding(
 			key.WithKeys("k", "up"),
 			key.WithHelp("k/up", "up"),
@@ -108,5 +134,85 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "sort"),
 		),
+		ResetTotals: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "reset session totals"),
+		),
+		Chart: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "throughput chart"),
+		),
+		Units: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "bits/bytes"),
+		),
+		Topology: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "topology / process tree"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "group rows"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "collapse/expand subtree"),
+		),
+		CollapseAll: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "collapse all"),
+		),
+		ExpandAll: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "expand all"),
+		),
+		Resources: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "resource columns"),
+		),
+		Trace: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "firewall trace"),
+		),
+		BlockRemote: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "block remote"),
+		),
+		AllowRemote: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "allow remote"),
+		),
+		ManagedRules: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "managed firewall rules"),
+		),
+		Signal: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "signal process"),
+		),
+		DestroySocket: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "close TCP socket"),
+		),
+		Change: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "change interface, route or neighbor"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "undo last change"),
+		),
+		Changes: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "change log"),
+		),
+		NextNetNS: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next namespace"),
+		),
+		PrevNetNS: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous namespace"),
+		),
 	}
 }
@@ -0,0 +1,151 @@
+package data
+
+import (
+	"net"
+	"strings"
+)
+
+// Verdict is the firewall's decision on inbound traffic to a listener.
+type Verdict int
+
+const (
+	VerdictUnknown Verdict = iota // no rules loaded, or the deciding rule depends on the peer
+	VerdictAllowed
+	VerdictBlocked
+)
+
+// String returns the verdict as shown in the Listeners tab.
+func (v Verdict) String() string {
+	switch v {
+	case VerdictAllowed:
+		return "allowed"
+	case VerdictBlocked:
+		return "blocked"
+	default:
+		return "unknown"
+	}
+}
+
+// AuditListeners sets the firewall verdict of each listener from the rules
+// loaded in its namespace.
+func AuditListeners(listeners []Listener, rules []FirewallRule) {
+	byNS := make(map[string][]FirewallRule)
+	for _, r := range rules {
+		byNS[r.Namespace] = append(byNS[r.Namespace], r)
+	}
+	for i := range listeners {
+		l := &listeners[i]
+		l.Verdict, l.VerdictRule = InboundVerdict(byNS[l.Namespace], *l)
+	}
+}
+
+// InboundVerdict evaluates rules for inbound traffic from any peer to l.
+// It returns the verdict and the Ref of the deciding rule, or "" when no
+// rule decides. A rule that matches only some peers or interfaces
+// leaves the verdict unknown unless a later rule matches all traffic.
+func InboundVerdict(rules []FirewallRule, l Listener) (Verdict, string) {
+	if len(rules) == 0 {
+		return VerdictUnknown, ""
+	}
+	return evaluate(rules, "in", func(r FirewallRule) (Match, string) {
+		return matchListener(r, l), ""
+	}, nil)
+}
+
+// matchListener reports whether r applies to inbound traffic to l.
+func matchListener(r FirewallRule, l Listener) Match {
+	if r.Direction == "out" {
+		return MatchNo
+	}
+	m := MatchYes
+	if r.User != "" || r.Group != "" || r.Tagged != "" || r.Extra != "" {
+		m = MatchMaybe
+	}
+	if !r.From.IsAny() {
+		m = MatchMaybe
+	}
+	checks := []Match{
+		matchRemoteIface(r.Iface),
+		matchCtState(r.CtState),
+		matchProto(r.Proto, l.Proto),
+		matchFamily(r.Family, l.Sockets),
+		matchBinds(r.To, l.Addrs),
+		r.To.Port.Match(l.Port),
+	}
+	for _, c := range checks {
+		switch c {
+		case MatchNo:
+			return MatchNo
+		case MatchMaybe:
+			m = MatchMaybe
+		}
+	}
+	return m
+}
+
+// matchRemoteIface matches a rule's interface against traffic from other
+// hosts, which arrives on any interface but loopback.
+func matchRemoteIface(ruleIface string) Match {
+	name, not := strings.CutPrefix(ruleIface, "! ")
+	switch {
+	case ruleIface == "":
+		return MatchYes
+	case name != "lo" && name != "lo0":
+		return MatchMaybe
+	case not:
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// matchFamily matches a rule's address family against the families of a
+// listener's sockets.
+func matchFamily(family string, sockets []Socket) Match {
+	if family == "" {
+		return MatchYes
+	}
+	hit, miss := 0, 0
+	for _, s := range sockets {
+		if strings.HasSuffix(s.Proto, "6") == (family == "inet6") {
+			hit++
+		} else {
+			miss++
+		}
+	}
+	return tally(hit, miss)
+}
+
+// matchBinds matches a rule's destination address against a listener's
+// bind addresses. Wildcard binds accept traffic to every local address, so
+// only "any" is sure to cover them.
+func matchBinds(to AddrSpec, binds []string) Match {
+	if to.Addr == "" || to.Addr == "any" {
+		return to.MatchAddr(nil)
+	}
+	hit, miss := 0, 0
+	for _, b := range binds {
+		if addrExposure(b) == ExposureAll {
+			return MatchMaybe
+		}
+		switch to.MatchAddr(net.ParseIP(stripZone(b))) {
+		case MatchYes:
+			hit++
+		case MatchNo:
+			miss++
+		default:
+			return MatchMaybe
+		}
+	}
+	return tally(hit, miss)
+}
+
+// tally combines per-socket or per-address matches.
+func tally(hit, miss int) Match {
+	switch {
+	case miss == 0:
+		return MatchYes
+	case hit == 0:
+		return MatchNo
+	}
+	return MatchMaybe
+}
@@ -0,0 +1,485 @@
+package data
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// Match is how a firewall rule applies to the traffic being evaluated.
+type Match int
+
+const (
+	MatchNo    Match = iota // the rule does not match
+	MatchYes                // the rule matches
+	MatchMaybe              // the rule matches depending on what cannot be evaluated
+)
+
+// String returns the match as shown in a trace.
+func (m Match) String() string {
+	switch m {
+	case MatchYes:
+		return "match"
+	case MatchMaybe:
+		return "maybe"
+	default:
+		return "no match"
+	}
+}
+
+// Decides reports whether the rule can set the verdict: pass and block
+// rules can, while match, scrub and anchor rules cannot.
+func (r FirewallRule) Decides() bool {
+	return r.Action == "pass" || r.Action == "block"
+}
+
+// Hit reports whether the rule's counters moved since the previous
+// refresh, that is whether it is matching traffic right now.
+func (r FirewallRule) Hit() bool {
+	return r.PacketRate > 0 || r.ByteRate > 0
+}
+
+// IsAny reports whether the spec matches every address and port.
+func (a AddrSpec) IsAny() bool {
+	return !a.Not && (a.Addr == "" || a.Addr == "any") && a.Port.Op == ""
+}
+
+// String returns the spec as shown in a trace.
+func (a AddrSpec) String() string {
+	addr := a.Addr
+	if addr == "" {
+		addr = "any"
+	}
+	if a.Not {
+		addr = "! " + addr
+	}
+	if a.Port.Op != "" {
+		addr += " port " + a.Port.Name
+	}
+	return addr
+}
+
+// MatchAddr matches the address part of the spec against ip. A nil ip
+// stands for any peer, which only "any" is sure to match. Tables and
+// interface addresses cannot be evaluated.
+func (a AddrSpec) MatchAddr(ip net.IP) Match {
+	if a.Addr == "" || a.Addr == "any" {
+		if a.Not {
+			return MatchNo
+		}
+		return MatchYes
+	}
+	network := a.network()
+	if ip == nil || network == nil {
+		return MatchMaybe
+	}
+	if network.Contains(ip) != a.Not {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// MatchTable matches a <table> address spec against ip using the table's
+// entries. An entry negated with "!" excludes the addresses it covers.
+func (a AddrSpec) MatchTable(entries []string, ip net.IP) Match {
+	if ip == nil {
+		return MatchMaybe
+	}
+	in := false
+	for _, e := range entries {
+		neg := strings.HasPrefix(e, "!")
+		n := AddrSpec{Addr: strings.TrimSpace(strings.TrimPrefix(e, "!"))}.network()
+		if n == nil || !n.Contains(ip) {
+			continue
+		}
+		if neg {
+			in = false
+			break
+		}
+		in = true
+	}
+	if in != a.Not {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// Table returns the table name of a <table> address spec, or "".
+func (a AddrSpec) Table() string {
+	if strings.HasPrefix(a.Addr, "<") && strings.HasSuffix(a.Addr, ">") {
+		return strings.Trim(a.Addr, "<>")
+	}
+	return ""
+}
+
+// network parses the address as a single address or a network.
+func (a AddrSpec) network() *net.IPNet {
+	if _, n, err := net.ParseCIDR(a.Addr); err == nil {
+		return n
+	}
+	ip := net.ParseIP(a.Addr)
+	if ip == nil {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// Match matches the spec against port. Port 0 stands for any port.
+func (p PortSpec) Match(port uint32) Match {
+	if p.Op == "" {
+		return MatchYes
+	}
+	if port == 0 || p.Op == "?" {
+		return MatchMaybe
+	}
+	if p.Op == "{" {
+		m := MatchNo
+		for _, spec := range p.List {
+			switch spec.Match(port) {
+			case MatchYes:
+				return MatchYes
+			case MatchMaybe:
+				m = MatchMaybe
+			}
+		}
+		return m
+	}
+	var ok bool
+	switch p.Op {
+	case "=":
+		ok = port == p.Lo
+	case "!=":
+		ok = port != p.Lo
+	case "<":
+		ok = port < p.Lo
+	case "<=":
+		ok = port <= p.Lo
+	case ">":
+		ok = port > p.Lo
+	case ">=":
+		ok = port >= p.Lo
+	case ":":
+		ok = port >= p.Lo && port <= p.Hi
+	case "><":
+		ok = port > p.Lo && port < p.Hi
+	case "<>":
+		ok = port < p.Lo || port > p.Hi
+	default:
+		return MatchMaybe
+	}
+	if ok {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// matchProto matches a rule's protocol against a packet's, which may
+// carry a "6" suffix.
+func matchProto(ruleProto, proto string) Match {
+	proto = strings.TrimSuffix(proto, "6")
+	switch {
+	case ruleProto == "" || ruleProto == proto:
+		return MatchYes
+	case strings.HasPrefix(ruleProto, "{"):
+		return MatchMaybe
+	}
+	return MatchNo
+}
+
+// matchIface matches a rule's interface, which may be negated, against
+// iface. An empty iface stands for any interface.
+func matchIface(ruleIface, iface string) Match {
+	if ruleIface == "" {
+		return MatchYes
+	}
+	if iface == "" {
+		return MatchMaybe
+	}
+	name, not := strings.CutPrefix(ruleIface, "! ")
+	if (name == iface) != not {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// matchCtState matches the conntrack states a netfilter rule requires
+// against the first packet of a connection, which is in state new.
+func matchCtState(states string) Match {
+	if states == "" {
+		return MatchYes
+	}
+	for _, s := range strings.Split(states, ",") {
+		if s == "new" {
+			return MatchYes
+		}
+	}
+	return MatchNo
+}
+
+// maxAnchorDepth bounds how deeply evaluation follows nested anchors.
+const maxAnchorDepth = 16
+
+// evaluate folds rule matches into a verdict the way pf does: the filter
+// rules are walked in order, descending into anchors where an anchor rule
+// matches; the last matching rule decides, unless a quick rule matches
+// first, and traffic no rule matches passes. match says how each rule
+// applies; a rule that may match changes the verdict to unknown unless it
+// would not change it. A quick rule that may match settles the traffic it
+// covers, and evaluation goes on for the rest: the verdict is known only
+// if both end up the same. Netfilter rules are walked from the base
+// chains of the hook dir, in or out, goes through instead; see
+// runChains. It returns the verdict and the Ref of the deciding rule, or
+// "" for the default, and calls step, if not nil, for each rule
+// considered.
+func evaluate(rules []FirewallRule, dir string, match func(FirewallRule) (Match, string), step func(TraceStep)) (Verdict, string) {
+	e := &evaluator{
+		rules:   make(map[string][]FirewallRule),
+		match:   match,
+		step:    step,
+		verdict: VerdictAllowed,
+	}
+	for _, r := range rules {
+		if r.Ruleset == "nat" {
+			continue
+		}
+		if _, ok := e.rules[r.Anchor]; !ok && r.Anchor != "" {
+			e.anchors = append(e.anchors, r.Anchor)
+		}
+		e.rules[r.Anchor] = append(e.rules[r.Anchor], r)
+	}
+	if chains, ok := baseChains(rules, dir); ok {
+		return e.runChains(chains)
+	}
+	e.run("", 0)
+	return e.result()
+}
+
+// baseChains lists the netfilter filter chains hooked where traffic in
+// direction dir passes, in the order they run. It reports false for pf
+// rules, which have no hooks.
+func baseChains(rules []FirewallRule, dir string) ([]string, bool) {
+	hook := "input"
+	if dir == "out" {
+		hook = "output"
+	}
+	netfilter := false
+	seen := make(map[string]bool)
+	var chains []FirewallRule
+	for _, r := range rules {
+		if r.Hook == "" {
+			continue
+		}
+		netfilter = true
+		if r.Hook == hook && r.Ruleset == "filter" && !seen[r.Anchor] {
+			seen[r.Anchor] = true
+			chains = append(chains, r)
+		}
+	}
+	sort.SliceStable(chains, func(i, j int) bool { return chains[i].Priority < chains[j].Priority })
+	names := make([]string, len(chains))
+	for i, c := range chains {
+		names[i] = c.Anchor
+	}
+	return names, netfilter
+}
+
+// runChains walks netfilter base chains in priority order. An accept only
+// ends the chain it is in, while a drop is final, so traffic is allowed
+// when every chain accepts it and blocked as soon as one drops it.
+func (e *evaluator) runChains(chains []string) (Verdict, string) {
+	verdict, ref := VerdictAllowed, ""
+	for _, c := range chains {
+		e.verdict, e.ref, e.branches = VerdictAllowed, "", nil
+		e.run(c, 0)
+		v, r := e.result()
+		switch {
+		case v == VerdictBlocked:
+			return v, r
+		case verdict == VerdictAllowed:
+			verdict, ref = v, r
+		}
+	}
+	return verdict, ref
+}
+
+// result returns the verdict of the rules walked, unknown if traffic that
+// a quick rule which may match stopped evaluation for ends up with
+// another.
+func (e *evaluator) result() (Verdict, string) {
+	for _, b := range e.branches {
+		if b.verdict != e.verdict {
+			return VerdictUnknown, b.ref
+		}
+	}
+	return e.verdict, e.ref
+}
+
+// evaluator holds the state of one evaluate call.
+type evaluator struct {
+	rules   map[string][]FirewallRule // filter rules by anchor path
+	anchors []string                  // anchor paths with rules, in listing order
+	match   func(FirewallRule) (Match, string)
+	step    func(TraceStep)
+	verdict Verdict
+	ref     string
+	matches int // rules that matched so far
+
+	// Verdicts of the traffic that quick rules which may match stopped
+	// evaluation for, while it went on for the traffic they do not cover.
+	branches []branch
+}
+
+// branch is the verdict a quick rule that may match sets on the traffic
+// it covers.
+type branch struct {
+	verdict Verdict
+	ref     string
+}
+
+// run walks the rules of an anchor, "" for the main ruleset. It returns
+// true when a quick rule stopped evaluation. A netfilter chain is left at
+// a matching return rule, or after a goto, for its policy if it has one.
+func (e *evaluator) run(anchor string, depth int) bool {
+	returned := false
+	for _, r := range e.rules[anchor] {
+		switch {
+		case returned && !r.Policy:
+			continue
+		case r.Action == "anchor" || r.Action == "jump" || r.Action == "goto":
+			stop, entered := e.enter(r, depth)
+			if stop {
+				return true
+			}
+			returned = entered && r.Action == "goto"
+		case r.Action == "return":
+			m, reason := e.match(r)
+			switch m {
+			case MatchYes:
+				returned = true
+			case MatchMaybe:
+				// What becomes of the traffic returning is not followed.
+				e.branches = append(e.branches, branch{verdict: VerdictUnknown, ref: r.Ref()})
+			}
+			e.record(r, m, reason)
+		case !r.Decides():
+			e.record(r, MatchNo, r.Action+" rules do not decide the verdict")
+		default:
+			if e.decide(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decide applies a pass or block rule.
+func (e *evaluator) decide(r FirewallRule) bool {
+	v := VerdictAllowed
+	if r.Action == "block" {
+		v = VerdictBlocked
+	}
+	m, reason := e.match(r)
+	switch {
+	case m == MatchYes:
+		e.verdict, e.ref = v, r.Ref()
+		e.matches++
+	case m == MatchMaybe && r.Quick:
+		e.branches = append(e.branches, branch{verdict: v, ref: r.Ref()})
+		reason += "; quick, evaluation stops where it matches"
+	case m == MatchMaybe && v != e.verdict:
+		e.verdict, e.ref = VerdictUnknown, r.Ref()
+	}
+	stop := m == MatchYes && r.Quick
+	if stop {
+		reason += "; quick, evaluation stops"
+	}
+	e.record(r, m, reason)
+	return stop
+}
+
+// enter applies an anchor rule, or a netfilter jump or goto, walking the
+// anchors it calls when it matches. A quick anchor rule stops evaluation
+// if any rule inside matched. It also reports whether the rule matched
+// all traffic, which a goto then does not return from.
+func (e *evaluator) enter(r FirewallRule, depth int) (stop, entered bool) {
+	m, reason := e.match(r)
+	targets := e.anchorTargets(r.Anchor, r.AnchorCall)
+	what := "anchor "
+	if r.Action != "anchor" {
+		what = "chain "
+	}
+	switch {
+	case m == MatchNo:
+		e.record(r, m, reason)
+		return false, false
+	case len(targets) == 0:
+		e.record(r, m, what+r.AnchorCall+" has no filter rules")
+		return false, m == MatchYes
+	case m == MatchMaybe || depth >= maxAnchorDepth:
+		// The anchor applies to only some traffic, so its rules could
+		// decide for some and not others. A netfilter chain settles the
+		// traffic it sees, which later rules cannot change.
+		e.record(r, MatchMaybe, reason+"; "+what+"not evaluated")
+		if r.Action == "anchor" {
+			e.verdict, e.ref = VerdictUnknown, r.Ref()
+		} else {
+			e.branches = append(e.branches, branch{verdict: VerdictUnknown, ref: r.Ref()})
+		}
+		return false, false
+	}
+	e.record(r, m, "enters "+what+strings.Join(targets, ", "))
+	before := e.matches
+	for _, t := range targets {
+		if e.run(t, depth+1) {
+			return true, true
+		}
+	}
+	return r.Quick && e.matches > before, true
+}
+
+// anchorTargets resolves the anchor called from anchor cur: absolute with
+// a leading "/", otherwise relative to cur, possibly with "../" steps. A
+// trailing "/*" calls each child of that anchor.
+func (e *evaluator) anchorTargets(cur, call string) []string {
+	path := call
+	if strings.HasPrefix(path, "/") {
+		path = strings.TrimPrefix(path, "/")
+	} else {
+		base := cur
+		for strings.HasPrefix(path, "../") {
+			path = strings.TrimPrefix(path, "../")
+			if i := strings.LastIndexByte(base, '/'); i >= 0 {
+				base = base[:i]
+			} else {
+				base = ""
+			}
+		}
+		if base != "" {
+			path = base + "/" + path
+		}
+	}
+
+	parent, wildcard := strings.CutSuffix(path, "/*")
+	if !wildcard {
+		if _, ok := e.rules[path]; ok {
+			return []string{path}
+		}
+		return nil
+	}
+	var targets []string
+	for _, a := range e.anchors {
+		if i := strings.LastIndexByte(a, '/'); (i < 0 && parent == "") || (i >= 0 && a[:i] == parent) {
+			targets = append(targets, a)
+		}
+	}
+	return targets
+}
+
+func (e *evaluator) record(r FirewallRule, m Match, reason string) {
+	if e.step != nil {
+		e.step(TraceStep{Rule: r, Match: m, Reason: reason})
+	}
+}
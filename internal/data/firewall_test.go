@@ -0,0 +1,63 @@
+package data
+
+import "testing"
+
+func TestInboundVerdictQuick(t *testing.T) {
+	l := Listener{Proto: "tcp", Port: 22, Addrs: []string{"0.0.0.0"}}
+	rule := func(num int, action string, quick bool, from string) FirewallRule {
+		return FirewallRule{RuleNum: num, Action: action, Direction: "in", Quick: quick, From: AddrSpec{Addr: from}}
+	}
+	tests := []struct {
+		name     string
+		rules    []FirewallRule
+		want     Verdict
+		wantRule string
+	}{
+		{
+			"maybe quick block, then pass in all",
+			[]FirewallRule{rule(0, "block", true, "10.0.0.0/8"), rule(1, "pass", false, "")},
+			VerdictUnknown, "@0",
+		},
+		{
+			"maybe quick pass, then pass in all",
+			[]FirewallRule{rule(0, "pass", true, "10.0.0.0/8"), rule(1, "pass", false, "")},
+			VerdictAllowed, "@1",
+		},
+		{
+			"maybe quick block, then block in all",
+			[]FirewallRule{rule(0, "block", true, "10.0.0.0/8"), rule(1, "block", false, "")},
+			VerdictBlocked, "@1",
+		},
+		{
+			"maybe quick pass, then quick block in all",
+			[]FirewallRule{rule(0, "pass", true, "10.0.0.0/8"), rule(1, "block", true, ""), rule(2, "pass", false, "")},
+			VerdictUnknown, "@0",
+		},
+		{
+			"maybe quick pass, default pass",
+			[]FirewallRule{rule(0, "pass", true, "10.0.0.0/8")},
+			VerdictAllowed, "",
+		},
+		{
+			"maybe quick block, default pass",
+			[]FirewallRule{rule(0, "block", true, "10.0.0.0/8")},
+			VerdictUnknown, "@0",
+		},
+		{
+			"maybe block overridden by pass in all",
+			[]FirewallRule{rule(0, "block", false, "10.0.0.0/8"), rule(1, "pass", false, "")},
+			VerdictAllowed, "@1",
+		},
+		{
+			"quick block in all stops evaluation",
+			[]FirewallRule{rule(0, "block", true, ""), rule(1, "pass", false, "")},
+			VerdictBlocked, "@0",
+		},
+	}
+	for _, tt := range tests {
+		got, ref := InboundVerdict(tt.rules, l)
+		if got != tt.want || ref != tt.wantRule {
+			t.Errorf("%s: %s by %q, want %s by %q", tt.name, got, ref, tt.want, tt.wantRule)
+		}
+	}
+}
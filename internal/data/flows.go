@@ -0,0 +1,48 @@
+package data
+
+import "time"
+
+// Open reports whether the flow has not closed yet.
+func (f Flow) Open() bool {
+	return f.End.IsZero()
+}
+
+// Duration returns how long the flow has been or was open as of now, or
+// 0 if its start is unknown.
+func (f Flow) Duration(now time.Time) time.Duration {
+	if f.Start.IsZero() {
+		return 0
+	}
+	end := f.End
+	if end.IsZero() {
+		end = now
+	}
+	return end.Sub(f.Start)
+}
+
+// Packets returns the packets seen in both directions.
+func (f Flow) Packets() uint64 {
+	return f.OrigPackets + f.ReplyPackets
+}
+
+// Bytes returns the bytes seen in both directions.
+func (f Flow) Bytes() uint64 {
+	return f.OrigBytes + f.ReplyBytes
+}
+
+// AttributeFlows sets the owning process of each flow that has none from
+// the socket it belongs to among sockets, matched as MatchStateSockets
+// does.
+func AttributeFlows(flows []*Flow, sockets []Socket, ifaces []Interface) {
+	states := make([]FirewallState, len(flows))
+	for i, f := range flows {
+		states[i] = FirewallState{Proto: f.Proto, Orig: f.Orig, Reply: f.Reply, Namespace: f.Namespace}
+	}
+	MatchStateSockets(states, sockets, ifaces)
+	for i, st := range states {
+		if st.Socket != nil && st.Socket.PID > 0 {
+			flows[i].PID = st.Socket.PID
+			flows[i].Process = st.Socket.Process
+		}
+	}
+}
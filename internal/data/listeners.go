@@ -0,0 +1,196 @@
+package data
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// Exposure classifies which networks a listening service is reachable from.
+type Exposure int
+
+const (
+	ExposureLoopback Exposure = iota // bound to loopback addresses only
+	ExposureSpecific                 // bound to specific non-loopback addresses
+	ExposureAll                      // bound to the wildcard address
+)
+
+// String returns the exposure as shown in the Listeners tab.
+func (e Exposure) String() string {
+	switch e {
+	case ExposureLoopback:
+		return "loopback"
+	case ExposureSpecific:
+		return "specific"
+	case ExposureAll:
+		return "all interfaces"
+	default:
+		return "unknown"
+	}
+}
+
+// Listener is a listening service: the TCP sockets in LISTEN state and
+// unconnected UDP sockets on one port, across address families, bind
+// addresses and worker processes.
+type Listener struct {
+	Proto       string // tcp or udp
+	Port        uint32
+	Addrs       []string // bind addresses, sorted
+	Ifaces      []string // interfaces owning the specific bind addresses
+	Exposure    Exposure
+	Verdict     Verdict // firewall verdict on inbound traffic
+	VerdictRule string  // Ref of the deciding rule, or ""
+	PIDs        []int32 // owning processes, sorted
+	Process     string  // name of the first owning process
+	Unit        string  // systemd unit of the first owning process
+	Container   string  // container of the first owning process
+	Namespace   string
+	Sockets     []Socket
+}
+
+// IsListening reports whether a socket accepts inbound traffic: a TCP
+// socket in LISTEN state or a UDP socket not connected to a peer.
+func (s Socket) IsListening() bool {
+	switch {
+	case strings.HasPrefix(s.Proto, "tcp"):
+		return s.State == "LISTEN"
+	case strings.HasPrefix(s.Proto, "udp"):
+		return s.RemotePort == 0
+	}
+	return false
+}
+
+// addrExposure classifies a single bind address.
+func addrExposure(addr string) Exposure {
+	if addr == "" || addr == "*" {
+		return ExposureAll
+	}
+	ip := net.ParseIP(stripZone(addr))
+	switch {
+	case ip == nil:
+		return ExposureSpecific
+	case ip.IsUnspecified():
+		return ExposureAll
+	case ip.IsLoopback():
+		return ExposureLoopback
+	}
+	return ExposureSpecific
+}
+
+func stripZone(addr string) string {
+	if i := strings.IndexByte(addr, '%'); i >= 0 {
+		return addr[:i]
+	}
+	return addr
+}
+
+// BuildListeners groups the listening sockets into one Listener per
+// namespace, protocol and port, ordered the same way. Owning processes
+// are looked up in procs, and specific bind addresses in ifaces.
+func BuildListeners(sockets []Socket, procs map[int32]*Process, ifaces []Interface) []Listener {
+	type addrKey struct{ ns, ip string }
+	ifaceByAddr := make(map[addrKey]string)
+	for _, iface := range ifaces {
+		for _, a := range iface.Addresses {
+			ifaceByAddr[addrKey{iface.Namespace, a.IP}] = iface.Name
+		}
+		for _, a := range iface.Addrs {
+			ip, _, _ := strings.Cut(a, "/")
+			if _, ok := ifaceByAddr[addrKey{iface.Namespace, ip}]; !ok {
+				ifaceByAddr[addrKey{iface.Namespace, ip}] = iface.Name
+			}
+		}
+	}
+
+	type key struct {
+		ns    string
+		proto string
+		port  uint32
+	}
+	byKey := make(map[key]*Listener)
+	var keys []key
+	for _, s := range sockets {
+		if !s.IsListening() {
+			continue
+		}
+		k := key{ns: s.Namespace, proto: strings.TrimSuffix(s.Proto, "6"), port: s.LocalPort}
+		l, ok := byKey[k]
+		if !ok {
+			l = &Listener{Proto: k.proto, Port: k.port, Namespace: k.ns}
+			byKey[k] = l
+			keys = append(keys, k)
+		}
+		l.Sockets = append(l.Sockets, s)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.ns != b.ns {
+			return a.ns < b.ns
+		}
+		if a.port != b.port {
+			return a.port < b.port
+		}
+		return a.proto < b.proto
+	})
+
+	listeners := make([]Listener, 0, len(keys))
+	for _, k := range keys {
+		l := byKey[k]
+		addrs := make(map[string]bool)
+		ifaceNames := make(map[string]bool)
+		pids := make(map[int32]bool)
+		for _, s := range l.Sockets {
+			addr := s.LocalAddr
+			if addr == "" {
+				addr = "*"
+			}
+			addrs[addr] = true
+			e := addrExposure(s.LocalAddr)
+			if e > l.Exposure {
+				l.Exposure = e
+			}
+			if e == ExposureSpecific {
+				if name, ok := ifaceByAddr[addrKey{k.ns, stripZone(s.LocalAddr)}]; ok {
+					ifaceNames[name] = true
+				}
+			}
+			if s.PID > 0 {
+				pids[s.PID] = true
+			}
+		}
+		l.Addrs = sortedKeys(addrs)
+		l.Ifaces = sortedKeys(ifaceNames)
+		for pid := range pids {
+			l.PIDs = append(l.PIDs, pid)
+		}
+		sort.Slice(l.PIDs, func(i, j int) bool { return l.PIDs[i] < l.PIDs[j] })
+
+		if len(l.PIDs) > 0 {
+			if p, ok := procs[l.PIDs[0]]; ok {
+				l.Process = p.Name
+				l.Unit = p.Unit
+				l.Container = p.Container.String()
+			}
+		}
+		if l.Process == "" {
+			for _, s := range l.Sockets {
+				if s.Process != "" {
+					l.Process = s.Process
+					break
+				}
+			}
+		}
+		listeners = append(listeners, *l)
+	}
+	return listeners
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
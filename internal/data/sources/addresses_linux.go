@@ -0,0 +1,112 @@
+//go:build linux
+
+package sources
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ifaFlagNames lists the IFA_F_* address flags worth showing, in display order.
+var ifaFlagNames = []struct {
+	flag int
+	name string
+}{
+	{unix.IFA_F_PERMANENT, "permanent"},
+	{unix.IFA_F_TENTATIVE, "tentative"},
+	{unix.IFA_F_DEPRECATED, "deprecated"},
+	{unix.IFA_F_DADFAILED, "dadfailed"},
+	{unix.IFA_F_OPTIMISTIC, "optimistic"},
+	{unix.IFA_F_NODAD, "nodad"},
+	{unix.IFA_F_HOMEADDRESS, "home"},
+	{unix.IFA_F_MANAGETEMPADDR, "mngtmpaddr"},
+	{unix.IFA_F_NOPREFIXROUTE, "noprefixroute"},
+	{unix.IFA_F_STABLE_PRIVACY, "stable-privacy"},
+}
+
+// collectAddresses replaces each interface's addresses with the full
+// rtnetlink view: scope, broadcast, IFA_F_* flags and lifetimes.
+func collectAddresses(ifaces []data.Interface) []data.CollectionError {
+	addrs, err := netlink.AddrList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return []data.CollectionError{{Source: "addresses", Error: fmt.Sprintf("AddrList(): %v", err)}}
+	}
+
+	byIndex := make(map[int][]data.Address)
+	for _, a := range addrs {
+		if a.IPNet == nil {
+			continue
+		}
+		ones, _ := a.Mask.Size()
+		da := data.Address{
+			IP:                a.IP.String(),
+			Family:            addressFamily(a.IP),
+			PrefixLen:         ones,
+			Scope:             rtScopeName(a.Scope),
+			Flags:             ifaFlags(a.Flags, a.IP.To4() != nil),
+			ValidLifetime:     addrLifetime(a.ValidLft),
+			PreferredLifetime: addrLifetime(a.PreferedLft),
+		}
+		if a.Broadcast != nil {
+			da.Broadcast = a.Broadcast.String()
+		}
+		byIndex[a.LinkIndex] = append(byIndex[a.LinkIndex], da)
+	}
+
+	for i := range ifaces {
+		if list, ok := byIndex[ifaces[i].Index]; ok {
+			ifaces[i].Addresses = list
+		}
+	}
+	return nil
+}
+
+func ifaFlags(flags int, v4 bool) []string {
+	var names []string
+	// IFA_F_SECONDARY and IFA_F_TEMPORARY share a bit; which one it means
+	// depends on the family.
+	if flags&unix.IFA_F_SECONDARY != 0 {
+		if v4 {
+			names = append(names, "secondary")
+		} else {
+			names = append(names, "temporary")
+		}
+	}
+	for _, f := range ifaFlagNames {
+		if flags&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+func rtScopeName(scope int) string {
+	switch scope {
+	case unix.RT_SCOPE_UNIVERSE:
+		return "global"
+	case unix.RT_SCOPE_SITE:
+		return "site"
+	case unix.RT_SCOPE_LINK:
+		return "link"
+	case unix.RT_SCOPE_HOST:
+		return "host"
+	case unix.RT_SCOPE_NOWHERE:
+		return "nowhere"
+	default:
+		return fmt.Sprintf("%d", scope)
+	}
+}
+
+// addrLifetime converts an IFA_CACHEINFO lifetime in seconds, where
+// all ones means infinite.
+func addrLifetime(secs int) time.Duration {
+	if secs < 0 || uint64(secs) >= math.MaxUint32 {
+		return data.Forever
+	}
+	return time.Duration(secs) * time.Second
+}
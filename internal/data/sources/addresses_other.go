@@ -0,0 +1,11 @@
+//go:build !linux
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// collectAddresses is a no-op outside Linux. On Darwin the IPv6 flags and
+// lifetimes come from the same ifconfig pass as the link details.
+func collectAddresses(ifaces []data.Interface) []data.CollectionError {
+	return nil
+}
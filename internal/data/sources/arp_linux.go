@@ -0,0 +1,71 @@
+//go:build linux
+
+package sources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+)
+
+// neighStates names the NUD_* neighbor states, in display order.
+var neighStates = []struct {
+	bit  int
+	name string
+}{
+	{netlink.NUD_INCOMPLETE, "incomplete"},
+	{netlink.NUD_REACHABLE, "reachable"},
+	{netlink.NUD_STALE, "stale"},
+	{netlink.NUD_DELAY, "delay"},
+	{netlink.NUD_PROBE, "probe"},
+	{netlink.NUD_FAILED, "failed"},
+	{netlink.NUD_NOARP, "noarp"},
+	{netlink.NUD_PERMANENT, "permanent"},
+}
+
+// CollectARP reads the IPv4 ARP and IPv6 neighbor tables over rtnetlink.
+func CollectARP() ([]data.ARPEntry, []data.CollectionError) {
+	neighs, err := netlink.NeighList(0, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "arp", Error: fmt.Sprintf("NeighList(): %v", err)}}
+	}
+
+	links, _ := netlink.LinkList()
+	linkByIndex := make(map[int]*netlink.LinkAttrs, len(links))
+	for _, l := range links {
+		linkByIndex[l.Attrs().Index] = l.Attrs()
+	}
+
+	entries := make([]data.ARPEntry, 0, len(neighs))
+	for _, n := range neighs {
+		if n.IP == nil {
+			continue
+		}
+		entry := data.ARPEntry{
+			IP:    n.IP.String(),
+			MAC:   "(incomplete)",
+			Flags: neighStateString(n.State),
+		}
+		if len(n.HardwareAddr) > 0 {
+			entry.MAC = n.HardwareAddr.String()
+		}
+		if attrs, ok := linkByIndex[n.LinkIndex]; ok {
+			entry.Interface = attrs.Name
+			entry.Type = attrs.EncapType
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func neighStateString(state int) string {
+	var names []string
+	for _, s := range neighStates {
+		if state&s.bit != 0 {
+			names = append(names, s.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
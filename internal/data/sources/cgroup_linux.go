@@ -0,0 +1,261 @@
+//go:build linux
+
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// cgroupContainerRes match the cgroup path components container runtimes
+// create, for both the systemd and cgroupfs cgroup drivers. The first
+// match wins, so Kubernetes runtimes come before the generic patterns.
+var cgroupContainerRes = []struct {
+	runtime string
+	re      *regexp.Regexp
+}{
+	{"containerd", regexp.MustCompile(`cri-containerd-([0-9a-f]{64})\.scope`)},
+	{"cri-o", regexp.MustCompile(`crio-([0-9a-f]{64})\.scope`)},
+	{"docker", regexp.MustCompile(`docker-([0-9a-f]{64})\.scope`)},
+	{"docker", regexp.MustCompile(`/docker/([0-9a-f]{64})`)},
+	{"podman", regexp.MustCompile(`libpod-([0-9a-f]{64})\.scope`)},
+	{"podman", regexp.MustCompile(`/libpod-([0-9a-f]{64})`)},
+	{"kubernetes", regexp.MustCompile(`/kubepods/(?:[^/]+/)?pod[0-9a-f-]{36}/([0-9a-f]{64})`)},
+	{"containerd", regexp.MustCompile(`nerdctl-([0-9a-f]{64})\.scope`)},
+	{"systemd-nspawn", regexp.MustCompile(`systemd-nspawn@([^/]+)\.service`)},
+	{"systemd-nspawn", regexp.MustCompile(`machine-([^/]+)\.scope`)},
+	{"lxc", regexp.MustCompile(`/lxc\.payload\.([^/]+)`)},
+	{"lxc", regexp.MustCompile(`/lxc/([^/]+)`)},
+}
+
+// podUIDRe matches the pod slice or directory Kubernetes creates, with the
+// UID's dashes turned into underscores under the systemd driver.
+var podUIDRe = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
+// parseCgroupContainer finds the container in the contents of
+// /proc/<pid>/cgroup. The cgroup v2 line is preferred; on v1 hosts any
+// hierarchy will do, since runtimes place a container in all of them.
+func parseCgroupContainer(cgroup string) data.Container {
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(cgroup), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			paths = append([]string{parts[2]}, paths...)
+		} else {
+			paths = append(paths, parts[2])
+		}
+	}
+
+	for _, path := range paths {
+		var c data.Container
+		if m := podUIDRe.FindStringSubmatch(path); m != nil {
+			c.Runtime = "kubernetes"
+			c.PodUID = strings.ReplaceAll(m[1], "_", "-")
+		}
+		for _, r := range cgroupContainerRes {
+			if m := r.re.FindStringSubmatch(path); m != nil {
+				c.Runtime = r.runtime
+				c.ID = unescapeUnitName(m[1])
+				break
+			}
+		}
+		if c.Runtime != "" {
+			return c
+		}
+	}
+	return data.Container{}
+}
+
+// unescapeUnitName undoes systemd's \xNN escaping of unit names, which
+// machined applies to container names (e.g. "web\x2d1" for "web-1").
+func unescapeUnitName(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i+4 <= len(s) && s[i] == '\\' && s[i+1] == 'x' {
+			if c, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// systemdCgroupPath returns the cgroup path systemd manages in the
+// contents of /proc/<pid>/cgroup: the cgroup v2 path, or the name=systemd
+// hierarchy on v1 hosts.
+func systemdCgroupPath(cgroup string) string {
+	var v1 string
+	for _, line := range strings.Split(strings.TrimSpace(cgroup), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "" && parts[2] != "/":
+			return parts[2]
+		case parts[1] == "name=systemd":
+			v1 = parts[2]
+		}
+	}
+	return v1
+}
+
+// unitTypes are the systemd unit types that own processes.
+var unitTypes = []string{".service", ".scope"}
+
+// parseCgroupUnit finds the innermost systemd unit in a cgroup path and the
+// slice containing it, e.g. "nginx.service" in "system.slice". Processes of
+// a user's services resolve to the unit under their user@.service.
+func parseCgroupUnit(path string) (unit, slice string) {
+	var parent string
+	for _, part := range strings.Split(path, "/") {
+		switch {
+		case strings.HasSuffix(part, ".slice"):
+			parent = unescapeUnitName(part)
+		case hasUnitType(part):
+			unit, slice = unescapeUnitName(part), parent
+		}
+	}
+	return unit, slice
+}
+
+func hasUnitType(name string) bool {
+	for _, t := range unitTypes {
+		if strings.HasSuffix(name, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// cgroupMissTTL is how long a failed container name lookup is remembered
+// before it is retried, as a container's metadata may not be written yet
+// when its first process appears.
+const cgroupMissTTL = 30 * time.Second
+
+// cgroupCache attributes processes to containers and systemd units, and
+// remembers the container names found in runtime state, so each running
+// container's on-disk metadata is read once.
+type cgroupCache struct {
+	names map[string]cgroupName // by container ID
+}
+
+// cgroupName is a cached container name lookup.
+type cgroupName struct {
+	name string // "" if the lookup failed
+	at   time.Time
+}
+
+func newCgroupCache() *cgroupCache {
+	return &cgroupCache{names: make(map[string]cgroupName)}
+}
+
+// attribute fills the cgroup, unit and container of each process from
+// /proc/<pid>/cgroup, then forgets the names of containers none of procs
+// belongs to.
+func (cc *cgroupCache) attribute(procs []data.Process) {
+	now := time.Now()
+	seen := make(map[string]bool)
+	for i := range procs {
+		raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", procs[i].PID))
+		if err != nil {
+			continue
+		}
+		c := parseCgroupContainer(string(raw))
+		if c.ID != "" {
+			c.Name = cc.name(c, now)
+			seen[c.ID] = true
+		}
+		procs[i].Container = c
+		procs[i].Cgroup = systemdCgroupPath(string(raw))
+		procs[i].Unit, procs[i].Slice = parseCgroupUnit(procs[i].Cgroup)
+	}
+	if len(procs) == 0 {
+		// A failed process scan says nothing about which containers exited.
+		return
+	}
+	for id := range cc.names {
+		if !seen[id] {
+			delete(cc.names, id)
+		}
+	}
+}
+
+// name returns the name of container c, looking it up in its runtime's
+// state unless a recent lookup is cached.
+func (cc *cgroupCache) name(c data.Container, now time.Time) string {
+	if e, ok := cc.names[c.ID]; ok && (e.name != "" || now.Sub(e.at) < cgroupMissTTL) {
+		return e.name
+	}
+	var name string
+	switch c.Runtime {
+	case "docker":
+		name = dockerContainerName(c.ID)
+	case "podman", "cri-o":
+		name = storageContainerName(c.ID)
+	}
+	cc.names[c.ID] = cgroupName{name: name, at: now}
+	return name
+}
+
+// dockerContainerName reads the name Docker records in the container's
+// config.v2.json.
+func dockerContainerName(id string) string {
+	raw, err := os.ReadFile(filepath.Join("/var/lib/docker/containers", id, "config.v2.json"))
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		Name string
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(cfg.Name, "/")
+}
+
+// storageContainerName looks the container up in the containers/storage
+// index shared by podman and CRI-O, checking the rootful store and then
+// the caller's rootless one.
+func storageContainerName(id string) string {
+	stores := []string{"/var/lib/containers/storage"}
+	if home, err := os.UserHomeDir(); err == nil {
+		stores = append(stores, filepath.Join(home, ".local/share/containers/storage"))
+	}
+	for _, store := range stores {
+		raw, err := os.ReadFile(filepath.Join(store, "overlay-containers", "containers.json"))
+		if err != nil {
+			continue
+		}
+		var containers []struct {
+			ID    string   `json:"id"`
+			Names []string `json:"names"`
+		}
+		if err := json.Unmarshal(raw, &containers); err != nil {
+			continue
+		}
+		for _, c := range containers {
+			if c.ID == id && len(c.Names) > 0 {
+				return c.Names[0]
+			}
+		}
+	}
+	return ""
+}
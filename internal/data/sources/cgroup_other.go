@@ -0,0 +1,15 @@
+//go:build !linux
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// cgroupCache is a no-op outside Linux, where processes are not
+// attributed to containers or systemd units.
+type cgroupCache struct{}
+
+func newCgroupCache() *cgroupCache {
+	return &cgroupCache{}
+}
+
+func (cc *cgroupCache) attribute(procs []data.Process) {}
@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -13,6 +14,12 @@ type Collector struct {
 	isRoot     bool
 	throughput *ThroughputCalculator
 	dns        *DNSCache
+	procs      *ProcessCache
+	cgroups    *cgroupCache
+	cpu        *cpuSampler
+	ruleRates  *ruleRateSampler
+	flows      *flowRecorder
+	netns      string // "" for nettui's own namespace, AllNamespaces, or a name
 }
 
 // NewCollector creates a new Collector.
@@ -21,6 +28,11 @@ func NewCollector() *Collector {
 		isRoot:     util.IsRoot(),
 		throughput: NewThroughputCalculator(),
 		dns:        NewDNSCache(),
+		procs:      NewProcessCache(),
+		cgroups:    newCgroupCache(),
+		cpu:        newCPUSampler(),
+		ruleRates:  newRuleRateSampler(),
+		flows:      newFlowRecorder(),
 	}
 }
 
@@ -29,51 +41,61 @@ func (c *Collector) DNSCache() *DNSCache {
 	return c.dns
 }
 
+// SetNamespace selects the network namespace to collect from: "" for
+// nettui's own, data.AllNamespaces for every namespace, or a name from
+// ListNamespaces.
+func (c *Collector) SetNamespace(name string) {
+	c.netns = name
+}
+
 // Collect gathers data from all sources and returns a CollectionResult.
 func (c *Collector) Collect() data.CollectionResult {
 	result := data.CollectionResult{
 		Timestamp: time.Now(),
 		IsRoot:    c.isRoot,
+		NetNS:     c.netns,
 	}
 
-	// Interfaces + IO counters.
-	ifaces, errs := CollectInterfaces()
-	result.Interfaces = ifaces
+	namespaces, errs := ListNamespaces()
+	result.Namespaces = namespaces
 	result.Errors = append(result.Errors, errs...)
+	c.flows.prune(namespaces)
+
+	// Interfaces, routes, sockets, firewall rules and states, flows and ARP
+	// live in a namespace.
+	if c.netns == "" {
+		c.collectNet(context.Background(), namespaces, &result)
+	} else {
+		c.collectNamespaces(namespaces, &result)
+	}
+
+	// Rule hit rates from firewall counters.
+	c.ruleRates.apply(result.Firewall, time.Now())
 
 	// Calculate throughput from interface counters.
-	throughputs := c.throughput.Calculate(result.Interfaces)
+	throughputs := c.throughput.Calculate(result.Interfaces, namespaces)
 	result.Throughputs = throughputs
 
 	// Apply throughput rates back to interfaces.
 	for i := range result.Interfaces {
-		if tp, ok := throughputs[result.Interfaces[i].Name]; ok {
-			result.Interfaces[i].TxRate = tp.TxRate
-			result.Interfaces[i].RxRate = tp.RxRate
+		if tp, ok := throughputs[result.Interfaces[i].Key()]; ok {
+			applyThroughput(&result.Interfaces[i], tp)
 		}
 	}
 
-	// Routes.
-	routes, errs := c.collectRoutes()
-	result.Routes = routes
+	// Unix sockets.
+	unixSockets, errs := CollectLsofUnix()
+	result.UnixSockets = unixSockets
 	result.Errors = append(result.Errors, errs...)
 
-	// Connections.
-	sockets, errs := CollectConnections()
-	result.Sockets = sockets
-	result.Errors = append(result.Errors, errs...)
-
-	// Lsof: enrich sockets with PID/process info and get unix sockets.
-	lsofResult, errs := CollectLsof()
-	result.Errors = append(result.Errors, errs...)
-	if lsofResult != nil {
-		EnrichSockets(result.Sockets, lsofResult)
-		result.UnixSockets = lsofResult.UnixSockets
-	}
-
 	// Processes.
-	procs, errs := CollectProcesses()
+	procs, errs := c.procs.Collect()
 	result.Errors = append(result.Errors, errs...)
+	c.cpu.apply(procs, time.Now())
+	if c.netns != "" {
+		procs = scopeProcesses(procs, namespaces, c.netns)
+	}
+	c.cgroups.attribute(procs)
 
 	// Enrich processes with connection counts from sockets.
 	pidConns := make(map[int32]int)
@@ -94,20 +116,164 @@ func (c *Collector) Collect() data.CollectionResult {
 	}
 	result.Processes = procs
 
+	// Trigger async DNS resolution for unique remote addresses.
+	c.triggerDNS(result.Sockets)
+
+	return result
+}
+
+// collectNet gathers the per-namespace sources into result, appending so
+// several namespaces can share one result. namespaces are those listed
+// this refresh.
+func (c *Collector) collectNet(ctx context.Context, namespaces []data.Namespace, result *data.CollectionResult) {
+	// Interfaces + IO counters.
+	ifaces, errs := CollectInterfaces(ctx, namespaces)
+	result.Interfaces = append(result.Interfaces, ifaces...)
+	result.Errors = append(result.Errors, errs...)
+
+	// Routes.
+	routes, errs := c.collectRoutes()
+	result.Routes = append(result.Routes, routes...)
+	result.Errors = append(result.Errors, errs...)
+
+	// Connections.
+	sockets, errs := CollectConnections(ctx)
+	result.Errors = append(result.Errors, errs...)
+
+	// Lsof: enrich sockets with PID/process info.
+	lsofResult, errs := CollectLsofInet()
+	result.Errors = append(result.Errors, errs...)
+	EnrichSockets(sockets, lsofResult)
+	result.Sockets = append(result.Sockets, sockets...)
+
 	// Firewall (requires root).
-	fwRules, errs := CollectFirewall(c.isRoot)
-	result.Firewall = fwRules
+	fwRules, fwTables, errs := CollectFirewall(c.isRoot)
+	result.Firewall = append(result.Firewall, fwRules...)
+	result.FirewallTables = append(result.FirewallTables, fwTables...)
+	result.Errors = append(result.Errors, errs...)
+
+	// Firewall state table (requires root).
+	states, errs := CollectStates(c.isRoot)
+	result.FirewallStates = append(result.FirewallStates, states...)
+	result.Errors = append(result.Errors, errs...)
+
+	// Recent flows from conntrack events (requires root).
+	flows, errs := c.flows.collect(c.isRoot, sockets, ifaces)
+	result.Flows = append(result.Flows, flows...)
 	result.Errors = append(result.Errors, errs...)
 
 	// ARP table.
 	arpEntries, errs := CollectARP()
-	result.ARPEntries = arpEntries
+	result.ARPEntries = append(result.ARPEntries, arpEntries...)
 	result.Errors = append(result.Errors, errs...)
+}
 
-	// Trigger async DNS resolution for unique remote addresses.
-	c.triggerDNS(result.Sockets)
+// collectNamespaces runs collectNet inside each selected namespace and
+// labels what it finds with the namespace's name.
+func (c *Collector) collectNamespaces(namespaces []data.Namespace, result *data.CollectionResult) {
+	found := false
+	for _, ns := range namespaces {
+		if c.netns != data.AllNamespaces && ns.Name != c.netns {
+			continue
+		}
+		found = true
 
-	return result
+		var part data.CollectionResult
+		if ns.Self {
+			c.collectNet(context.Background(), namespaces, &part)
+		} else {
+			ctx := withNamespace(context.Background(), ns)
+			if err := inNamespace(ns.Path, func() { c.collectNet(ctx, namespaces, &part) }); err != nil {
+				result.Errors = append(result.Errors, data.CollectionError{Source: "netns", Error: fmt.Sprintf("%s: %v", ns.Name, err)})
+				continue
+			}
+		}
+
+		for i := range part.Interfaces {
+			part.Interfaces[i].Namespace = ns.Name
+		}
+		for i := range part.Routes {
+			part.Routes[i].Namespace = ns.Name
+		}
+		for i := range part.Sockets {
+			part.Sockets[i].Namespace = ns.Name
+		}
+		for i := range part.Firewall {
+			part.Firewall[i].Namespace = ns.Name
+		}
+		for i := range part.FirewallTables {
+			part.FirewallTables[i].Namespace = ns.Name
+		}
+		for i := range part.FirewallStates {
+			part.FirewallStates[i].Namespace = ns.Name
+		}
+		for i := range part.Flows {
+			part.Flows[i].Namespace = ns.Name
+		}
+		for i := range part.ARPEntries {
+			part.ARPEntries[i].Namespace = ns.Name
+		}
+		result.Interfaces = append(result.Interfaces, part.Interfaces...)
+		result.Routes = append(result.Routes, part.Routes...)
+		result.Sockets = append(result.Sockets, part.Sockets...)
+		result.Firewall = append(result.Firewall, part.Firewall...)
+		result.FirewallTables = append(result.FirewallTables, part.FirewallTables...)
+		result.FirewallStates = append(result.FirewallStates, part.FirewallStates...)
+		result.Flows = append(result.Flows, part.Flows...)
+		result.ARPEntries = append(result.ARPEntries, part.ARPEntries...)
+		result.Errors = append(result.Errors, part.Errors...)
+	}
+	if !found {
+		result.Errors = append(result.Errors, data.CollectionError{Source: "netns", Error: fmt.Sprintf("namespace %q not found", c.netns)})
+	}
+}
+
+// scopeProcesses labels processes with their network namespace and, when
+// a single namespace is selected, keeps only the processes living in it.
+func scopeProcesses(procs []data.Process, namespaces []data.Namespace, netns string) []data.Process {
+	nsByPID := make(map[int32]string)
+	for _, ns := range namespaces {
+		for _, pid := range ns.PIDs {
+			nsByPID[pid] = ns.Name
+		}
+	}
+	scoped := procs[:0]
+	for _, p := range procs {
+		p.Namespace = nsByPID[p.PID]
+		if netns == data.AllNamespaces || p.Namespace == netns {
+			scoped = append(scoped, p)
+		}
+	}
+	return scoped
+}
+
+// SetSmoothingWindow sets the EWMA window applied to interface rates.
+func (c *Collector) SetSmoothingWindow(window time.Duration) {
+	c.throughput.SetSmoothing(window)
+}
+
+// ResetSessionTotals zeroes the per-interface totals accumulated since start.
+func (c *Collector) ResetSessionTotals() {
+	c.throughput.ResetTotals()
+}
+
+func applyThroughput(iface *data.Interface, tp data.Throughput) {
+	iface.TxRate = tp.TxRate
+	iface.RxRate = tp.RxRate
+	iface.TxPktRate = tp.TxPktRate
+	iface.RxPktRate = tp.RxPktRate
+	iface.ErrInRate = tp.ErrInRate
+	iface.ErrOutRate = tp.ErrOutRate
+	iface.DropInRate = tp.DropInRate
+	iface.DropOutRate = tp.DropOutRate
+	iface.FifoInRate = tp.FifoInRate
+	iface.FifoOutRate = tp.FifoOutRate
+	iface.SessionTxBytes = tp.SessionTxBytes
+	iface.SessionRxBytes = tp.SessionRxBytes
+	iface.SessionTxPkts = tp.SessionTxPkts
+	iface.SessionRxPkts = tp.SessionRxPkts
+	iface.SessionStart = tp.SessionStart
+	iface.History = tp.History
 }
 
 func (c *Collector) collectRoutes() ([]data.Route, []data.CollectionError) {
@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jerryluo/nettui/internal/data"
@@ -8,8 +9,8 @@ import (
 )
 
 // CollectConnections gathers all TCP and UDP connections.
-func CollectConnections() ([]data.Socket, []data.CollectionError) {
-	conns, err := psnet.Connections("all")
+func CollectConnections(ctx context.Context) ([]data.Socket, []data.CollectionError) {
+	conns, err := psnet.ConnectionsWithContext(ctx, "all")
 	if err != nil {
 		return nil, []data.CollectionError{{Source: "connections", Error: fmt.Sprintf("Connections(): %v", err)}}
 	}
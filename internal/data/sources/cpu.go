@@ -0,0 +1,39 @@
+package sources
+
+import (
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// cpuSampler turns the cumulative CPU time of each process into a
+// percentage over the interval since the previous refresh.
+type cpuSampler struct {
+	prev     map[int32]cpuSample
+	prevTime time.Time
+}
+
+type cpuSample struct {
+	start   time.Time // tells a reused PID from the process seen before
+	cpuTime float64
+}
+
+func newCPUSampler() *cpuSampler {
+	return &cpuSampler{prev: make(map[int32]cpuSample)}
+}
+
+// apply sets CPUPercent on each process seen in the previous sample and
+// replaces the sample, dropping processes that have exited.
+func (s *cpuSampler) apply(procs []data.Process, now time.Time) {
+	elapsed := now.Sub(s.prevTime).Seconds()
+	next := make(map[int32]cpuSample, len(procs))
+	for i := range procs {
+		p := &procs[i]
+		if prev, ok := s.prev[p.PID]; ok && elapsed > 0 && prev.start.Equal(p.StartTime) && p.CPUTime >= prev.cpuTime {
+			p.CPUPercent = (p.CPUTime - prev.cpuTime) / elapsed * 100
+		}
+		next[p.PID] = cpuSample{start: p.StartTime, cpuTime: p.CPUTime}
+	}
+	s.prev = next
+	s.prevTime = now
+}
@@ -2,6 +2,7 @@ package sources
 
 import (
 	"fmt"
+	"net"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -17,19 +18,126 @@ var (
 	evalLineRe = regexp.MustCompile(`\[\s*Evaluations:\s*\d+\s+Packets:\s*(\d+)\s+Bytes:\s*(\d+)`)
 )
 
-// CollectFirewall parses pfctl -vsr output to collect firewall rules.
-// Requires root access; returns an error if not root.
-func CollectFirewall(isRoot bool) ([]data.FirewallRule, []data.CollectionError) {
+// collectPF parses pfctl output to collect the filter and NAT rules of the
+// main ruleset and of every anchor, recursively, and the contents of the
+// tables they reference. Requires root access; returns an error if not
+// root.
+func collectPF(isRoot bool) ([]data.FirewallRule, []data.FirewallTable, []data.CollectionError) {
 	if !isRoot {
-		return nil, []data.CollectionError{{Source: "firewall", Error: "pfctl requires root access"}}
+		return nil, nil, []data.CollectionError{{Source: "firewall", Error: "pfctl requires root access"}}
 	}
 
 	out, err := exec.Command("pfctl", "-vsr").CombinedOutput()
 	if err != nil {
-		return nil, []data.CollectionError{{Source: "firewall", Error: fmt.Sprintf("pfctl -vsr: %v: %s", err, string(out))}}
+		return nil, nil, []data.CollectionError{{Source: "firewall", Error: fmt.Sprintf("pfctl -vsr: %v: %s", err, string(out))}}
 	}
+	rules := parsePfctlOutput(string(out))
+	var errs []data.CollectionError
 
-	return parsePfctlOutput(string(out)), nil
+	anchors := []string{""}
+	if out, err := exec.Command("pfctl", "-vsA").CombinedOutput(); err == nil {
+		anchors = append(anchors, parseAnchorList(string(out))...)
+	} else {
+		errs = append(errs, data.CollectionError{Source: "firewall", Error: fmt.Sprintf("pfctl -vsA: %v", err)})
+	}
+
+	var result []data.FirewallRule
+	for _, anchor := range anchors {
+		filter := rules
+		if anchor != "" {
+			out, err := pfctl(anchor, "-vsr")
+			if err != nil {
+				errs = append(errs, data.CollectionError{Source: "firewall", Error: err.Error()})
+				continue
+			}
+			filter = parsePfctlOutput(out)
+		}
+		for i := range filter {
+			filter[i].Anchor = anchor
+		}
+		result = append(result, filter...)
+
+		out, err := pfctl(anchor, "-vsn")
+		if err != nil {
+			errs = append(errs, data.CollectionError{Source: "firewall", Error: err.Error()})
+			continue
+		}
+		nat := parsePfctlOutput(out)
+		for i := range nat {
+			nat[i].Anchor = anchor
+			nat[i].Ruleset = "nat"
+		}
+		result = append(result, nat...)
+	}
+
+	tables, tableErrs := collectTables(result)
+	return result, tables, append(errs, tableErrs...)
+}
+
+// pfctl runs pfctl with args on the given anchor, "" for the main
+// ruleset.
+func pfctl(anchor string, args ...string) (string, error) {
+	if anchor != "" {
+		args = append([]string{"-a", anchor}, args...)
+	}
+	out, err := exec.Command("pfctl", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("pfctl %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// parseAnchorList parses pfctl -vsA output: one anchor path per line,
+// indented by depth, parents before their children.
+func parseAnchorList(output string) []string {
+	var anchors []string
+	for _, line := range strings.Split(output, "\n") {
+		if a := strings.TrimSpace(line); a != "" {
+			anchors = append(anchors, a)
+		}
+	}
+	return anchors
+}
+
+// collectTables lists the addresses of each table the rules reference,
+// looking tables up in the anchor of the referencing rule.
+func collectTables(rules []data.FirewallRule) ([]data.FirewallTable, []data.CollectionError) {
+	type key struct{ anchor, name string }
+	seen := make(map[key]bool)
+	var tables []data.FirewallTable
+	var errs []data.CollectionError
+	for _, r := range rules {
+		for _, name := range r.Tables {
+			k := key{r.Anchor, name}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			out, err := pfctl(r.Anchor, "-t", name, "-T", "show")
+			if err != nil {
+				errs = append(errs, data.CollectionError{Source: "firewall", Error: err.Error()})
+				continue
+			}
+			tables = append(tables, data.FirewallTable{
+				Name:   name,
+				Anchor: r.Anchor,
+				Addrs:  parseTableShow(out),
+			})
+		}
+	}
+	return tables, errs
+}
+
+// parseTableShow parses pfctl -T show output: one address or network per
+// line, possibly negated with "!".
+func parseTableShow(output string) []string {
+	var addrs []string
+	for _, line := range strings.Split(output, "\n") {
+		if a := strings.TrimSpace(line); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
 }
 
 func parsePfctlOutput(output string) []data.FirewallRule {
@@ -53,9 +161,10 @@ func parsePfctlOutput(output string) []data.FirewallRule {
 		rule := data.FirewallRule{
 			RuleNum: ruleNum,
 			RawRule: ruleText,
+			Ruleset: "filter",
 		}
 
-		// Parse action, direction, proto, src, dst from the rule text.
+		// Parse the action, match criteria and options from the rule text.
 		parseRuleFields(ruleText, &rule)
 
 		// Look ahead for evaluation counters.
@@ -74,46 +183,243 @@ func parsePfctlOutput(output string) []data.FirewallRule {
 	return rules
 }
 
+// tokenizeRule splits a rule as pfctl prints it into words, keeping a
+// quoted string (without its quotes) or a parenthesized option list such
+// as "(max 100, source-track rule)" as one word.
+func tokenizeRule(text string) []string {
+	var tokens []string
+	var cur strings.Builder
+	depth, quoted := 0, false
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, c := range text {
+		switch {
+		case quoted:
+			if c == '"' {
+				quoted = false
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '"' && depth == 0:
+			flush()
+			quoted = true
+		case c == '(':
+			depth++
+			cur.WriteRune(c)
+		case c == ')':
+			depth--
+			cur.WriteRune(c)
+		case (c == ' ' || c == '\t') && depth == 0:
+			flush()
+		default:
+			cur.WriteRune(c)
+		}
+	}
+	flush()
+	return tokens
+}
+
 func parseRuleFields(ruleText string, rule *data.FirewallRule) {
-	fields := strings.Fields(ruleText)
+	fields := tokenizeRule(ruleText)
 	if len(fields) == 0 {
 		return
 	}
 
-	// Action is first word: pass, block, match, etc.
+	// Action is first word: pass, block, match, anchor, nat, rdr, etc.
 	rule.Action = fields[0]
+	if strings.HasSuffix(rule.Action, "anchor") && len(fields) > 1 {
+		rule.AnchorCall = fields[1]
+	}
+
+	// next returns the word after fields[i], or "".
+	next := func(i int) string {
+		if i+1 < len(fields) {
+			return fields[i+1]
+		}
+		return ""
+	}
+	// opSpec returns the operator and operand after fields[i], as in
+	// "user = 501".
+	opSpec := func(i int) string {
+		switch op := next(i); op {
+		case "=", "!=", "<", "<=", ">", ">=":
+			return op + " " + next(i+1)
+		default:
+			return op
+		}
+	}
 
-	for i, f := range fields {
-		switch f {
+	var seenFrom, seenTo bool
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; f {
 		case "in", "out":
 			if rule.Direction == "" {
 				rule.Direction = f
 			}
-		case "proto":
-			if i+1 < len(fields) {
-				rule.Proto = fields[i+1]
+		case "quick":
+			rule.Quick = true
+		case "log":
+			rule.Log = "log"
+			if opts := next(i); strings.HasPrefix(opts, "(") {
+				rule.Log += " " + opts
+				i++
+			}
+		case "on":
+			if rule.Iface == "" {
+				rule.Iface = next(i)
+				if rule.Iface == "!" {
+					rule.Iface += " " + next(i+1)
+				}
+			}
+		case "inet", "inet6":
+			if rule.Family == "" {
+				rule.Family = f
 			}
+		case "proto":
+			rule.Proto = next(i)
 		case "from":
-			if i+1 < len(fields) {
-				rule.Src = collectAddrSpec(fields, i+1)
+			if next(i) != "" && !seenFrom {
+				seenFrom = true
+				rule.From, rule.Src = parseAddrSpec(fields, i+1, rule.Proto)
 			}
 		case "to":
-			if i+1 < len(fields) {
-				rule.Dst = collectAddrSpec(fields, i+1)
+			if next(i) != "" && !seenTo {
+				seenTo = true
+				rule.To, rule.Dst = parseAddrSpec(fields, i+1, rule.Proto)
+			}
+		case "user":
+			rule.User = opSpec(i)
+		case "group":
+			rule.Group = opSpec(i)
+		case "flags":
+			rule.Flags = next(i)
+			i++
+		case "label":
+			rule.Label = next(i)
+			i++
+		case "tag":
+			rule.Tag = next(i)
+			i++
+		case "tagged":
+			rule.Tagged = next(i)
+			if i > 0 && fields[i-1] == "!" {
+				rule.Tagged = "! " + rule.Tagged
+			}
+			i++
+		case "keep", "modulate", "synproxy", "no":
+			if next(i) != "state" {
+				continue
+			}
+			rule.State = f + " state"
+			i++
+			if opts := next(i); strings.HasPrefix(opts, "(") {
+				rule.State += " " + opts
+				i++
+			}
+		case "->":
+			rule.Translation = strings.Join(fields[i+1:], " ")
+			i = len(fields)
+		}
+	}
+
+	for _, spec := range []string{rule.From.Addr, rule.To.Addr, rule.Translation} {
+		for _, w := range strings.Fields(spec) {
+			if strings.HasPrefix(w, "<") && strings.HasSuffix(w, ">") {
+				rule.Tables = appendUnique(rule.Tables, strings.Trim(w, "<>"))
 			}
 		}
 	}
 }
 
-// collectAddrSpec extracts address spec following "from" or "to".
-// It takes the next token, and if followed by "port", appends that.
-func collectAddrSpec(fields []string, start int) string {
+// appendUnique appends s to list unless it is already there.
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// parseAddrSpec parses the address spec following "from" or "to": an
+// address with an optional leading "!" negation, followed by an optional
+// port spec as pfctl prints it: "port = 22", "port 1000:2000" or
+// "port 1000 >< 2000". It also returns the spec as text for display.
+func parseAddrSpec(fields []string, start int, proto string) (data.AddrSpec, string) {
+	var spec data.AddrSpec
 	if start >= len(fields) {
-		return ""
+		return spec, ""
+	}
+	if fields[start] == "!" && start+1 < len(fields) {
+		spec.Not = true
+		start++
+	}
+	spec.Addr = fields[start]
+	text := spec.Addr
+	if spec.Not {
+		text = "! " + text
+	}
+
+	i := start + 1
+	if i+1 >= len(fields) || fields[i] != "port" {
+		return spec, text
+	}
+	toks := fields[i+1 : i+2]
+	switch fields[i+1] {
+	case "=", "!=", "<", "<=", ">", ">=":
+		if i+2 < len(fields) {
+			toks = fields[i+1 : i+3]
+		}
+	default:
+		if i+3 < len(fields) && (fields[i+2] == "><" || fields[i+2] == "<>") {
+			toks = fields[i+1 : i+4]
+		}
 	}
-	addr := fields[start]
-	if start+2 < len(fields) && fields[start+1] == "port" {
-		addr += " port " + fields[start+2]
+	spec.Port = parsePortSpec(toks, proto)
+	return spec, text + " port " + spec.Port.Name
+}
+
+// parsePortSpec parses the tokens of a port spec, resolving service names
+// for proto.
+func parsePortSpec(toks []string, proto string) data.PortSpec {
+	ps := data.PortSpec{Name: strings.Join(toks, " ")}
+	var err1, err2 error
+	switch len(toks) {
+	case 1:
+		if lo, hi, ok := strings.Cut(toks[0], ":"); ok {
+			ps.Op = ":"
+			ps.Lo, err1 = lookupPort(proto, lo)
+			ps.Hi, err2 = lookupPort(proto, hi)
+		} else {
+			ps.Op = "="
+			ps.Lo, err1 = lookupPort(proto, toks[0])
+		}
+	case 2:
+		ps.Op = toks[0]
+		ps.Lo, err1 = lookupPort(proto, toks[1])
+	case 3:
+		ps.Op = toks[1]
+		ps.Lo, err1 = lookupPort(proto, toks[0])
+		ps.Hi, err2 = lookupPort(proto, toks[2])
+	}
+	if ps.Op == "" || err1 != nil || err2 != nil {
+		ps.Op = "?"
+	}
+	return ps
+}
+
+// lookupPort resolves a port number or service name. Rules without a
+// protocol look names up as TCP services.
+func lookupPort(proto, name string) (uint32, error) {
+	if proto != "udp" {
+		proto = "tcp"
 	}
-	return addr
+	n, err := net.LookupPort(proto, name)
+	return uint32(n), err
 }
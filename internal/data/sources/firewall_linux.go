@@ -0,0 +1,63 @@
+//go:build linux
+
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// CollectFirewall lists the netfilter rules with nft -j, and the address
+// sets they reference as tables. Where nft is not installed it falls back
+// to iptables-save and ip6tables-save, which have no sets to list.
+// Requires root access.
+func CollectFirewall(isRoot bool) ([]data.FirewallRule, []data.FirewallTable, []data.CollectionError) {
+	if !isRoot {
+		return nil, nil, []data.CollectionError{{Source: "firewall", Error: "nft and iptables-save require root access"}}
+	}
+
+	if _, err := exec.LookPath("nft"); err == nil {
+		out, err := exec.Command("nft", "-j", "list", "ruleset").Output()
+		if err != nil {
+			return nil, nil, []data.CollectionError{{Source: "firewall", Error: fmt.Sprintf("nft -j list ruleset: %v%s", err, stderr(err))}}
+		}
+		rules, tables, err := parseNftRuleset(out)
+		if err != nil {
+			return nil, nil, []data.CollectionError{{Source: "firewall", Error: fmt.Sprintf("nft -j list ruleset: %v", err)}}
+		}
+		return rules, tables, nil
+	}
+
+	var rules []data.FirewallRule
+	var errs []data.CollectionError
+	found := false
+	for _, tool := range []struct{ name, family string }{{"iptables-save", "ip"}, {"ip6tables-save", "ip6"}} {
+		if _, err := exec.LookPath(tool.name); err != nil {
+			continue
+		}
+		found = true
+		out, err := exec.Command(tool.name, "-c").Output()
+		if err != nil {
+			errs = append(errs, data.CollectionError{Source: "firewall", Error: fmt.Sprintf("%s -c: %v%s", tool.name, err, stderr(err))})
+			continue
+		}
+		rules = append(rules, parseIptablesSave(string(out), tool.family)...)
+	}
+	if !found {
+		errs = append(errs, data.CollectionError{Source: "firewall", Error: "neither nft nor iptables-save is installed"})
+	}
+	return rules, nil, errs
+}
+
+// stderr returns what a failed command printed to stderr, after a colon.
+func stderr(err error) string {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+		return ": " + strings.TrimSpace(string(exit.Stderr))
+	}
+	return ""
+}
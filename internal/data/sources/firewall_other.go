@@ -0,0 +1,11 @@
+//go:build !linux
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// CollectFirewall collects the pf rules and the tables they reference.
+// Requires root access.
+func CollectFirewall(isRoot bool) ([]data.FirewallRule, []data.FirewallTable, []data.CollectionError) {
+	return collectPF(isRoot)
+}
@@ -0,0 +1,132 @@
+package sources
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// maxFlows bounds the flows kept per namespace; the oldest are dropped
+// first.
+const maxFlows = 2000
+
+// flowRecorder records the connections conntrack reports opening and
+// closing in each namespace it watches, so the ones that live between two
+// refreshes are listed too.
+type flowRecorder struct {
+	mu   sync.Mutex
+	logs map[uint64]*flowLog // by namespace inode
+}
+
+// flowLog is the record of one namespace.
+type flowLog struct {
+	flows       []*data.Flow          // oldest first
+	ids         []string              // conntrack ID of each of flows
+	open        map[string]*data.Flow // by conntrack ID
+	lost        int                   // events the kernel dropped since the last collect
+	err         error                 // why the event stream stopped, nil while it runs
+	stop        func()                // closes the event stream
+	lastCollect time.Time
+}
+
+func newFlowRecorder() *flowRecorder {
+	return &flowRecorder{logs: make(map[uint64]*flowLog)}
+}
+
+func newFlowLog() *flowLog {
+	return &flowLog{open: make(map[string]*data.Flow)}
+}
+
+// prune stops recording in namespaces no longer listed, so their event
+// streams do not keep them alive.
+func (r *flowRecorder) prune(namespaces []data.Namespace) {
+	if len(namespaces) == 0 {
+		return
+	}
+	live := make(map[uint64]bool, len(namespaces))
+	for _, ns := range namespaces {
+		live[ns.ID] = true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, l := range r.logs {
+		if !live[id] {
+			if l.stop != nil {
+				l.stop()
+			}
+			delete(r.logs, id)
+		}
+	}
+}
+
+// flowEvent is a conntrack event: a flow created, updated or destroyed.
+type flowEvent struct {
+	id      string
+	created bool
+	closed  bool // destroyed, or a TCP state past the close handshake
+	destroy bool
+	flow    data.Flow // Start and End from kernel timestamps, when enabled
+}
+
+// record applies an event at time now.
+func (l *flowLog) record(ev flowEvent, now time.Time) {
+	f, ok := l.open[ev.id]
+	if !ok {
+		f = &data.Flow{Proto: ev.flow.Proto, Family: ev.flow.Family, Orig: ev.flow.Orig, Reply: ev.flow.Reply}
+		f.Start = ev.flow.Start
+		if f.Start.IsZero() && ev.created {
+			f.Start = now
+		}
+		l.flows = append(l.flows, f)
+		l.ids = append(l.ids, ev.id)
+		l.open[ev.id] = f
+		if len(l.flows) > maxFlows {
+			if l.open[l.ids[0]] == l.flows[0] {
+				delete(l.open, l.ids[0])
+			}
+			l.flows, l.ids = l.flows[1:], l.ids[1:]
+		}
+	}
+	if ev.flow.State != "" {
+		f.State = ev.flow.State
+	}
+	if ev.flow.Packets() > 0 {
+		f.OrigPackets, f.OrigBytes = ev.flow.OrigPackets, ev.flow.OrigBytes
+		f.ReplyPackets, f.ReplyBytes = ev.flow.ReplyPackets, ev.flow.ReplyBytes
+	}
+	if ev.closed && f.End.IsZero() {
+		f.End = ev.flow.End
+		if f.End.IsZero() {
+			f.End = now
+		}
+	}
+	if ev.destroy {
+		delete(l.open, ev.id)
+	}
+}
+
+// snapshot attributes the flows open or closed since the last collect to
+// the owners of sockets, then returns a copy of all flows, newest first.
+func (l *flowLog) snapshot(sockets []data.Socket, ifaces []data.Interface, now time.Time) ([]data.Flow, []data.CollectionError) {
+	var pending []*data.Flow
+	for _, f := range l.flows {
+		if f.PID == 0 && (f.Open() || f.End.After(l.lastCollect)) {
+			pending = append(pending, f)
+		}
+	}
+	data.AttributeFlows(pending, sockets, ifaces)
+	l.lastCollect = now
+
+	flows := make([]data.Flow, 0, len(l.flows))
+	for i := len(l.flows) - 1; i >= 0; i-- {
+		flows = append(flows, *l.flows[i])
+	}
+	var errs []data.CollectionError
+	if l.lost > 0 {
+		errs = append(errs, data.CollectionError{Source: "flows", Error: fmt.Sprintf("%d conntrack events lost", l.lost)})
+		l.lost = 0
+	}
+	return flows, errs
+}
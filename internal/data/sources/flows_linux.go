@@ -0,0 +1,232 @@
+//go:build linux
+
+package sources
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// collect returns the flows recorded in the namespace of the calling
+// thread, subscribing to its conntrack NEW, UPDATE and DESTROY events the
+// first time. The flows open or closed since the last collect are
+// attributed to the owners of sockets. Subscribing needs CAP_NET_ADMIN,
+// and byte counts need net.netfilter.nf_conntrack_acct.
+func (r *flowRecorder) collect(isRoot bool, sockets []data.Socket, ifaces []data.Interface) ([]data.Flow, []data.CollectionError) {
+	if !isRoot {
+		return nil, []data.CollectionError{{Source: "flows", Error: "conntrack events require root access"}}
+	}
+	id, err := nsInode("/proc/thread-self/ns/net")
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "flows", Error: fmt.Sprintf("stat /proc/thread-self/ns/net: %v", err)}}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	log, ok := r.logs[id]
+	if !ok || log.err != nil {
+		sock, err := nl.Subscribe(unix.NETLINK_NETFILTER,
+			unix.NFNLGRP_CONNTRACK_NEW, unix.NFNLGRP_CONNTRACK_UPDATE, unix.NFNLGRP_CONNTRACK_DESTROY)
+		if err != nil {
+			return nil, []data.CollectionError{{Source: "flows", Error: fmt.Sprintf("subscribe to conntrack events: %v", err)}}
+		}
+		if log == nil {
+			log = newFlowLog()
+			r.logs[id] = log
+		}
+		log.err = nil
+		log.stop = sock.Close
+		go r.watch(sock, log)
+	}
+	return log.snapshot(sockets, ifaces, time.Now())
+}
+
+// watch records the events received on sock into log until the socket
+// fails or is closed.
+func (r *flowRecorder) watch(sock *nl.NetlinkSocket, log *flowLog) {
+	for {
+		msgs, _, err := sock.Receive()
+		if errors.Is(err, unix.ENOBUFS) {
+			// The kernel dropped events faster than they were read.
+			r.mu.Lock()
+			log.lost++
+			r.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			r.mu.Lock()
+			log.err = err
+			r.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		r.mu.Lock()
+		for _, m := range msgs {
+			if ev, ok := parseFlowEvent(m); ok {
+				log.record(ev, now)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// Attribute types of ctnetlink messages not in the nl package.
+const (
+	ctaProtoInfoTCP      = 1
+	ctaProtoInfoTCPState = 1
+	tcpConntrackTimeWait = 7
+	tcpConntrackClose    = 8
+)
+
+// parseFlowEvent decodes a ctnetlink event message.
+func parseFlowEvent(m syscall.NetlinkMessage) (flowEvent, bool) {
+	if m.Header.Type>>8 != unix.NFNL_SUBSYS_CTNETLINK || len(m.Data) < nl.SizeofNfgenmsg {
+		return flowEvent{}, false
+	}
+	var ev flowEvent
+	switch m.Header.Type & 0xff {
+	case nl.IPCTNL_MSG_CT_NEW:
+		ev.created = m.Header.Flags&unix.NLM_F_CREATE != 0
+	case nl.IPCTNL_MSG_CT_DELETE:
+		ev.destroy, ev.closed = true, true
+	default:
+		return flowEvent{}, false
+	}
+	ev.flow.Family = "inet"
+	if m.Data[0] == unix.AF_INET6 {
+		ev.flow.Family = "inet6"
+	}
+
+	attrs, err := nl.ParseRouteAttr(m.Data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return flowEvent{}, false
+	}
+	for _, a := range attrs {
+		switch a.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_ORIG:
+			ev.flow.Orig, ev.flow.Proto = parseFlowTuple(a.Value)
+		case nl.CTA_TUPLE_REPLY:
+			ev.flow.Reply, _ = parseFlowTuple(a.Value)
+		case nl.CTA_COUNTERS_ORIG:
+			ev.flow.OrigPackets, ev.flow.OrigBytes = parseFlowCounters(a.Value)
+		case nl.CTA_COUNTERS_REPLY:
+			ev.flow.ReplyPackets, ev.flow.ReplyBytes = parseFlowCounters(a.Value)
+		case nl.CTA_ID:
+			if len(a.Value) >= 4 {
+				ev.id = fmt.Sprintf("%d", binary.BigEndian.Uint32(a.Value))
+			}
+		case nl.CTA_TIMESTAMP:
+			for _, t := range nestedAttrs(a.Value) {
+				if len(t.Value) < 8 {
+					continue
+				}
+				ts := time.Unix(0, int64(binary.BigEndian.Uint64(t.Value)))
+				switch t.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_TIMESTAMP_START:
+					ev.flow.Start = ts
+				case nl.CTA_TIMESTAMP_STOP:
+					ev.flow.End = ts
+				}
+			}
+		case nl.CTA_PROTOINFO:
+			for _, p := range nestedAttrs(a.Value) {
+				if p.Attr.Type&nl.NLA_TYPE_MASK != ctaProtoInfoTCP {
+					continue
+				}
+				for _, t := range nestedAttrs(p.Value) {
+					if t.Attr.Type&nl.NLA_TYPE_MASK == ctaProtoInfoTCPState && len(t.Value) > 0 {
+						state := t.Value[0]
+						if int(state) < len(tcpConntrackStates) {
+							ev.flow.State = tcpConntrackStates[state]
+						}
+						// The connection is over at TIME_WAIT, or CLOSE after
+						// a reset; the entry lingers until its timeout.
+						if state == tcpConntrackTimeWait || state == tcpConntrackClose {
+							ev.closed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	if ev.id == "" {
+		// Without an ID, the original tuple identifies the flow.
+		ev.id = ev.flow.Proto + " " + ev.flow.Orig.String()
+	}
+	// The stop timestamp is only meaningful on destroy.
+	if !ev.destroy {
+		ev.flow.End = time.Time{}
+	}
+	return ev, true
+}
+
+// parseFlowTuple decodes a CTA_TUPLE_* attribute into a tuple and its IP
+// protocol.
+func parseFlowTuple(b []byte) (data.FlowTuple, string) {
+	var t data.FlowTuple
+	var proto string
+	for _, a := range nestedAttrs(b) {
+		switch a.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_IP:
+			for _, ip := range nestedAttrs(a.Value) {
+				switch ip.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					t.Src = net.IP(ip.Value).String()
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					t.Dst = net.IP(ip.Value).String()
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			for _, p := range nestedAttrs(a.Value) {
+				switch p.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_PROTO_NUM:
+					if len(p.Value) > 0 {
+						proto = ipProtoName(p.Value[0])
+					}
+				case nl.CTA_PROTO_SRC_PORT:
+					if len(p.Value) >= 2 {
+						t.SrcPort = uint32(binary.BigEndian.Uint16(p.Value))
+					}
+				case nl.CTA_PROTO_DST_PORT:
+					if len(p.Value) >= 2 {
+						t.DstPort = uint32(binary.BigEndian.Uint16(p.Value))
+					}
+				}
+			}
+		}
+	}
+	return t, proto
+}
+
+// parseFlowCounters decodes a CTA_COUNTERS_* attribute.
+func parseFlowCounters(b []byte) (packets, bytes uint64) {
+	for _, a := range nestedAttrs(b) {
+		if len(a.Value) < 8 {
+			continue
+		}
+		switch a.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_COUNTERS_PACKETS:
+			packets = binary.BigEndian.Uint64(a.Value)
+		case nl.CTA_COUNTERS_BYTES:
+			bytes = binary.BigEndian.Uint64(a.Value)
+		}
+	}
+	return packets, bytes
+}
+
+// nestedAttrs parses the attributes nested in b, or none if b is malformed.
+func nestedAttrs(b []byte) []syscall.NetlinkRouteAttr {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil
+	}
+	return attrs
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// collect is a no-op outside Linux: pf has no event stream of states
+// opening and closing.
+func (r *flowRecorder) collect(isRoot bool, sockets []data.Socket, ifaces []data.Interface) ([]data.Flow, []data.CollectionError) {
+	return nil, nil
+}
@@ -0,0 +1,34 @@
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// historySize is the number of rate samples retained per interface.
+const historySize = 300
+
+// rateHistory is a fixed-size ring buffer of rate samples.
+type rateHistory struct {
+	samples [historySize]data.RateSample
+	next    int
+	full    bool
+}
+
+func (h *rateHistory) add(s data.RateSample) {
+	h.samples[h.next] = s
+	h.next = (h.next + 1) % historySize
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns the retained samples ordered oldest first.
+func (h *rateHistory) snapshot() []data.RateSample {
+	if !h.full {
+		out := make([]data.RateSample, h.next)
+		copy(out, h.samples[:h.next])
+		return out
+	}
+	out := make([]data.RateSample, 0, historySize)
+	out = append(out, h.samples[h.next:]...)
+	out = append(out, h.samples[:h.next]...)
+	return out
+}
@@ -1,24 +1,28 @@
 package sources
 
 import (
+	"context"
 	"fmt"
+	"net"
 
 	"github.com/jerryluo/nettui/internal/data"
 	psnet "github.com/shirou/gopsutil/v4/net"
 )
 
 // CollectInterfaces gathers network interface info and IO counters.
-func CollectInterfaces() ([]data.Interface, []data.CollectionError) {
+// namespaces, as ListNamespaces returns them, name the namespaces veth
+// peers live in.
+func CollectInterfaces(ctx context.Context, namespaces []data.Namespace) ([]data.Interface, []data.CollectionError) {
 	var errs []data.CollectionError
 
-	ifaces, err := psnet.Interfaces()
+	ifaces, err := psnet.InterfacesWithContext(ctx)
 	if err != nil {
 		return nil, []data.CollectionError{{Source: "interfaces", Error: fmt.Sprintf("Interfaces(): %v", err)}}
 	}
 
 	// Gather IO counters keyed by interface name.
 	counters := make(map[string]psnet.IOCountersStat)
-	ioStats, err := psnet.IOCounters(true)
+	ioStats, err := psnet.IOCountersWithContext(ctx, true)
 	if err != nil {
 		errs = append(errs, data.CollectionError{Source: "interfaces", Error: fmt.Sprintf("IOCounters(): %v", err)})
 	} else {
@@ -35,11 +39,13 @@ func CollectInterfaces() ([]data.Interface, []data.CollectionError) {
 		}
 
 		di := data.Interface{
-			Name:  iface.Name,
-			Index: iface.Index,
-			MTU:   iface.MTU,
-			Addrs: addrs,
-			Up:    containsFlag(iface.Flags, "up"),
+			Name:      iface.Name,
+			Index:     iface.Index,
+			MTU:       iface.MTU,
+			Flags:     parseFlags(iface.Flags),
+			Addrs:     addrs,
+			Addresses: parseAddresses(addrs),
+			Up:        containsFlag(iface.Flags, "up"),
 		}
 
 		if iface.HardwareAddr != "" {
@@ -51,14 +57,87 @@ func CollectInterfaces() ([]data.Interface, []data.CollectionError) {
 			di.BytesRecv = c.BytesRecv
 			di.PacketSent = c.PacketsSent
 			di.PacketRecv = c.PacketsRecv
+			di.ErrIn = c.Errin
+			di.ErrOut = c.Errout
+			di.DropIn = c.Dropin
+			di.DropOut = c.Dropout
+			di.FifoIn = c.Fifoin
+			di.FifoOut = c.Fifoout
 		}
 
 		result = append(result, di)
 	}
 
+	// sysfs describes the namespace it was mounted from, so interfaces in
+	// other namespaces get only what collectTopology reads over rtnetlink.
+	if !foreignNamespace(ctx) {
+		errs = append(errs, collectLinkDetails(result)...)
+	}
+	errs = append(errs, collectTopology(result, namespaces)...)
+	errs = append(errs, collectAddresses(result)...)
+
 	return result, errs
 }
 
+// interfaceFlags maps gopsutil's flag names (net.Flags.String() tokens)
+// back to their net.Flags bits.
+var interfaceFlags = map[string]net.Flags{
+	"up":           net.FlagUp,
+	"broadcast":    net.FlagBroadcast,
+	"loopback":     net.FlagLoopback,
+	"pointtopoint": net.FlagPointToPoint,
+	"multicast":    net.FlagMulticast,
+	"running":      net.FlagRunning,
+}
+
+// parseAddresses turns gopsutil's "ip/prefix" strings into structured
+// addresses. Platform collectors replace these with richer details
+// (broadcast, IPv6 flags, lifetimes) where they can.
+func parseAddresses(addrs []string) []data.Address {
+	result := make([]data.Address, 0, len(addrs))
+	for _, a := range addrs {
+		ip, ipnet, err := net.ParseCIDR(a)
+		if err != nil {
+			continue
+		}
+		ones, _ := ipnet.Mask.Size()
+		result = append(result, data.Address{
+			IP:        ip.String(),
+			Family:    addressFamily(ip),
+			PrefixLen: ones,
+			Scope:     addressScope(ip),
+		})
+	}
+	return result
+}
+
+func addressFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "inet"
+	}
+	return "inet6"
+}
+
+// addressScope infers an address's scope from its range.
+func addressScope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+		return "link"
+	default:
+		return "global"
+	}
+}
+
+func parseFlags(flags []string) net.Flags {
+	var f net.Flags
+	for _, name := range flags {
+		f |= interfaceFlags[name]
+	}
+	return f
+}
+
 func containsFlag(flags []string, target string) bool {
 	for _, f := range flags {
 		if f == target {
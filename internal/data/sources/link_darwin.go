@@ -0,0 +1,227 @@
+//go:build darwin
+
+package sources
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+var (
+	// Matches interface header lines like: en0: flags=8863<UP,BROADCAST,...> mtu 1500
+	ifconfigHeaderRe = regexp.MustCompile(`^(\S+): flags=`)
+	// Matches the active media subtype like: (1000baseT <full-duplex,flow-control>)
+	ifconfigMediaRe = regexp.MustCompile(`\((\d+)(G?)base\S*(?:\s*<([^>]*)>)?`)
+	// Matches VLAN lines like: vlan: 100 parent interface: en0
+	ifconfigVLANRe = regexp.MustCompile(`^(\d+) parent interface: (\S+)`)
+)
+
+// darwinLink holds the link details ifconfig reports for one interface.
+type darwinLink struct {
+	speed  int
+	duplex string
+	status string
+	kind   string
+
+	members    []string // bridge members and bond slaves
+	vlanID     int
+	vlanParent string
+	peer       string // feth peer
+
+	addrs []data.Address
+}
+
+// collectLinkDetails fills link speed, duplex, state and kind from
+// `ifconfig -v -L -a`, along with bridge, bond, VLAN and feth relationships
+// and the IPv6 address flags and lifetimes. Darwin has no per-interface
+// driver or queue length.
+func collectLinkDetails(ifaces []data.Interface) []data.CollectionError {
+	out, err := exec.Command("ifconfig", "-v", "-L", "-a").Output()
+	if err != nil {
+		return []data.CollectionError{{Source: "interfaces", Error: fmt.Sprintf("ifconfig -v -L -a: %v", err)}}
+	}
+	links := parseIfconfigOutput(string(out))
+	for i := range ifaces {
+		l, ok := links[ifaces[i].Name]
+		if !ok {
+			continue
+		}
+		ifaces[i].Speed = l.speed
+		ifaces[i].Duplex = l.duplex
+		ifaces[i].Kind = l.kind
+		switch l.status {
+		case "active":
+			ifaces[i].OperState = "up"
+			ifaces[i].Carrier = true
+		case "inactive":
+			ifaces[i].OperState = "down"
+		}
+		ifaces[i].VLANID = l.vlanID
+		ifaces[i].Parent = l.vlanParent
+		ifaces[i].Peer = l.peer
+		if len(l.addrs) > 0 {
+			ifaces[i].Addresses = l.addrs
+		}
+	}
+
+	// Membership is reported on the bridge or bond, not on its ports.
+	byName := make(map[string]*data.Interface, len(ifaces))
+	for i := range ifaces {
+		byName[ifaces[i].Name] = &ifaces[i]
+	}
+	for master, l := range links {
+		for _, member := range l.members {
+			if port, ok := byName[member]; ok {
+				port.Master = master
+			}
+		}
+	}
+	return nil
+}
+
+func parseIfconfigOutput(output string) map[string]darwinLink {
+	links := make(map[string]darwinLink)
+	var name string
+	var cur darwinLink
+
+	flush := func() {
+		if name != "" {
+			links[name] = cur
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := ifconfigHeaderRe.FindStringSubmatch(line); m != nil {
+			flush()
+			name = m[1]
+			cur = darwinLink{}
+			continue
+		}
+		if f := strings.Fields(line); len(f) > 1 && (f[0] == "inet" || f[0] == "inet6") {
+			if a, ok := parseIfconfigAddr(f); ok {
+				cur.addrs = append(cur.addrs, a)
+			}
+			continue
+		}
+		field, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			continue
+		}
+		switch field {
+		case "media":
+			if m := ifconfigMediaRe.FindStringSubmatch(value); m != nil {
+				cur.speed, _ = strconv.Atoi(m[1])
+				if m[2] == "G" {
+					cur.speed *= 1000
+				}
+				for _, opt := range strings.Split(m[3], ",") {
+					if d, ok := strings.CutSuffix(opt, "-duplex"); ok {
+						cur.duplex = d
+					}
+				}
+			}
+		case "status":
+			cur.status = value
+		case "type":
+			cur.kind = strings.ToLower(value)
+		case "member":
+			if f := strings.Fields(value); len(f) > 0 {
+				cur.members = append(cur.members, f[0])
+			}
+		case "bond interfaces":
+			cur.members = append(cur.members, strings.Fields(value)...)
+		case "vlan":
+			if m := ifconfigVLANRe.FindStringSubmatch(value); m != nil {
+				cur.vlanID, _ = strconv.Atoi(m[1])
+				cur.vlanParent = m[2]
+			}
+		case "peer":
+			cur.peer = value
+		}
+	}
+	flush()
+
+	return links
+}
+
+// ifconfigAddrFlags maps ifconfig's inet6 keywords to the flag names used
+// on Linux, so both platforms read the same in the detail panel.
+var ifconfigAddrFlags = map[string]string{
+	"autoconf":   "autoconf",
+	"temporary":  "temporary",
+	"deprecated": "deprecated",
+	"tentative":  "tentative",
+	"duplicated": "dadfailed",
+	"detached":   "detached",
+	"optimistic": "optimistic",
+	"secured":    "secured",
+	"dynamic":    "dynamic",
+}
+
+// parseIfconfigAddr parses the fields of an inet or inet6 line such as
+//
+//	inet 192.168.1.5 netmask 0xffffff00 broadcast 192.168.1.255
+//	inet6 fe80::1%en0 prefixlen 64 secured scopeid 0x6
+//	inet6 2001:db8::1 prefixlen 64 autoconf temporary pltime 85000 vltime 604000
+func parseIfconfigAddr(f []string) (data.Address, bool) {
+	addr, _, _ := strings.Cut(f[1], "%")
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return data.Address{}, false
+	}
+	a := data.Address{
+		IP:     ip.String(),
+		Family: f[0],
+		Scope:  addressScope(ip),
+	}
+	for i := 2; i < len(f); i++ {
+		next := ""
+		if i+1 < len(f) {
+			next = f[i+1]
+		}
+		switch f[i] {
+		case "netmask":
+			if v, err := strconv.ParseUint(strings.TrimPrefix(next, "0x"), 16, 32); err == nil {
+				a.PrefixLen, _ = net.IPv4Mask(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).Size()
+			}
+			i++
+		case "prefixlen":
+			a.PrefixLen, _ = strconv.Atoi(next)
+			i++
+		case "broadcast":
+			a.Broadcast = next
+			i++
+		case "pltime":
+			a.PreferredLifetime = ifconfigLifetime(next)
+			i++
+		case "vltime":
+			a.ValidLifetime = ifconfigLifetime(next)
+			i++
+		case "scopeid", "-->":
+			i++
+		default:
+			if flag, ok := ifconfigAddrFlags[f[i]]; ok {
+				a.Flags = append(a.Flags, flag)
+			}
+		}
+	}
+	return a, true
+}
+
+func ifconfigLifetime(s string) time.Duration {
+	if s == "infty" {
+		return data.Forever
+	}
+	secs, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,102 @@
+//go:build linux
+
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// sysClassNet is the sysfs directory holding one entry per network interface.
+const sysClassNet = "/sys/class/net"
+
+// collectLinkDetails fills link speed, duplex, state, driver and kind from
+// /sys/class/net/<if>. Attributes a driver does not implement are skipped.
+func collectLinkDetails(ifaces []data.Interface) []data.CollectionError {
+	for i := range ifaces {
+		readSysfsLink(filepath.Join(sysClassNet, ifaces[i].Name), &ifaces[i])
+	}
+	return nil
+}
+
+func readSysfsLink(dir string, iface *data.Interface) {
+	// speed reads -1 or fails with EINVAL when the link is down.
+	if v, ok := readSysfsInt(dir, "speed"); ok && v > 0 {
+		iface.Speed = int(v)
+	}
+	if v := readSysfsString(dir, "duplex"); v != "unknown" {
+		iface.Duplex = v
+	}
+	iface.OperState = readSysfsString(dir, "operstate")
+	if v, ok := readSysfsInt(dir, "carrier"); ok {
+		iface.Carrier = v == 1
+	}
+	if v, ok := readSysfsInt(dir, "carrier_changes"); ok {
+		iface.CarrierChanges = uint64(v)
+	}
+	if v, ok := readSysfsInt(dir, "tx_queue_len"); ok {
+		iface.TxQueueLen = int(v)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "device", "driver")); err == nil {
+		iface.Driver = filepath.Base(target)
+	}
+	iface.Kind = sysfsKind(dir)
+}
+
+// sysfsKind classifies an interface. DEVTYPE in uevent covers bridges,
+// bonds, VLANs, WLAN and most virtual drivers; the rest are told apart by
+// the attributes and links sysfs exposes for them.
+func sysfsKind(dir string) string {
+	if devType := ueventValue(dir, "DEVTYPE"); devType != "" {
+		return devType
+	}
+	if t, ok := readSysfsInt(dir, "type"); ok && t == 772 { // ARPHRD_LOOPBACK
+		return "loopback"
+	}
+	switch {
+	case sysfsExists(dir, "bridge"):
+		return "bridge"
+	case sysfsExists(dir, "bonding"):
+		return "bond"
+	case sysfsExists(dir, "tun_flags"):
+		return "tun"
+	case sysfsExists(dir, "device"):
+		return "ethernet"
+	}
+	return "virtual"
+}
+
+func ueventValue(dir, key string) string {
+	raw, err := os.ReadFile(filepath.Join(dir, "uevent"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if v, ok := strings.CutPrefix(line, key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func readSysfsString(dir, name string) string {
+	raw, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func readSysfsInt(dir, name string) (int64, bool) {
+	v, err := strconv.ParseInt(readSysfsString(dir, name), 10, 64)
+	return v, err == nil
+}
+
+func sysfsExists(dir, name string) bool {
+	_, err := os.Lstat(filepath.Join(dir, name))
+	return err == nil
+}
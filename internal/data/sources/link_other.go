@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// collectLinkDetails is not implemented on this platform.
+func collectLinkDetails(ifaces []data.Interface) []data.CollectionError {
+	return nil
+}
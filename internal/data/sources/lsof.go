@@ -9,41 +9,38 @@ import (
 	"github.com/jerryluo/nettui/internal/data"
 )
 
-// LsofResult holds parsed lsof data for enriching sockets and collecting unix sockets.
+// LsofResult holds parsed lsof data for enriching sockets.
 type LsofResult struct {
 	// PIDProcess maps PID to process name from lsof.
 	PIDProcess map[int32]string
 	// SocketPIDs maps "localAddr:localPort" to PID for inet sockets.
 	SocketPIDs map[string]int32
-	// UnixSockets are the parsed unix domain sockets.
-	UnixSockets []data.UnixSocket
 }
 
-// CollectLsof runs lsof to gather inet socket-to-PID mappings and unix sockets.
-func CollectLsof() (*LsofResult, []data.CollectionError) {
-	var errs []data.CollectionError
+// CollectLsofInet runs lsof for inet socket-to-PID mappings only. lsof
+// resolves socket addresses in its own network namespace, so running it
+// inside another namespace maps that namespace's sockets.
+func CollectLsofInet() (*LsofResult, []data.CollectionError) {
 	result := &LsofResult{
 		PIDProcess: make(map[int32]string),
 		SocketPIDs: make(map[string]int32),
 	}
 
-	// Collect inet sockets.
 	inetOut, err := exec.Command("lsof", "-i", "-P", "-n", "-F", "pcfn").Output()
 	if err != nil {
-		errs = append(errs, data.CollectionError{Source: "lsof-inet", Error: fmt.Sprintf("lsof -i: %v", err)})
-	} else {
-		parseInetLsof(string(inetOut), result)
+		return result, []data.CollectionError{{Source: "lsof-inet", Error: fmt.Sprintf("lsof -i: %v", err)}}
 	}
+	parseInetLsof(string(inetOut), result)
+	return result, nil
+}
 
-	// Collect unix sockets.
+// CollectLsofUnix runs lsof for unix domain sockets.
+func CollectLsofUnix() ([]data.UnixSocket, []data.CollectionError) {
 	unixOut, err := exec.Command("lsof", "-U", "-F", "pcfn").Output()
 	if err != nil {
-		errs = append(errs, data.CollectionError{Source: "lsof-unix", Error: fmt.Sprintf("lsof -U: %v", err)})
-	} else {
-		result.UnixSockets = parseUnixLsof(string(unixOut))
+		return nil, []data.CollectionError{{Source: "lsof-unix", Error: fmt.Sprintf("lsof -U: %v", err)}}
 	}
-
-	return result, errs
+	return parseUnixLsof(string(unixOut)), nil
 }
 
 func parseInetLsof(output string, result *LsofResult) {
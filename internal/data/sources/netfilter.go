@@ -0,0 +1,830 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// iptablesCountersRe matches the counters iptables-save -c puts before a
+// rule, like "[12:720] -A INPUT -j ACCEPT".
+var iptablesCountersRe = regexp.MustCompile(`^\[(\d+):(\d+)\]\s+(.*)$`)
+
+// ctStates are the conntrack states a netfilter rule can match.
+var ctStates = []string{"invalid", "established", "related", "new", "untracked"}
+
+// iptablesHooks maps the built-in iptables chains to their hooks.
+var iptablesHooks = map[string]string{
+	"PREROUTING":  "prerouting",
+	"INPUT":       "input",
+	"FORWARD":     "forward",
+	"OUTPUT":      "output",
+	"POSTROUTING": "postrouting",
+}
+
+// chainAnchor names a netfilter chain the way rules list it as their
+// anchor: "inet filter/input", or "ip filter/INPUT" for iptables.
+func chainAnchor(family, table, chain string) string {
+	return family + " " + table + "/" + chain
+}
+
+// familyName maps a netfilter address family to pf's name for it, "" for
+// inet tables, which see both.
+func familyName(family string) string {
+	switch family {
+	case "ip", "ipv4":
+		return "inet"
+	case "ip6", "ipv6":
+		return "inet6"
+	}
+	return ""
+}
+
+// hookDirection gives the direction of the traffic a hook sees, "" for
+// the hooks that see both or forwarded traffic.
+func hookDirection(hook string) string {
+	switch hook {
+	case "input":
+		return "in"
+	case "output":
+		return "out"
+	}
+	return ""
+}
+
+// policyRule lists the policy of a base chain as a rule deciding the
+// traffic its rules let through.
+func policyRule(anchor, family, policy string) data.FirewallRule {
+	policy = strings.ToLower(policy)
+	if policy == "" {
+		policy = "accept"
+	}
+	action := "pass"
+	if policy == "drop" {
+		action = "block"
+	}
+	return data.FirewallRule{
+		Action:  action,
+		Quick:   true,
+		Policy:  true,
+		Anchor:  anchor,
+		Family:  familyName(family),
+		Src:     "any",
+		Dst:     "any",
+		RawRule: "policy " + policy,
+	}
+}
+
+// ctStateList joins the conntrack states a rule matches, turning a
+// negated list into the states it leaves out.
+func ctStateList(states []string, not bool) string {
+	for i, s := range states {
+		states[i] = strings.ToLower(s)
+	}
+	if !not {
+		return strings.Join(states, ",")
+	}
+	var rest []string
+	for _, s := range ctStates {
+		found := false
+		for _, t := range states {
+			found = found || s == t
+		}
+		if !found {
+			rest = append(rest, s)
+		}
+	}
+	return strings.Join(rest, ",")
+}
+
+// nftRuleset is nft -j list ruleset output: a list of objects, each
+// holding a table, chain, set, rule or other item under its kind.
+type nftRuleset struct {
+	Nftables []map[string]json.RawMessage `json:"nftables"`
+}
+
+type nftChain struct {
+	Family string `json:"family"`
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Hook   string `json:"hook"`
+	Prio   int    `json:"prio"`
+	Policy string `json:"policy"`
+}
+
+type nftSet struct {
+	Family string `json:"family"`
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Type   any    `json:"type"`
+	Elem   []any  `json:"elem"`
+}
+
+type nftRule struct {
+	Family  string           `json:"family"`
+	Table   string           `json:"table"`
+	Chain   string           `json:"chain"`
+	Handle  int              `json:"handle"`
+	Comment string           `json:"comment"`
+	Expr    []map[string]any `json:"expr"`
+}
+
+// decodeNft decodes JSON keeping numbers as json.Number, so counters do
+// not lose precision.
+func decodeNft(raw []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// parseNftRuleset converts nft -j list ruleset output into rules, chain by
+// chain with each base chain's policy after its rules, and the address
+// sets they reference into tables. Tables of families other than ip, ip6
+// and inet do not filter traffic to local sockets and are skipped. Rules
+// are numbered by their handles.
+func parseNftRuleset(out []byte) ([]data.FirewallRule, []data.FirewallTable, error) {
+	var rs nftRuleset
+	if err := decodeNft(out, &rs); err != nil {
+		return nil, nil, err
+	}
+
+	var chains []nftChain
+	rules := make(map[string][]data.FirewallRule)
+	sets := make(map[string][]string)
+	for _, obj := range rs.Nftables {
+		switch {
+		case obj["chain"] != nil:
+			var c nftChain
+			if decodeNft(obj["chain"], &c) == nil && isIPFamily(c.Family) {
+				chains = append(chains, c)
+			}
+		case obj["set"] != nil:
+			var s nftSet
+			if decodeNft(obj["set"], &s) != nil || !isIPFamily(s.Family) {
+				continue
+			}
+			if addrs, ok := nftSetAddrs(s); ok {
+				sets[chainAnchor(s.Family, s.Table, s.Name)] = addrs
+			}
+		case obj["rule"] != nil:
+			var r nftRule
+			if decodeNft(obj["rule"], &r) == nil && isIPFamily(r.Family) {
+				anchor := chainAnchor(r.Family, r.Table, r.Chain)
+				rules[anchor] = append(rules[anchor], convertNftRule(r, anchor))
+			}
+		}
+	}
+
+	var result []data.FirewallRule
+	var tables []data.FirewallTable
+	for _, c := range chains {
+		anchor := chainAnchor(c.Family, c.Table, c.Name)
+		chainRules := rules[anchor]
+		if c.Hook != "" {
+			chainRules = append(chainRules, policyRule(anchor, c.Family, c.Policy))
+		}
+		seen := make(map[string]bool)
+		for i := range chainRules {
+			r := &chainRules[i]
+			r.Ruleset = "filter"
+			if c.Type != "" {
+				r.Ruleset = c.Type
+			}
+			r.Hook, r.Priority, r.Direction = c.Hook, c.Prio, hookDirection(c.Hook)
+			for _, name := range r.Tables {
+				addrs, ok := sets[chainAnchor(c.Family, c.Table, name)]
+				if ok && !seen[name] {
+					seen[name] = true
+					tables = append(tables, data.FirewallTable{Name: name, Anchor: anchor, Addrs: addrs})
+				}
+			}
+		}
+		result = append(result, chainRules...)
+	}
+	return result, tables, nil
+}
+
+func isIPFamily(family string) bool {
+	return family == "ip" || family == "ip6" || family == "inet"
+}
+
+// nftSetAddrs lists the elements of an address set as addresses and
+// networks. It reports false for other sets, and for sets holding
+// elements such as ranges, which cannot be matched.
+func nftSetAddrs(s nftSet) ([]string, bool) {
+	if s.Type != "ipv4_addr" && s.Type != "ipv6_addr" {
+		return nil, false
+	}
+	addrs := make([]string, 0, len(s.Elem))
+	for _, e := range s.Elem {
+		// Elements with a timeout or comment are wrapped.
+		if m, ok := e.(map[string]any); ok {
+			if elem, ok := m["elem"].(map[string]any); ok {
+				e = elem["val"]
+			}
+		}
+		addr, ok := nftAddr(e)
+		if !ok {
+			return nil, false
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, true
+}
+
+// nftAddr returns an address or prefix value as text.
+func nftAddr(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, !strings.HasPrefix(v, "@")
+	case map[string]any:
+		if p, ok := v["prefix"].(map[string]any); ok {
+			return fmt.Sprintf("%v/%v", p["addr"], p["len"]), true
+		}
+	}
+	return "", false
+}
+
+// convertNftRule converts the expressions of an nftables rule. Matches on
+// addresses, ports, protocol, input interface and conntrack state become
+// criteria and other matches Extra; the verdict becomes the action, and
+// statements such as log or counter fill in options.
+func convertNftRule(nr nftRule, anchor string) data.FirewallRule {
+	r := data.FirewallRule{
+		RuleNum: nr.Handle,
+		Anchor:  anchor,
+		Family:  familyName(nr.Family),
+		Label:   nr.Comment,
+	}
+	var text, extra []string
+	for _, e := range nr.Expr {
+		for kind, v := range e {
+			t := nftExprText(kind, v)
+			text = append(text, t)
+			switch kind {
+			case "match":
+				if !nftMatch(v, &r) {
+					extra = append(extra, t)
+				}
+			case "counter":
+				if c, ok := v.(map[string]any); ok {
+					r.Packets = nftUint(c["packets"])
+					r.Bytes = nftUint(c["bytes"])
+				}
+			case "accept":
+				r.Action, r.Quick = "pass", true
+			case "drop", "reject":
+				r.Action, r.Quick = "block", true
+			case "jump", "goto":
+				r.Action = kind
+				if j, ok := v.(map[string]any); ok {
+					r.AnchorCall = "/" + chainAnchor(nr.Family, nr.Table, fmt.Sprint(j["target"]))
+				}
+			case "return":
+				r.Action = kind
+			case "log":
+				r.Log = "log"
+			case "limit", "quota":
+				extra = append(extra, t)
+			case "xt":
+				// An iptables extension kept by iptables-nft.
+				xt, _ := v.(map[string]any)
+				if xt["type"] == "target" {
+					r.Action = strings.ToLower(fmt.Sprint(xt["name"]))
+				} else {
+					extra = append(extra, t)
+				}
+			case "snat", "dnat", "redirect":
+				r.Action, r.Translation = kind, strings.TrimPrefix(t, kind+" ")
+			default:
+				if r.Action == "" {
+					r.Action = kind
+				}
+			}
+		}
+	}
+	if r.Action == "" {
+		r.Action = "match"
+	}
+	r.Extra = strings.Join(extra, " ")
+	r.Src, r.Dst = r.From.String(), r.To.String()
+	r.RawRule = strings.Join(text, " ")
+	return r
+}
+
+// nftMatch sets the criterion of a match expression on r. It reports
+// false for matches it does not evaluate.
+func nftMatch(v any, r *data.FirewallRule) bool {
+	m, _ := v.(map[string]any)
+	op, _ := m["op"].(string)
+	left, _ := m["left"].(map[string]any)
+	right := m["right"]
+	not := op == "!="
+	if op != "==" && op != "!=" && op != "in" {
+		// Only ports are compared by order.
+		p, _ := left["payload"].(map[string]any)
+		if field := p["field"]; field != "sport" && field != "dport" {
+			return false
+		}
+	}
+
+	if p, ok := left["payload"].(map[string]any); ok {
+		proto, _ := p["protocol"].(string)
+		switch field, _ := p["field"].(string); field {
+		case "saddr", "daddr":
+			if proto != "ip" && proto != "ip6" {
+				return false
+			}
+			spec := &r.From
+			if field == "daddr" {
+				spec = &r.To
+			}
+			spec.Not = not
+			spec.Addr = nftValueText(right)
+			if addr, ok := nftAddr(right); ok {
+				spec.Addr = addr
+			} else if name, ok := strings.CutPrefix(spec.Addr, "@"); ok {
+				spec.Addr = "<" + name + ">"
+				r.Tables = appendUnique(r.Tables, name)
+			}
+			r.Family = familyName(proto)
+		case "sport", "dport":
+			if proto != "th" && r.Proto == "" {
+				r.Proto = proto
+			}
+			ps := nftPortSpec(op, right, proto)
+			if field == "sport" {
+				r.From.Port = ps
+			} else {
+				r.To.Port = ps
+			}
+		case "protocol", "nexthdr":
+			if not {
+				return false
+			}
+			r.Proto = nftValueText(right)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if meta, ok := left["meta"].(map[string]any); ok {
+		switch meta["key"] {
+		case "l4proto":
+			if not {
+				return false
+			}
+			r.Proto = nftValueText(right)
+		case "nfproto":
+			s, _ := right.(string)
+			if not || familyName(s) == "" {
+				return false
+			}
+			r.Family = familyName(s)
+		case "iifname", "iif":
+			s, ok := right.(string)
+			if !ok || strings.Contains(s, "*") {
+				return false
+			}
+			if not {
+				s = "! " + s
+			}
+			r.Iface = s
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ct, ok := left["ct"].(map[string]any); ok && ct["key"] == "state" {
+		var states []string
+		switch right := right.(type) {
+		case string:
+			states = []string{right}
+		case []any:
+			for _, s := range right {
+				states = append(states, fmt.Sprint(s))
+			}
+		default:
+			return false
+		}
+		r.CtState = ctStateList(states, not)
+		return true
+	}
+	return false
+}
+
+// nftPortSpec converts the right side of a port match: a port, a range
+// or an anonymous set of either.
+func nftPortSpec(op string, right any, proto string) data.PortSpec {
+	ps := data.PortSpec{Op: "?", Name: nftValueText(right)}
+	if op == "in" {
+		op = "=="
+	}
+	switch v := right.(type) {
+	case json.Number, string:
+		n, err := lookupPort(proto, fmt.Sprint(v))
+		if err != nil {
+			return ps
+		}
+		ps.Op, ps.Lo = op, n
+		if op == "==" {
+			ps.Op = "="
+		}
+	case map[string]any:
+		if rg, ok := v["range"].([]any); ok && len(rg) == 2 && (op == "==" || op == "!=") {
+			lo, err1 := lookupPort(proto, fmt.Sprint(rg[0]))
+			hi, err2 := lookupPort(proto, fmt.Sprint(rg[1]))
+			if err1 != nil || err2 != nil {
+				return ps
+			}
+			ps.Op, ps.Lo, ps.Hi = ":", lo, hi
+			if op == "!=" {
+				ps.Op = "<>"
+			}
+		}
+		if set, ok := v["set"].([]any); ok && op == "==" {
+			ps.Op = "{"
+			for _, e := range set {
+				ps.List = append(ps.List, nftPortSpec("==", e, proto))
+			}
+		}
+	}
+	return ps
+}
+
+// nftExprText renders an expression of nft -j output roughly the way nft
+// lists rules, like "tcp dport 22" or "jump tcp_in".
+func nftExprText(kind string, v any) string {
+	switch kind {
+	case "match":
+		m, _ := v.(map[string]any)
+		op, _ := m["op"].(string)
+		s := nftValueText(m["left"])
+		if op != "==" && op != "in" {
+			s += " " + op
+		}
+		return s + " " + nftValueText(m["right"])
+	case "counter":
+		return "counter"
+	case "limit":
+		if l, ok := v.(map[string]any); ok {
+			return fmt.Sprintf("limit rate %v/%v", l["rate"], l["per"])
+		}
+	case "jump", "goto":
+		if j, ok := v.(map[string]any); ok {
+			return kind + " " + fmt.Sprint(j["target"])
+		}
+	}
+	if s := nftValueText(v); s != "" {
+		return kind + " " + s
+	}
+	return kind
+}
+
+// nftValueText renders a value of nft -j output, with the keys of
+// objects it does not know sorted so the text is the same every refresh.
+func nftValueText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		items := make([]string, len(v))
+		for i, e := range v {
+			items[i] = nftValueText(e)
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	case map[string]any:
+		switch {
+		case v["payload"] != nil:
+			p, _ := v["payload"].(map[string]any)
+			return fmt.Sprintf("%v %v", p["protocol"], p["field"])
+		case v["meta"] != nil:
+			m, _ := v["meta"].(map[string]any)
+			switch key := fmt.Sprint(m["key"]); key {
+			case "iifname", "oifname", "iif", "oif":
+				return key
+			default:
+				return "meta " + key
+			}
+		case v["ct"] != nil:
+			m, _ := v["ct"].(map[string]any)
+			return fmt.Sprintf("ct %v", m["key"])
+		case v["prefix"] != nil:
+			addr, _ := nftAddr(v)
+			return addr
+		case v["range"] != nil:
+			rg, _ := v["range"].([]any)
+			if len(rg) == 2 {
+				return nftValueText(rg[0]) + "-" + nftValueText(rg[1])
+			}
+		case v["set"] != nil:
+			if set, ok := v["set"].([]any); ok {
+				return nftValueText(set)
+			}
+			return "{ " + nftValueText(v["set"]) + " }"
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, strings.TrimSpace(k+" "+nftValueText(v[k])))
+		}
+		return strings.Join(parts, " ")
+	}
+	return fmt.Sprint(v)
+}
+
+// nftUint returns a counter of nft -j output.
+func nftUint(v any) uint64 {
+	n, _ := strconv.ParseUint(fmt.Sprint(v), 10, 64)
+	return n
+}
+
+// iptablesPriority gives the priority the chains of an iptables table run
+// at, numbered the way nftables numbers them.
+func iptablesPriority(table, hook string) int {
+	switch table {
+	case "raw":
+		return -300
+	case "mangle":
+		return -150
+	case "nat":
+		if hook == "prerouting" || hook == "output" {
+			return -100
+		}
+		return 100
+	case "security":
+		return 50
+	}
+	return 0
+}
+
+// parseIptablesSave parses iptables-save -c output of family ip, or ip6
+// for ip6tables-save, chain by chain with each built-in chain's policy
+// after its rules. Rules are numbered from 1 in each chain, as iptables
+// -L --line-numbers lists them, and the table is the ruleset.
+func parseIptablesSave(out, family string) []data.FirewallRule {
+	var result []data.FirewallRule
+	var table string
+	var chains []string
+	rules := make(map[string][]data.FirewallRule)
+	policies := make(map[string]data.FirewallRule)
+	commit := func() {
+		for _, chain := range chains {
+			chainRules := rules[chain]
+			p, builtin := policies[chain]
+			if builtin {
+				chainRules = append(chainRules, p)
+			}
+			for i := range chainRules {
+				r := &chainRules[i]
+				r.Ruleset = table
+				if builtin {
+					r.Hook = iptablesHooks[chain]
+					r.Priority = iptablesPriority(table, r.Hook)
+					r.Direction = hookDirection(r.Hook)
+				}
+			}
+			result = append(result, chainRules...)
+		}
+		chains = nil
+		rules = make(map[string][]data.FirewallRule)
+		policies = make(map[string]data.FirewallRule)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = line[1:]
+		case strings.HasPrefix(line, ":"):
+			// A chain, with its policy and counters if built in:
+			// ":INPUT DROP [30:1800]" or ":SSH - [0:0]".
+			f := strings.Fields(line[1:])
+			if len(f) < 2 {
+				continue
+			}
+			chains = append(chains, f[0])
+			if _, ok := iptablesHooks[f[0]]; ok && f[1] != "-" {
+				p := policyRule(chainAnchor(family, table, f[0]), family, f[1])
+				if len(f) > 2 {
+					packets, bytes, _ := strings.Cut(strings.Trim(f[2], "[]"), ":")
+					p.Packets, _ = strconv.ParseUint(packets, 10, 64)
+					p.Bytes, _ = strconv.ParseUint(bytes, 10, 64)
+				}
+				policies[f[0]] = p
+			}
+		case line == "COMMIT":
+			commit()
+		default:
+			var packets, bytes uint64
+			if m := iptablesCountersRe.FindStringSubmatch(line); m != nil {
+				packets, _ = strconv.ParseUint(m[1], 10, 64)
+				bytes, _ = strconv.ParseUint(m[2], 10, 64)
+				line = m[3]
+			}
+			if !strings.HasPrefix(line, "-A ") {
+				continue
+			}
+			chain, r := parseIptablesRule(line, family, table, chains)
+			r.RuleNum = len(rules[chain]) + 1
+			r.Packets, r.Bytes = packets, bytes
+			rules[chain] = append(rules[chain], r)
+		}
+	}
+	commit()
+	return result
+}
+
+// parseIptablesRule parses a rule as iptables-save lists it, like
+// "-A INPUT -p tcp -m tcp --dport 22 -j ACCEPT", returning its chain.
+// Options it does not evaluate, such as -o or --limit, become Extra.
+// chains are the chains of the table, which -j and -g can jump to.
+func parseIptablesRule(line, family, table string, chains []string) (string, data.FirewallRule) {
+	r := data.FirewallRule{Family: familyName(family), RawRule: line}
+	f := tokenizeRule(line)
+	var chain string
+	var extra []string
+	not := false
+	for i := 0; i < len(f); i++ {
+		opt := f[i]
+		arg := func() string {
+			if i+1 < len(f) {
+				i++
+				return f[i]
+			}
+			return ""
+		}
+		switch opt {
+		case "!":
+			not = true
+			continue
+		case "-A", "--append":
+			chain = arg()
+		case "-c", "--set-counters":
+			r.Packets, _ = strconv.ParseUint(arg(), 10, 64)
+			r.Bytes, _ = strconv.ParseUint(arg(), 10, 64)
+		case "-s", "--source":
+			r.From.Addr, r.From.Not = arg(), not
+		case "-d", "--destination":
+			r.To.Addr, r.To.Not = arg(), not
+		case "-p", "--protocol":
+			if p := arg(); not {
+				extra = append(extra, "! -p "+p)
+			} else if p != "all" {
+				r.Proto = p
+			}
+		case "-i", "--in-interface":
+			name := arg()
+			switch {
+			case strings.HasSuffix(name, "+"):
+				extra = append(extra, strings.TrimPrefix(notText(not)+" -i "+name, " "))
+			case not:
+				r.Iface = "! " + name
+			default:
+				r.Iface = name
+			}
+		case "-m", "--match":
+			arg() // the module is named by the options that follow
+		case "--dport", "--destination-port", "--sport", "--source-port":
+			ps := iptablesPortSpec(arg(), not, r.Proto)
+			if opt == "--sport" || opt == "--source-port" {
+				r.From.Port = ps
+			} else {
+				r.To.Port = ps
+			}
+		case "--dports", "--destination-ports", "--sports", "--source-ports":
+			ps := data.PortSpec{Op: "{", Name: "{ " + strings.ReplaceAll(arg(), ",", ", ") + " }"}
+			for _, p := range strings.Split(strings.Trim(ps.Name, "{ }"), ", ") {
+				ps.List = append(ps.List, iptablesPortSpec(p, false, r.Proto))
+			}
+			if not {
+				ps.Op = "?"
+			}
+			if opt == "--sports" || opt == "--source-ports" {
+				r.From.Port = ps
+			} else {
+				r.To.Port = ps
+			}
+		case "--ctstate", "--state":
+			r.CtState = ctStateList(strings.Split(arg(), ","), not)
+		case "--match-set":
+			name, dirs := arg(), arg()
+			spec := &r.To
+			if strings.HasPrefix(dirs, "src") {
+				spec = &r.From
+			}
+			spec.Addr, spec.Not = "<"+name+">", not
+			r.Tables = appendUnique(r.Tables, name)
+		case "--comment":
+			r.Label = arg()
+		case "--syn":
+			r.Flags = "SYN/FIN,SYN,RST,ACK"
+		case "--tcp-flags":
+			mask := arg()
+			r.Flags = arg() + "/" + mask
+		case "-j", "--jump", "-g", "--goto":
+			target := arg()
+			iptablesTarget(&r, target, opt == "-g" || opt == "--goto", chainAnchor(family, table, ""), chains)
+			r.Translation = iptablesTranslation(f[i+1:])
+			i = len(f)
+		default:
+			if !strings.HasPrefix(opt, "-") {
+				continue
+			}
+			s := strings.TrimPrefix(notText(not)+" "+opt, " ")
+			for i+1 < len(f) && !strings.HasPrefix(f[i+1], "-") && f[i+1] != "!" {
+				i++
+				s += " " + f[i]
+			}
+			extra = append(extra, s)
+		}
+		not = false
+	}
+	if r.Action == "" {
+		r.Action = "match"
+	}
+	r.Extra = strings.Join(extra, " ")
+	r.Src, r.Dst = r.From.String(), r.To.String()
+	r.Anchor = chainAnchor(family, table, chain)
+	return chain, r
+}
+
+func notText(not bool) string {
+	if not {
+		return "!"
+	}
+	return ""
+}
+
+// iptablesTarget sets the action of a rule from its -j or -g target.
+// Targets naming a chain of the table jump to it; prefix is the anchor
+// of the table's chains without the chain name.
+func iptablesTarget(r *data.FirewallRule, target string, isGoto bool, prefix string, chains []string) {
+	switch target {
+	case "ACCEPT":
+		r.Action, r.Quick = "pass", true
+		return
+	case "DROP", "REJECT":
+		r.Action, r.Quick = "block", true
+		return
+	case "RETURN":
+		r.Action = "return"
+		return
+	case "LOG":
+		r.Log = "log"
+	}
+	for _, c := range chains {
+		if c == target {
+			r.Action, r.AnchorCall = "jump", "/"+prefix+target
+			if isGoto {
+				r.Action = "goto"
+			}
+			return
+		}
+	}
+	r.Action = strings.ToLower(target)
+}
+
+// iptablesTranslation returns the address a NAT target translates to.
+func iptablesTranslation(opts []string) string {
+	for i, o := range opts {
+		switch o {
+		case "--to-destination", "--to-source", "--to-ports", "--to":
+			if i+1 < len(opts) {
+				return opts[i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// iptablesPortSpec parses a port or "lo:hi" range of an iptables rule.
+func iptablesPortSpec(spec string, not bool, proto string) data.PortSpec {
+	ps := parsePortSpec([]string{spec}, proto)
+	if !not {
+		return ps
+	}
+	ps.Name = "! " + ps.Name
+	switch ps.Op {
+	case "=":
+		ps.Op = "!="
+	case ":":
+		ps.Op = "<>"
+	}
+	return ps
+}
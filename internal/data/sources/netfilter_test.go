@@ -0,0 +1,237 @@
+package sources
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// nftFixture is nft -j list ruleset output, with line breaks added, for:
+//
+//	table inet filter {
+//		set blocklist {
+//			type ipv4_addr
+//			flags interval
+//			elements = { 198.51.100.0/24, 203.0.113.7 }
+//		}
+//		chain input {
+//			type filter hook input priority filter; policy drop;
+//			ct state established,related accept
+//			iifname "lo" accept
+//			ct state invalid drop
+//			ip saddr @blocklist counter packets 3 bytes 180 drop
+//			tcp dport { 22, 80 } accept
+//			meta l4proto tcp jump tcp_in
+//			limit rate 5/second log prefix "drop: "
+//		}
+//		chain tcp_in {
+//			ip saddr 10.0.0.0/8 tcp dport 8080 accept comment "intranet"
+//			return
+//		}
+//	}
+//	table ip nat {
+//		chain postrouting {
+//			type nat hook postrouting priority srcnat; policy accept;
+//			oifname "eth0" masquerade
+//		}
+//	}
+//	table inet nettui {
+//		chain input {
+//			type filter hook input priority -10; policy accept;
+//			ip saddr 203.0.113.6 accept
+//		}
+//	}
+const nftFixture = `{"nftables": [
+{"metainfo": {"version": "1.0.9", "release_name": "Old Doc Yak #3", "json_schema_version": 1}},
+{"table": {"family": "inet", "name": "filter", "handle": 1}},
+{"set": {"family": "inet", "name": "blocklist", "table": "filter", "type": "ipv4_addr", "handle": 2, "flags": ["interval"], "elem": [{"prefix": {"addr": "198.51.100.0", "len": 24}}, "203.0.113.7"]}},
+{"chain": {"family": "inet", "table": "filter", "name": "input", "handle": 3, "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}},
+{"chain": {"family": "inet", "table": "filter", "name": "tcp_in", "handle": 4}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 5, "expr": [{"match": {"op": "in", "left": {"ct": {"key": "state"}}, "right": ["established", "related"]}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 6, "expr": [{"match": {"op": "==", "left": {"meta": {"key": "iifname"}}, "right": "lo"}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 7, "expr": [{"match": {"op": "in", "left": {"ct": {"key": "state"}}, "right": "invalid"}}, {"drop": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 8, "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": "@blocklist"}}, {"counter": {"packets": 3, "bytes": 180}}, {"drop": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 9, "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": {"set": [22, 80]}}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 10, "expr": [{"match": {"op": "==", "left": {"meta": {"key": "l4proto"}}, "right": "tcp"}}, {"jump": {"target": "tcp_in"}}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 11, "expr": [{"limit": {"rate": 5, "burst": 5, "per": "second"}}, {"log": {"prefix": "drop: "}}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "tcp_in", "handle": 12, "comment": "intranet", "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": {"prefix": {"addr": "10.0.0.0", "len": 8}}}}, {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": 8080}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "filter", "chain": "tcp_in", "handle": 13, "expr": [{"return": null}]}},
+{"table": {"family": "ip", "name": "nat", "handle": 14}},
+{"chain": {"family": "ip", "table": "nat", "name": "postrouting", "handle": 1, "type": "nat", "hook": "postrouting", "prio": 100, "policy": "accept"}},
+{"rule": {"family": "ip", "table": "nat", "chain": "postrouting", "handle": 2, "expr": [{"match": {"op": "==", "left": {"meta": {"key": "oifname"}}, "right": "eth0"}}, {"masquerade": null}]}},
+{"table": {"family": "inet", "name": "nettui", "handle": 15}},
+{"chain": {"family": "inet", "table": "nettui", "name": "input", "handle": 1, "type": "filter", "hook": "input", "prio": -10, "policy": "accept"}},
+{"rule": {"family": "inet", "table": "nettui", "chain": "input", "handle": 2, "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": "203.0.113.6"}}, {"accept": null}]}}
+]}`
+
+// iptablesFixture is iptables-save -c output.
+const iptablesFixture = `# Generated by iptables-save v1.8.9 (nf_tables) on Mon Oct 19 10:00:00 2026
+*nat
+:PREROUTING ACCEPT [120:7680]
+:INPUT ACCEPT [0:0]
+:OUTPUT ACCEPT [40:2400]
+:POSTROUTING ACCEPT [40:2400]
+[12:720] -A POSTROUTING -s 172.17.0.0/16 ! -o docker0 -j MASQUERADE
+COMMIT
+# Completed on Mon Oct 19 10:00:00 2026
+# Generated by iptables-save v1.8.9 (nf_tables) on Mon Oct 19 10:00:00 2026
+*filter
+:INPUT DROP [30:1800]
+:FORWARD DROP [0:0]
+:OUTPUT ACCEPT [900:81000]
+:SSH - [0:0]
+[800:64000] -A INPUT -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT
+[4:240] -A INPUT -i lo -j ACCEPT
+[0:0] -A INPUT -m conntrack --ctstate INVALID -j DROP
+[2:120] -A INPUT -p tcp -m tcp --dport 22 -j SSH
+[6:360] -A INPUT -p tcp -m multiport --dports 80,443 -m comment --comment "web server" -j ACCEPT
+[1:60] -A INPUT -p tcp -m tcp --dport 5432 -m limit --limit 5/min -j LOG --log-prefix "pg: "
+[0:0] -A INPUT -p icmp -m icmp --icmp-type 8 -j ACCEPT
+[2:120] -A SSH -m set --match-set blocked src -j DROP
+[2:120] -A SSH -j ACCEPT
+COMMIT
+# Completed on Mon Oct 19 10:00:00 2026
+`
+
+// findRule returns the rule with the given Ref.
+func findRule(t *testing.T, rules []data.FirewallRule, ref string) data.FirewallRule {
+	t.Helper()
+	for _, r := range rules {
+		if r.Ref() == ref {
+			return r
+		}
+	}
+	t.Fatalf("no rule %s", ref)
+	return data.FirewallRule{}
+}
+
+// listener returns a TCP listener on all IPv4 addresses.
+func listener(port uint32) data.Listener {
+	return data.Listener{Proto: "tcp", Port: port, Addrs: []string{"0.0.0.0"}, Sockets: []data.Socket{{Proto: "tcp"}}}
+}
+
+func TestParseNftRuleset(t *testing.T) {
+	rules, tables, err := parseNftRuleset([]byte(nftFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 14 {
+		t.Fatalf("got %d rules, want 14", len(rules))
+	}
+
+	policy := findRule(t, rules, "inet filter/input@0")
+	if !policy.Policy || policy.Action != "block" || policy.Hook != "input" || policy.RawRule != "policy drop" {
+		t.Errorf("policy: %+v", policy)
+	}
+	if r := findRule(t, rules, "inet filter/input@5"); r.CtState != "established,related" || r.Action != "pass" || !r.Quick {
+		t.Errorf("ct state rule: %+v", r)
+	}
+	if r := findRule(t, rules, "inet filter/input@6"); r.Iface != "lo" || r.RawRule != "iifname lo accept" {
+		t.Errorf("iifname rule: %+v", r)
+	}
+	r := findRule(t, rules, "inet filter/input@8")
+	if r.From.Addr != "<blocklist>" || r.Family != "inet" || r.Packets != 3 || r.Bytes != 180 || r.Action != "block" {
+		t.Errorf("set rule: %+v", r)
+	}
+	r = findRule(t, rules, "inet filter/input@9")
+	if r.Proto != "tcp" || r.To.Port.Op != "{" || r.To.Port.Match(80) != data.MatchYes || r.To.Port.Match(443) != data.MatchNo {
+		t.Errorf("port set rule: %+v", r)
+	}
+	if r := findRule(t, rules, "inet filter/input@10"); r.Action != "jump" || r.AnchorCall != "/inet filter/tcp_in" {
+		t.Errorf("jump rule: %+v", r)
+	}
+	if r := findRule(t, rules, "inet filter/input@11"); r.Action != "match" || r.Log != "log" || r.Extra != "limit rate 5/second" {
+		t.Errorf("log rule: %+v", r)
+	}
+	r = findRule(t, rules, "inet filter/tcp_in@12")
+	if r.From.Addr != "10.0.0.0/8" || r.To.Port.Op != "=" || r.To.Port.Lo != 8080 || r.Label != "intranet" || r.Hook != "" {
+		t.Errorf("tcp_in rule: %+v", r)
+	}
+	if r := findRule(t, rules, "ip nat/postrouting/nat@2"); r.Action != "masquerade" || r.Extra != "oifname eth0" {
+		t.Errorf("nat rule: %+v", r)
+	}
+
+	if len(tables) != 1 || tables[0].Name != "blocklist" || tables[0].Anchor != "inet filter/input" || len(tables[0].Addrs) != 2 || tables[0].Addrs[0] != "198.51.100.0/24" {
+		t.Errorf("tables: %+v", tables)
+	}
+
+	// The filter chain drops what it does not accept, whatever the nettui
+	// chain before it accepts, and the blocklist leaves every port it
+	// accepts unknown.
+	for _, tt := range []struct {
+		port uint32
+		want data.Verdict
+		rule string
+	}{
+		{22, data.VerdictUnknown, "inet filter/input@8"},
+		{8080, data.VerdictUnknown, "inet filter/tcp_in@12"},
+		{9999, data.VerdictBlocked, "inet filter/input@0"},
+	} {
+		if v, ref := data.InboundVerdict(rules, listener(tt.port)); v != tt.want || ref != tt.rule {
+			t.Errorf("port %d: %s by %s, want %s by %s", tt.port, v, ref, tt.want, tt.rule)
+		}
+	}
+	for _, tt := range []struct {
+		src  string
+		port uint32
+		want data.Verdict
+		rule string
+	}{
+		{"192.0.2.1", 22, data.VerdictAllowed, "inet filter/input@9"},
+		{"198.51.100.9", 22, data.VerdictBlocked, "inet filter/input@8"},
+		{"10.1.2.3", 8080, data.VerdictAllowed, "inet filter/tcp_in@12"},
+		{"192.0.2.1", 8080, data.VerdictBlocked, "inet filter/input@0"},
+		{"203.0.113.6", 9999, data.VerdictBlocked, "inet filter/input@0"},
+	} {
+		pkt := data.Packet{Dir: "in", Proto: "tcp", Iface: "eth0", Src: net.ParseIP(tt.src), Dst: net.ParseIP("192.0.2.10"), DstPort: tt.port}
+		if tr := data.TraceRules(rules, tables, pkt); tr.Verdict != tt.want || tr.Rule != tt.rule {
+			t.Errorf("trace %s: %s by %s, want %s by %s", pkt, tr.Verdict, tr.Rule, tt.want, tt.rule)
+		}
+	}
+}
+
+func TestParseIptablesSave(t *testing.T) {
+	rules := parseIptablesSave(iptablesFixture, "ip")
+	if len(rules) != 17 {
+		t.Fatalf("got %d rules, want 17", len(rules))
+	}
+
+	policy := findRule(t, rules, "ip filter/INPUT@0")
+	if !policy.Policy || policy.Action != "block" || policy.Packets != 30 || policy.Bytes != 1800 || policy.Ruleset != "filter" {
+		t.Errorf("policy: %+v", policy)
+	}
+	if r := findRule(t, rules, "ip filter/INPUT@1"); r.CtState != "related,established" || r.Packets != 800 || r.Bytes != 64000 || r.Family != "inet" {
+		t.Errorf("conntrack rule: %+v", r)
+	}
+	if r := findRule(t, rules, "ip filter/INPUT@4"); r.Action != "jump" || r.AnchorCall != "/ip filter/SSH" || r.Proto != "tcp" || r.To.Port.Lo != 22 {
+		t.Errorf("jump rule: %+v", r)
+	}
+	r := findRule(t, rules, "ip filter/INPUT@5")
+	if r.Label != "web server" || r.To.Port.Op != "{" || r.To.Port.Match(443) != data.MatchYes || r.To.Port.Match(8443) != data.MatchNo {
+		t.Errorf("multiport rule: %+v", r)
+	}
+	if r := findRule(t, rules, "ip filter/INPUT@6"); r.Action != "log" || r.Extra != "--limit 5/min" {
+		t.Errorf("log rule: %+v", r)
+	}
+	if r := findRule(t, rules, "ip filter/SSH@1"); r.From.Addr != "<blocked>" || r.Hook != "" || r.Action != "block" {
+		t.Errorf("set rule: %+v", r)
+	}
+	if r := findRule(t, rules, "ip nat/POSTROUTING/nat@1"); r.Action != "masquerade" || r.Extra != "! -o docker0" || r.From.Addr != "172.17.0.0/16" {
+		t.Errorf("nat rule: %+v", r)
+	}
+
+	for _, tt := range []struct {
+		port uint32
+		want data.Verdict
+		rule string
+	}{
+		{22, data.VerdictUnknown, "ip filter/SSH@1"},
+		{443, data.VerdictAllowed, "ip filter/INPUT@5"},
+		{5432, data.VerdictBlocked, "ip filter/INPUT@0"},
+	} {
+		if v, ref := data.InboundVerdict(rules, listener(tt.port)); v != tt.want || ref != tt.rule {
+			t.Errorf("port %d: %s by %s, want %s by %s", tt.port, v, ref, tt.want, tt.rule)
+		}
+	}
+}
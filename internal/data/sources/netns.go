@@ -0,0 +1,35 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+type netnsKey struct{}
+
+// withNamespace returns the context for collecting inside ns from a thread
+// that has entered it. gopsutil is pointed at /proc/thread-self, since
+// /proc/self follows the main thread, which stays in nettui's namespace.
+func withNamespace(ctx context.Context, ns data.Namespace) context.Context {
+	ctx = context.WithValue(ctx, common.EnvKey, common.EnvMap{common.HostProcEnvKey: "/proc/thread-self"})
+	return context.WithValue(ctx, netnsKey{}, ns)
+}
+
+// foreignNamespace reports whether ctx collects from a namespace other
+// than nettui's own.
+func foreignNamespace(ctx context.Context) bool {
+	ns, ok := ctx.Value(netnsKey{}).(data.Namespace)
+	return ok && !ns.Self
+}
+
+// RunInNamespace runs fn inside ns, entering it on a dedicated thread
+// unless it is nettui's own namespace or has no path to enter it through.
+func RunInNamespace(ns data.Namespace, fn func()) error {
+	if ns.Self || ns.Path == "" {
+		fn()
+		return nil
+	}
+	return inNamespace(ns.Path, fn)
+}
@@ -0,0 +1,156 @@
+//go:build linux
+
+package sources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ListNamespaces discovers the network namespaces on the host: the ones
+// named under /run/netns, and the ones processes live in according to
+// /proc/<pid>/ns/net. nettui's own namespace comes first, then named
+// namespaces, then the rest in order of their lowest PID. Without root,
+// only namespaces of the user's own processes are visible.
+func ListNamespaces() ([]data.Namespace, []data.CollectionError) {
+	self, err := nsInode("/proc/self/ns/net")
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "netns", Error: fmt.Sprintf("stat /proc/self/ns/net: %v", err)}}
+	}
+
+	byID := make(map[uint64]*data.Namespace)
+	var order []uint64
+	add := func(id uint64, ns data.Namespace) *data.Namespace {
+		if existing, ok := byID[id]; ok {
+			return existing
+		}
+		ns.ID = id
+		byID[id] = &ns
+		order = append(order, id)
+		return &ns
+	}
+
+	add(self, data.Namespace{Name: "self", Path: "/proc/self/ns/net", Self: true})
+
+	if entries, err := os.ReadDir("/run/netns"); err == nil {
+		for _, e := range entries {
+			path := filepath.Join("/run/netns", e.Name())
+			id, err := nsInode(path)
+			if err != nil {
+				continue
+			}
+			add(id, data.Namespace{Name: e.Name(), Path: path})
+		}
+	}
+
+	for _, pid := range procPIDs() {
+		dir := filepath.Join("/proc", strconv.Itoa(pid))
+		path := filepath.Join(dir, "ns", "net")
+		id, err := nsInode(path)
+		if err != nil {
+			continue
+		}
+		ns, ok := byID[id]
+		if !ok {
+			comm, _ := os.ReadFile(filepath.Join(dir, "comm"))
+			ns = add(id, data.Namespace{
+				Name: fmt.Sprintf("pid %d (%s)", pid, strings.TrimSpace(string(comm))),
+				Path: path,
+			})
+		}
+		ns.PIDs = append(ns.PIDs, int32(pid))
+	}
+
+	result := make([]data.Namespace, 0, len(order))
+	for _, id := range order {
+		result = append(result, *byID[id])
+	}
+	return result, nil
+}
+
+// procPIDs lists the PIDs under /proc in numeric order.
+func procPIDs() []int {
+	entries, _ := os.ReadDir("/proc")
+	pids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if pid, err := strconv.Atoi(e.Name()); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+func nsInode(path string) (uint64, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Ino, nil
+}
+
+// inNamespace runs fn on an OS thread that has entered the network
+// namespace at path. Netlink sockets, /proc/thread-self/net and child
+// processes started by fn all see that namespace.
+func inNamespace(path string, fn func()) error {
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		orig, err := os.Open("/proc/thread-self/ns/net")
+		if err != nil {
+			runtime.UnlockOSThread()
+			errc <- err
+			return
+		}
+		defer orig.Close()
+		target, err := os.Open(path)
+		if err != nil {
+			runtime.UnlockOSThread()
+			errc <- err
+			return
+		}
+		defer target.Close()
+
+		if err := unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+			runtime.UnlockOSThread()
+			errc <- fmt.Errorf("setns %s: %w", path, err)
+			return
+		}
+		fn()
+		// A thread that cannot get back stays locked, so the runtime
+		// retires it when this goroutine exits instead of reusing it.
+		if err := unix.Setns(int(orig.Fd()), unix.CLONE_NEWNET); err == nil {
+			runtime.UnlockOSThread()
+		}
+		errc <- nil
+	}()
+	return <-errc
+}
+
+// namespaceLabels maps the namespace IDs assigned by the current network
+// namespace to the names of namespaces, as ListNamespaces gives them.
+func namespaceLabels(namespaces []data.Namespace) map[int]string {
+	labels := make(map[int]string)
+	for _, ns := range namespaces {
+		f, err := os.Open(ns.Path)
+		if err != nil {
+			continue
+		}
+		if id, err := netlink.GetNetNsIdByFd(int(f.Fd())); err == nil && id >= 0 {
+			labels[id] = ns.Name
+		}
+		f.Close()
+	}
+	return labels
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package sources
+
+import (
+	"errors"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// ListNamespaces reports no namespaces outside Linux.
+func ListNamespaces() ([]data.Namespace, []data.CollectionError) {
+	return nil, nil
+}
+
+func inNamespace(path string, fn func()) error {
+	return errors.New("network namespaces not supported on this platform")
+}
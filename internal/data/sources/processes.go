@@ -2,44 +2,188 @@ package sources
 
 import (
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/jerryluo/nettui/internal/data"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
-// CollectProcesses gathers all running processes.
+// resourceRounds is how many refreshes it takes to re-read the working
+// directory, thread count and open descriptors of every process: each
+// refresh reads them for the PIDs whose turn it is.
+const resourceRounds = 5
+
+// ProcessCache remembers the metadata of running processes that does not
+// change over their lifetime, so each refresh only fetches it for processes
+// that started since the last one. Entries are keyed by PID and start time,
+// which tells a reused PID from the process that held it before, and by the
+// kernel's command name, which an execve replaces. The file descriptor
+// limit is cached too: services set it at startup, and reading it costs as
+// much as the rest.
+type ProcessCache struct {
+	entries map[int32]processMeta
+	round   int32
+}
+
+// processMeta is the per-process metadata the cache keeps.
+type processMeta struct {
+	createTime int64  // ms since the epoch, as gopsutil reports it
+	comm       string // command name as the kernel keeps it
+	name       string
+	cmdline    string
+	user       string
+	exe        string
+	fdLimit    uint64
+
+	// Slow-changing resources, re-read every resourceRounds refreshes.
+	cwd        string
+	numThreads int32
+	numFDs     int32
+}
+
+// NewProcessCache creates an empty ProcessCache.
+func NewProcessCache() *ProcessCache {
+	return &ProcessCache{entries: make(map[int32]processMeta)}
+}
+
+// CollectProcesses gathers all running processes without caching.
 func CollectProcesses() ([]data.Process, []data.CollectionError) {
-	var errs []data.CollectionError
+	return NewProcessCache().Collect()
+}
 
+// Collect gathers all running processes, reusing cached metadata for those
+// seen before and evicting processes that have exited.
+func (pc *ProcessCache) Collect() ([]data.Process, []data.CollectionError) {
 	procs, err := process.Processes()
 	if err != nil {
 		return nil, []data.CollectionError{{Source: "processes", Error: fmt.Sprintf("Processes(): %v", err)}}
 	}
 
+	seen := make(map[int32]processMeta, len(procs))
 	result := make([]data.Process, 0, len(procs))
 	for _, p := range procs {
-		name, err := p.Name()
+		createTime, err := p.CreateTime()
 		if err != nil {
-			name = ""
+			createTime = 0
 		}
 
-		cmdline, err := p.Cmdline()
-		if err != nil {
-			cmdline = ""
+		comm := commandName(p)
+		meta, ok := pc.entries[p.Pid]
+		if !ok || meta.createTime != createTime || meta.comm != comm {
+			meta = fetchMeta(p, createTime)
+			meta.comm = comm
+			collectSlowResources(p, &meta)
+		} else if p.Pid%resourceRounds == pc.round {
+			collectSlowResources(p, &meta)
 		}
+		seen[p.Pid] = meta
 
-		user, err := p.Username()
+		ppid, err := p.Ppid()
 		if err != nil {
-			user = ""
+			ppid = 0
 		}
 
-		result = append(result, data.Process{
-			PID:     p.Pid,
-			Name:    name,
-			Command: cmdline,
-			User:    user,
-		})
+		proc := data.Process{
+			PID:        p.Pid,
+			PPID:       ppid,
+			Kernel:     isKernelThread(p.Pid, ppid),
+			Name:       meta.name,
+			Command:    meta.cmdline,
+			User:       meta.user,
+			Exe:        meta.exe,
+			Cwd:        meta.cwd,
+			NumThreads: meta.numThreads,
+			NumFDs:     meta.numFDs,
+			FDLimit:    meta.fdLimit,
+		}
+		if createTime > 0 {
+			proc.StartTime = time.UnixMilli(createTime)
+		}
+		collectResources(p, &proc)
+		result = append(result, proc)
+	}
+	pc.entries = seen
+	pc.round = (pc.round + 1) % resourceRounds
+
+	return result, nil
+}
+
+// isKernelThread reports whether a process is a Linux kernel thread:
+// kthreadd, which is always PID 2, or one of the threads it spawns.
+func isKernelThread(pid, ppid int32) bool {
+	return runtime.GOOS == "linux" && (pid == 2 || ppid == 2)
+}
+
+// commandName returns the command name the kernel keeps for a process,
+// which changes when it execs another program. On Linux it is one small
+// read of /proc/<pid>/comm, cheap enough to do every refresh.
+func commandName(p *process.Process) string {
+	if runtime.GOOS != "linux" {
+		name, _ := p.Name()
+		return name
+	}
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", p.Pid))
+	if err != nil {
+		return ""
 	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// fetchMeta reads the metadata of a process the cache has not seen.
+func fetchMeta(p *process.Process, createTime int64) processMeta {
+	meta := processMeta{createTime: createTime}
+
+	name, err := p.Name()
+	if err != nil {
+		name = ""
+	}
+	meta.name = name
+
+	cmdline, err := p.Cmdline()
+	if err != nil {
+		cmdline = ""
+	}
+	meta.cmdline = cmdline
+
+	user, err := p.Username()
+	if err != nil {
+		user = ""
+	}
+	meta.user = user
+
+	meta.exe, _ = p.Exe()
+	if limits, err := p.Rlimit(); err == nil {
+		for _, l := range limits {
+			if l.Resource == process.RLIMIT_NOFILE {
+				meta.fdLimit = l.Soft
+			}
+		}
+	}
+	return meta
+}
+
+// collectResources fills the memory and CPU time of a process, read every
+// refresh since they change all the time and CPU% is worked out from the
+// CPU time. Anything the platform or our privileges do not allow is left
+// zero.
+func collectResources(p *process.Process, proc *data.Process) {
+	if mem, err := p.MemoryInfo(); err == nil {
+		proc.RSS = mem.RSS
+	}
+	if times, err := p.Times(); err == nil {
+		proc.CPUTime = times.User + times.System
+	}
+}
 
-	return result, errs
+// collectSlowResources reads the working directory, thread count and open
+// descriptors of a process into its cache entry. Counting descriptors
+// lists a directory per process, so these are only re-read in the
+// process's round.
+func collectSlowResources(p *process.Process, meta *processMeta) {
+	meta.cwd, _ = p.Cwd()
+	meta.numThreads, _ = p.NumThreads()
+	meta.numFDs, _ = p.NumFDs()
 }
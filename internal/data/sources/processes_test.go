@@ -0,0 +1,99 @@
+package sources
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// spawnSleepers starts n idle child processes for the duration of b, to
+// benchmark collection on hosts with many processes.
+func spawnSleepers(b *testing.B, n int) {
+	b.Helper()
+	cmds := make([]*exec.Cmd, 0, n)
+	b.Cleanup(func() {
+		for _, cmd := range cmds {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}
+	})
+	for i := 0; i < n; i++ {
+		cmd := exec.Command("sleep", "3600")
+		if err := cmd.Start(); err != nil {
+			b.Skipf("starting sleep: %v", err)
+		}
+		cmds = append(cmds, cmd)
+	}
+}
+
+// BenchmarkProcessRefresh compares a refresh that fetches every process's
+// metadata with one served from a warm ProcessCache.
+func BenchmarkProcessRefresh(b *testing.B) {
+	for _, extra := range []int{0, 1000, 4000} {
+		b.Run(fmt.Sprintf("extra=%d", extra), func(b *testing.B) {
+			spawnSleepers(b, extra)
+
+			b.Run("uncached", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					CollectProcesses()
+				}
+			})
+
+			b.Run("cached", func(b *testing.B) {
+				pc := NewProcessCache()
+				pc.Collect()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					pc.Collect()
+				}
+			})
+		})
+	}
+}
+
+// TestProcessCacheReuse checks that a refresh serves a process's metadata
+// from the cache, and fetches it again once its PID belongs to another
+// process or it has exec'd another program.
+func TestProcessCacheReuse(t *testing.T) {
+	pid := int32(os.Getpid())
+	pc := NewProcessCache()
+	self := func() data.Process {
+		t.Helper()
+		procs, errs := pc.Collect()
+		if len(errs) > 0 {
+			t.Fatalf("collect: %v", errs)
+		}
+		for _, p := range procs {
+			if p.PID == pid {
+				return p
+			}
+		}
+		t.Fatalf("PID %d not collected", pid)
+		return data.Process{}
+	}
+	mark := func(edit func(*processMeta)) {
+		meta := pc.entries[pid]
+		meta.cmdline = "cached"
+		edit(&meta)
+		pc.entries[pid] = meta
+	}
+
+	if p := self(); p.Command == "" || p.Command == "cached" {
+		t.Fatalf("first refresh: command %q", p.Command)
+	}
+	mark(func(*processMeta) {})
+	if p := self(); p.Command != "cached" {
+		t.Errorf("command %q, want the cached entry reused", p.Command)
+	}
+	mark(func(m *processMeta) { m.createTime-- })
+	if p := self(); p.Command == "cached" {
+		t.Error("entry reused for a process with another start time")
+	}
+	mark(func(m *processMeta) { m.comm = "exec'd" })
+	if p := self(); p.Command == "cached" {
+		t.Error("entry reused after the command name changed")
+	}
+}
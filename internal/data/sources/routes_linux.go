@@ -0,0 +1,57 @@
+//go:build linux
+
+package sources
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+)
+
+// CollectRoutes reads the main routing table over rtnetlink. Multipath
+// routes are listed under their first next hop.
+func CollectRoutes() ([]data.Route, []data.CollectionError) {
+	nlRoutes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "routes", Error: fmt.Sprintf("RouteList(): %v", err)}}
+	}
+
+	// Look up interface names by index.
+	ifaces, _ := net.Interfaces()
+	ifaceNames := make(map[int]string, len(ifaces))
+	for _, iface := range ifaces {
+		ifaceNames[iface.Index] = iface.Name
+	}
+
+	routes := make([]data.Route, 0, len(nlRoutes))
+	for _, nr := range nlRoutes {
+		gw, index := nr.Gw, nr.LinkIndex
+		if len(nr.MultiPath) > 0 {
+			gw, index = nr.MultiPath[0].Gw, nr.MultiPath[0].LinkIndex
+		}
+
+		r := data.Route{
+			Destination: "default",
+			Interface:   ifaceNames[index],
+			Flags:       strings.Join(nr.ListFlags(), ","),
+			Metric:      nr.Priority,
+		}
+		// Depending on the kernel, a default route has no destination or
+		// a zero-length prefix.
+		if nr.Dst != nil {
+			if ones, _ := nr.Dst.Mask.Size(); ones > 0 {
+				r.Destination = nr.Dst.IP.String()
+				r.Netmask = net.IP(nr.Dst.Mask).String()
+			}
+		}
+		if gw != nil {
+			r.Gateway = gw.String()
+		}
+		routes = append(routes, r)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || netbsd || openbsd || linux)
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// CollectRoutes is not implemented on this platform.
+func CollectRoutes() ([]data.Route, []data.CollectionError) {
+	return nil, []data.CollectionError{{Source: "routes", Error: "routing table not supported on this platform"}}
+}
@@ -0,0 +1,48 @@
+package sources
+
+import (
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+// ruleRateSampler turns the cumulative packet and byte counters of each
+// firewall rule into rates over the interval since the previous refresh.
+type ruleRateSampler struct {
+	prev     map[ruleKey]ruleCounters
+	prevTime time.Time
+}
+
+// ruleKey identifies a rule across refreshes. The rule text is part of
+// it, so a reloaded ruleset that puts another rule at the same number does
+// not inherit its counters.
+type ruleKey struct {
+	namespace, ref, text string
+}
+
+type ruleCounters struct {
+	packets, bytes uint64
+}
+
+func newRuleRateSampler() *ruleRateSampler {
+	return &ruleRateSampler{prev: make(map[ruleKey]ruleCounters)}
+}
+
+// apply sets PacketRate and ByteRate on each rule seen in the previous
+// sample and replaces the sample. Counters that went backwards, as when
+// they are zeroed with pfctl -z, give no rate.
+func (s *ruleRateSampler) apply(rules []data.FirewallRule, now time.Time) {
+	elapsed := now.Sub(s.prevTime).Seconds()
+	next := make(map[ruleKey]ruleCounters, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		k := ruleKey{r.Namespace, r.Ref(), r.RawRule}
+		if prev, ok := s.prev[k]; ok && elapsed > 0 && r.Packets >= prev.packets && r.Bytes >= prev.bytes {
+			r.PacketRate = float64(r.Packets-prev.packets) / elapsed
+			r.ByteRate = float64(r.Bytes-prev.bytes) / elapsed
+		}
+		next[k] = ruleCounters{packets: r.Packets, bytes: r.Bytes}
+	}
+	s.prev = next
+	s.prevTime = now
+}
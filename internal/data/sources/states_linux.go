@@ -0,0 +1,184 @@
+//go:build linux
+
+package sources
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// tcpConntrackStates names the TCP_CONNTRACK_* states by value.
+var tcpConntrackStates = []string{
+	"NONE", "SYN_SENT", "SYN_RECV", "ESTABLISHED", "FIN_WAIT",
+	"CLOSE_WAIT", "LAST_ACK", "TIME_WAIT", "CLOSE", "SYN_SENT2",
+}
+
+// CollectStates reads the conntrack table over ctnetlink, falling back to
+// /proc/net/nf_conntrack. Both need root access and the nf_conntrack
+// module; packet and byte counts need net.netfilter.nf_conntrack_acct.
+func CollectStates(isRoot bool) ([]data.FirewallState, []data.CollectionError) {
+	if !isRoot {
+		return nil, []data.CollectionError{{Source: "states", Error: "conntrack requires root access"}}
+	}
+	var states []data.FirewallState
+	var nlErr error
+	for _, family := range []netlink.InetFamily{unix.AF_INET, unix.AF_INET6} {
+		flows, err := netlink.ConntrackTableList(netlink.ConntrackTable, family)
+		if err != nil {
+			nlErr = err
+			break
+		}
+		for _, f := range flows {
+			states = append(states, conntrackState(f))
+		}
+	}
+	if nlErr == nil {
+		return states, nil
+	}
+
+	states, err := readProcConntrack("/proc/net/nf_conntrack")
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "states", Error: fmt.Sprintf("ConntrackTableList(): %v; %v", nlErr, err)}}
+	}
+	return states, nil
+}
+
+// conntrackState converts a ctnetlink flow.
+func conntrackState(f *netlink.ConntrackFlow) data.FirewallState {
+	st := data.FirewallState{
+		Proto:        ipProtoName(f.Forward.Protocol),
+		Family:       "inet",
+		Orig:         flowTuple(f.Forward),
+		Reply:        flowTuple(f.Reverse),
+		Timeout:      time.Duration(f.TimeOut) * time.Second,
+		OrigPackets:  f.Forward.Packets,
+		OrigBytes:    f.Forward.Bytes,
+		ReplyPackets: f.Reverse.Packets,
+		ReplyBytes:   f.Reverse.Bytes,
+	}
+	if f.FamilyType == unix.AF_INET6 {
+		st.Family = "inet6"
+	}
+	if f.TimeStart != 0 {
+		st.Age = time.Since(time.Unix(0, int64(f.TimeStart))).Truncate(time.Second)
+	}
+	if tcp, ok := f.ProtoInfo.(*netlink.ProtoInfoTCP); ok && int(tcp.State) < len(tcpConntrackStates) {
+		st.State = tcpConntrackStates[tcp.State]
+	}
+	return st
+}
+
+func flowTuple(t netlink.IPTuple) data.FlowTuple {
+	return data.FlowTuple{
+		Src:     t.SrcIP.String(),
+		SrcPort: uint32(t.SrcPort),
+		Dst:     t.DstIP.String(),
+		DstPort: uint32(t.DstPort),
+	}
+}
+
+// ipProtoName names an IP protocol number.
+func ipProtoName(proto uint8) string {
+	switch proto {
+	case unix.IPPROTO_TCP:
+		return "tcp"
+	case unix.IPPROTO_UDP:
+		return "udp"
+	case unix.IPPROTO_ICMP:
+		return "icmp"
+	case unix.IPPROTO_ICMPV6:
+		return "icmp6"
+	case unix.IPPROTO_SCTP:
+		return "sctp"
+	case unix.IPPROTO_DCCP:
+		return "dccp"
+	case unix.IPPROTO_GRE:
+		return "gre"
+	}
+	return strconv.Itoa(int(proto))
+}
+
+func readProcConntrack(path string) ([]data.FirewallState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var states []data.FirewallState
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if st, ok := parseConntrackLine(sc.Text()); ok {
+			states = append(states, st)
+		}
+	}
+	return states, sc.Err()
+}
+
+// parseConntrackLine parses a /proc/net/nf_conntrack line like
+//
+//	ipv4 2 tcp 6 431999 ESTABLISHED src=10.0.0.2 dst=1.1.1.1 sport=5000 dport=443 packets=3 bytes=180 src=1.1.1.1 dst=10.0.0.2 sport=443 dport=5000 packets=2 bytes=120 [ASSURED] mark=0 use=1
+//
+// The first src/dst/sport/dport group is the original tuple and the
+// second the reply tuple. Flags in brackets are appended to the state.
+func parseConntrackLine(line string) (data.FirewallState, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 {
+		return data.FirewallState{}, false
+	}
+	st := data.FirewallState{Family: "inet", Proto: fields[2]}
+	if fields[0] == "ipv6" {
+		st.Family = "inet6"
+	}
+	if secs, err := strconv.Atoi(fields[4]); err == nil {
+		st.Timeout = time.Duration(secs) * time.Second
+	}
+
+	var flags []string
+	tuple, counters := &st.Orig, [2]*uint64{&st.OrigPackets, &st.OrigBytes}
+	seen := make(map[string]bool)
+	for _, f := range fields[5:] {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			if strings.HasPrefix(f, "[") {
+				flags = append(flags, strings.Trim(f, "[]"))
+			} else if st.State == "" {
+				st.State = f
+			}
+			continue
+		}
+		if seen[k] && (k == "src" || k == "dst" || k == "sport" || k == "dport" || k == "packets" || k == "bytes") {
+			// The key repeats: the reply tuple starts here.
+			tuple, counters = &st.Reply, [2]*uint64{&st.ReplyPackets, &st.ReplyBytes}
+			seen = make(map[string]bool)
+		}
+		seen[k] = true
+		n, _ := strconv.ParseUint(v, 10, 64)
+		switch k {
+		case "src":
+			tuple.Src = v
+		case "dst":
+			tuple.Dst = v
+		case "sport":
+			tuple.SrcPort = uint32(n)
+		case "dport":
+			tuple.DstPort = uint32(n)
+		case "packets":
+			*counters[0] = n
+		case "bytes":
+			*counters[1] = n
+		}
+	}
+	if len(flags) > 0 {
+		st.State = strings.TrimSpace(st.State + " " + strings.Join(flags, " "))
+	}
+	return st, true
+}
@@ -0,0 +1,146 @@
+//go:build !linux
+
+package sources
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+var (
+	// Matches pf state lines like: all tcp 10.0.0.2:5000 (192.168.1.5:5000) -> 1.1.1.1:443       ESTABLISHED:ESTABLISHED
+	stateLineRe = regexp.MustCompile(`^(\S+)\s+(\S+)\s+(\S+)(?:\s+\((\S+)\))?\s+(->|<-)\s+(\S+)(?:\s+\((\S+)\))?\s+(\S+)\s*$`)
+	// Matches verbose state details like: age 00:01:23, expires in 00:00:10, 12:10 pkts, 1234:5678 bytes
+	stateAgeRe   = regexp.MustCompile(`age (\S+), expires in (\S+),`)
+	stateCountRe = regexp.MustCompile(`(\d+):(\d+) pkts, (\d+):(\d+) bytes`)
+)
+
+// CollectStates parses pfctl -ss -v output to collect the pf state table.
+// Requires root access.
+func CollectStates(isRoot bool) ([]data.FirewallState, []data.CollectionError) {
+	if !isRoot {
+		return nil, []data.CollectionError{{Source: "states", Error: "pfctl requires root access"}}
+	}
+	out, err := pfctl("", "-ss", "-v")
+	if err != nil {
+		return nil, []data.CollectionError{{Source: "states", Error: err.Error()}}
+	}
+	return parsePfStates(out), nil
+}
+
+// parsePfStates parses pfctl -ss -v output. Each state is a line with the
+// interface, protocol and endpoints, followed by indented detail lines.
+// The original tuple is the connection as its initiator sent it and the
+// reply tuple as the responder answers it, so that translation shows the
+// same as for conntrack: for outbound states the host's stack initiates,
+// and for inbound states a peer on the wire does.
+func parsePfStates(output string) []data.FirewallState {
+	var states []data.FirewallState
+	var cur *data.FirewallState
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if cur != nil {
+				parseStateDetail(line, cur)
+			}
+			continue
+		}
+		m := stateLineRe.FindStringSubmatch(line)
+		if m == nil {
+			cur = nil
+			continue
+		}
+		st := data.FirewallState{Proto: m[2], State: m[8]}
+		if m[1] != "all" {
+			st.Iface = m[1]
+		}
+
+		// Both ends as printed, and in parentheses where translation
+		// changed them.
+		main := [2]string{m[3], m[6]}
+		alt := main
+		if m[4] != "" {
+			alt[0] = m[4]
+		}
+		if m[7] != "" {
+			alt[1] = m[7]
+		}
+		// "a -> b" is an outbound state from a to b, printed with the
+		// stack's addresses first; "a <- b" an inbound one from b to a,
+		// printed with the wire's first. Either way the addresses printed
+		// first are the initiator's.
+		src, dst := 0, 1
+		st.Direction = "out"
+		if m[5] == "<-" {
+			src, dst = 1, 0
+			st.Direction = "in"
+		}
+		st.Orig.Src, st.Orig.SrcPort = splitPfEndpoint(main[src])
+		st.Orig.Dst, st.Orig.DstPort = splitPfEndpoint(main[dst])
+		st.Reply.Src, st.Reply.SrcPort = splitPfEndpoint(alt[dst])
+		st.Reply.Dst, st.Reply.DstPort = splitPfEndpoint(alt[src])
+		st.Family = "inet"
+		if strings.Contains(st.Orig.Src, ":") {
+			st.Family = "inet6"
+		}
+
+		states = append(states, st)
+		cur = &states[len(states)-1]
+	}
+	return states
+}
+
+// parseStateDetail reads the age, expiry and counters of a state from one
+// of its detail lines.
+func parseStateDetail(line string, st *data.FirewallState) {
+	if m := stateAgeRe.FindStringSubmatch(line); m != nil {
+		st.Age = parseClock(m[1])
+		st.Timeout = parseClock(m[2])
+	}
+	if m := stateCountRe.FindStringSubmatch(line); m != nil {
+		st.OrigPackets, _ = strconv.ParseUint(m[1], 10, 64)
+		st.ReplyPackets, _ = strconv.ParseUint(m[2], 10, 64)
+		st.OrigBytes, _ = strconv.ParseUint(m[3], 10, 64)
+		st.ReplyBytes, _ = strconv.ParseUint(m[4], 10, 64)
+	}
+}
+
+// splitPfEndpoint splits an endpoint as pfctl prints it: "10.0.0.2:5000"
+// for IPv4, "fe80::1[5000]" for IPv6, and no port for ICMP, where pfctl
+// prints the ICMP id in its place.
+func splitPfEndpoint(s string) (string, uint32) {
+	if i := strings.IndexByte(s, '['); i >= 0 && strings.HasSuffix(s, "]") {
+		port, _ := strconv.ParseUint(s[i+1:len(s)-1], 10, 16)
+		return s[:i], uint32(port)
+	}
+	if net.ParseIP(s) != nil {
+		return s, 0
+	}
+	if host, port, err := net.SplitHostPort(s); err == nil {
+		n, _ := strconv.ParseUint(port, 10, 16)
+		return host, uint32(n)
+	}
+	return s, 0
+}
+
+// parseClock parses a pfctl duration like "00:01:23", whose hours may
+// exceed 24.
+func parseClock(s string) time.Duration {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	var d time.Duration
+	for _, p := range parts {
+		n, _ := strconv.Atoi(p)
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second
+}
@@ -1,68 +1,267 @@
 package sources
 
 import (
+	"math"
 	"time"
 
 	"github.com/jerryluo/nettui/internal/data"
 )
 
-// ThroughputCalculator computes per-interface byte rates from IO counter deltas.
+// ThroughputCalculator computes per-interface byte and packet rates from IO
+// counter deltas, and accumulates per-interface totals for the session.
+//
+// Its state is keyed by namespace inode and interface name rather than by
+// Interface.Key, so switching which namespaces are viewed keeps the totals
+// and history of interfaces that are still there. An interface out of view
+// picks up where it left off when it comes back, its first rate averaged
+// over the time it was away.
 type ThroughputCalculator struct {
-	prevCounters map[string]ifaceCounters
-	prevTime     time.Time
+	prevCounters map[ifaceID]ifaceCounters
+
+	// smoothing is the EWMA time constant for byte and packet rates.
+	// Zero disables smoothing and reports the raw rate of the last interval.
+	smoothing time.Duration
+	smoothed  map[ifaceID]smoothedRates
+
+	totals       map[ifaceID]sessionTotals
+	sessionStart time.Time
+
+	history map[ifaceID]*rateHistory
+
+	// wrap32 treats a counter going backwards from the upper half of the
+	// 32-bit range as a wrap rather than a reset.
+	wrap32 bool
+}
+
+// ifaceID identifies an interface by the inode of its network namespace
+// and its name, which do not depend on the namespace view.
+type ifaceID struct {
+	netns uint64
+	name  string
 }
 
 type ifaceCounters struct {
-	bytesSent uint64
-	bytesRecv uint64
+	at          time.Time // when the counters were read
+	bytesSent   uint64
+	bytesRecv   uint64
+	packetsSent uint64
+	packetsRecv uint64
+	errIn       uint64
+	errOut      uint64
+	dropIn      uint64
+	dropOut     uint64
+	fifoIn      uint64
+	fifoOut     uint64
+}
+
+type smoothedRates struct {
+	tx, rx       float64
+	txPkt, rxPkt float64
+}
+
+type sessionTotals struct {
+	txBytes, rxBytes uint64
+	txPkts, rxPkts   uint64
+}
+
+func countersOf(iface data.Interface) ifaceCounters {
+	return ifaceCounters{
+		bytesSent:   iface.BytesSent,
+		bytesRecv:   iface.BytesRecv,
+		packetsSent: iface.PacketSent,
+		packetsRecv: iface.PacketRecv,
+		errIn:       iface.ErrIn,
+		errOut:      iface.ErrOut,
+		dropIn:      iface.DropIn,
+		dropOut:     iface.DropOut,
+		fifoIn:      iface.FifoIn,
+		fifoOut:     iface.FifoOut,
+	}
 }
 
 // NewThroughputCalculator creates a new calculator.
 func NewThroughputCalculator() *ThroughputCalculator {
 	return &ThroughputCalculator{
-		prevCounters: make(map[string]ifaceCounters),
+		prevCounters: make(map[ifaceID]ifaceCounters),
+		smoothed:     make(map[ifaceID]smoothedRates),
+		totals:       make(map[ifaceID]sessionTotals),
+		sessionStart: time.Now(),
+		history:      make(map[ifaceID]*rateHistory),
+		wrap32:       counters32,
+	}
+}
+
+// SetSmoothing sets the EWMA time constant applied to byte and packet rates.
+// A zero or negative window disables smoothing.
+func (tc *ThroughputCalculator) SetSmoothing(window time.Duration) {
+	if window < 0 {
+		window = 0
 	}
+	tc.smoothing = window
+	tc.smoothed = make(map[ifaceID]smoothedRates)
+}
+
+// ResetTotals zeroes the session totals and restarts the session clock.
+func (tc *ThroughputCalculator) ResetTotals() {
+	tc.totals = make(map[ifaceID]sessionTotals)
+	tc.sessionStart = time.Now()
 }
 
 // Calculate computes throughput for each interface by comparing current counters
-// against the previous snapshot. On the first call, all rates will be zero.
-func (tc *ThroughputCalculator) Calculate(interfaces []data.Interface) map[string]data.Throughput {
+// against the previous snapshot of the same interface, and returns it keyed
+// by Interface.Key(). namespaces are those listed this refresh, which map
+// interface namespace labels to inodes. An interface's first sample has
+// zero rates. State is dropped for interfaces missing from a namespace
+// that was collected, and for namespaces that no longer exist.
+func (tc *ThroughputCalculator) Calculate(interfaces []data.Interface, namespaces []data.Namespace) map[string]data.Throughput {
 	now := time.Now()
 	result := make(map[string]data.Throughput, len(interfaces))
 
-	elapsed := now.Sub(tc.prevTime).Seconds()
-	if elapsed <= 0 || tc.prevTime.IsZero() {
-		// First call or invalid interval: store counters, return zero rates.
-		for _, iface := range interfaces {
-			tc.prevCounters[iface.Name] = ifaceCounters{
-				bytesSent: iface.BytesSent,
-				bytesRecv: iface.BytesRecv,
-			}
-			result[iface.Name] = data.Throughput{Interface: iface.Name}
+	nsIDs := make(map[string]uint64, len(namespaces)+1)
+	for _, ns := range namespaces {
+		nsIDs[ns.Name] = ns.ID
+		if ns.Self {
+			nsIDs[""] = ns.ID
 		}
-		tc.prevTime = now
-		return result
 	}
 
+	seen := make(map[ifaceID]bool, len(interfaces))
 	for _, iface := range interfaces {
+		id := ifaceID{netns: nsIDs[iface.Namespace], name: iface.Name}
+		seen[id] = true
 		tp := data.Throughput{Interface: iface.Name}
+		cur := countersOf(iface)
+		cur.at = now
 
-		if prev, ok := tc.prevCounters[iface.Name]; ok {
-			if iface.BytesSent >= prev.bytesSent {
-				tp.TxRate = float64(iface.BytesSent-prev.bytesSent) / elapsed
+		prev, ok := tc.prevCounters[id]
+		if elapsed := now.Sub(prev.at).Seconds(); ok && elapsed > 0 {
+			txDelta := tc.delta(cur.bytesSent, prev.bytesSent)
+			rxDelta := tc.delta(cur.bytesRecv, prev.bytesRecv)
+			txPktDelta := tc.delta(cur.packetsSent, prev.packetsSent)
+			rxPktDelta := tc.delta(cur.packetsRecv, prev.packetsRecv)
+
+			raw := smoothedRates{
+				tx:    float64(txDelta) / elapsed,
+				rx:    float64(rxDelta) / elapsed,
+				txPkt: float64(txPktDelta) / elapsed,
+				rxPkt: float64(rxPktDelta) / elapsed,
 			}
-			if iface.BytesRecv >= prev.bytesRecv {
-				tp.RxRate = float64(iface.BytesRecv-prev.bytesRecv) / elapsed
+			rates := tc.smooth(id, raw, elapsed)
+			tp.TxRate = rates.tx
+			tp.RxRate = rates.rx
+			tp.TxPktRate = rates.txPkt
+			tp.RxPktRate = rates.rxPkt
+
+			tp.ErrInRate = float64(tc.delta(cur.errIn, prev.errIn)) / elapsed
+			tp.ErrOutRate = float64(tc.delta(cur.errOut, prev.errOut)) / elapsed
+			tp.DropInRate = float64(tc.delta(cur.dropIn, prev.dropIn)) / elapsed
+			tp.DropOutRate = float64(tc.delta(cur.dropOut, prev.dropOut)) / elapsed
+			tp.FifoInRate = float64(tc.delta(cur.fifoIn, prev.fifoIn)) / elapsed
+			tp.FifoOutRate = float64(tc.delta(cur.fifoOut, prev.fifoOut)) / elapsed
+
+			h, ok := tc.history[id]
+			if !ok {
+				h = &rateHistory{}
+				tc.history[id] = h
 			}
+			h.add(data.RateSample{Time: now, TxRate: tp.TxRate, RxRate: tp.RxRate})
+
+			t := tc.totals[id]
+			t.txBytes += txDelta
+			t.rxBytes += rxDelta
+			t.txPkts += txPktDelta
+			t.rxPkts += rxPktDelta
+			tc.totals[id] = t
 		}
 
-		tc.prevCounters[iface.Name] = ifaceCounters{
-			bytesSent: iface.BytesSent,
-			bytesRecv: iface.BytesRecv,
+		t := tc.totals[id]
+		tp.SessionTxBytes = t.txBytes
+		tp.SessionRxBytes = t.rxBytes
+		tp.SessionTxPkts = t.txPkts
+		tp.SessionRxPkts = t.rxPkts
+		tp.SessionStart = tc.sessionStart
+		if h, ok := tc.history[id]; ok {
+			tp.History = h.snapshot()
 		}
-		result[iface.Name] = tp
+
+		tc.prevCounters[id] = cur
+		result[iface.Key()] = tp
 	}
 
-	tc.prevTime = now
+	tc.prune(seen, namespaces)
 	return result
 }
+
+// smooth folds raw into the interface's moving average. The weight given to
+// the new sample depends on the elapsed interval, so irregular refreshes
+// decay the average at the same rate per second.
+func (tc *ThroughputCalculator) smooth(id ifaceID, raw smoothedRates, elapsed float64) smoothedRates {
+	if tc.smoothing <= 0 {
+		return raw
+	}
+	prev, ok := tc.smoothed[id]
+	if !ok {
+		tc.smoothed[id] = raw
+		return raw
+	}
+	alpha := 1 - math.Exp(-elapsed/tc.smoothing.Seconds())
+	next := smoothedRates{
+		tx:    prev.tx + alpha*(raw.tx-prev.tx),
+		rx:    prev.rx + alpha*(raw.rx-prev.rx),
+		txPkt: prev.txPkt + alpha*(raw.txPkt-prev.txPkt),
+		rxPkt: prev.rxPkt + alpha*(raw.rxPkt-prev.rxPkt),
+	}
+	tc.smoothed[id] = next
+	return next
+}
+
+// prune drops the state of interfaces that went away, so short-lived
+// interfaces such as container veths do not accumulate: those missing from
+// seen in a namespace that was collected, and those of namespaces no longer
+// listed. Namespaces out of view keep their state.
+func (tc *ThroughputCalculator) prune(seen map[ifaceID]bool, namespaces []data.Namespace) {
+	collected := make(map[uint64]bool)
+	for id := range seen {
+		collected[id.netns] = true
+	}
+	live := make(map[uint64]bool, len(namespaces))
+	for _, ns := range namespaces {
+		live[ns.ID] = true
+	}
+	for id := range tc.prevCounters {
+		gone := collected[id.netns] && !seen[id]
+		if len(namespaces) > 0 && !live[id.netns] {
+			gone = true
+		}
+		if gone {
+			delete(tc.prevCounters, id)
+			delete(tc.smoothed, id)
+			delete(tc.totals, id)
+			delete(tc.history, id)
+		}
+	}
+}
+
+// delta returns how far a counter advanced between two readings, with the
+// platform's counter width.
+func (tc *ThroughputCalculator) delta(cur, prev uint64) uint64 {
+	return counterDelta(cur, prev, tc.wrap32)
+}
+
+// counterDelta returns how far a counter advanced between two readings.
+//
+// A counter that went backwards either wrapped or was reset. Where counters
+// are 32 bits wide (wrap32), as Darwin's if_data counters are, a previous
+// reading in the upper half of the 32-bit range is treated as a wrap.
+// Anything else is treated as a reset (interface recreated, driver
+// reloaded), in which case the counter has advanced by its current value
+// since it restarted from zero.
+func counterDelta(cur, prev uint64, wrap32 bool) uint64 {
+	if cur >= prev {
+		return cur - prev
+	}
+	if wrap32 && prev <= math.MaxUint32 && prev > math.MaxUint32/2 {
+		return (math.MaxUint32 - prev) + cur + 1
+	}
+	return cur
+}
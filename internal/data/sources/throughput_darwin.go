@@ -0,0 +1,7 @@
+//go:build darwin
+
+package sources
+
+// counters32 is set where interface counters are 32 bits wide and wrap:
+// Darwin's if_data counters.
+const counters32 = true
@@ -0,0 +1,7 @@
+//go:build !darwin
+
+package sources
+
+// counters32 is set where interface counters are 32 bits wide and wrap.
+// Linux reports 64-bit counters, so one going backwards was reset.
+const counters32 = false
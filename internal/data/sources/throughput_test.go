@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jerryluo/nettui/internal/data"
+)
+
+func TestCounterDelta(t *testing.T) {
+	const gib = 1 << 30
+	tests := []struct {
+		name      string
+		cur, prev uint64
+		wrap32    bool
+		want      uint64
+	}{
+		{"advance", 1500, 1000, false, 500},
+		{"unchanged", 1000, 1000, true, 0},
+		{"32-bit wrap", 5, math.MaxUint32 - 10, true, 16},
+		{"32-bit reset from low reading", 100, 1000, true, 100},
+		{"64-bit reset from 3 GiB", 100, 3 * gib, false, 100},
+		{"64-bit reset from near 4 GiB", 100, math.MaxUint32 - 10, false, 100},
+		{"64-bit reset above 32 bits", 100, 8 * gib, true, 100},
+	}
+	for _, tt := range tests {
+		if got := counterDelta(tt.cur, tt.prev, tt.wrap32); got != tt.want {
+			t.Errorf("%s: counterDelta(%d, %d, %v) = %d, want %d", tt.name, tt.cur, tt.prev, tt.wrap32, got, tt.want)
+		}
+	}
+}
+
+func TestThroughputCalculate(t *testing.T) {
+	tc := NewThroughputCalculator()
+	tc.wrap32 = false
+	eth := data.Interface{Name: "eth0", BytesSent: 3 << 30, BytesRecv: 1000, PacketSent: 10, PacketRecv: 20}
+	veth := data.Interface{Name: "veth1", BytesSent: 500}
+
+	// The first sample has nothing to compare against.
+	first := tc.Calculate([]data.Interface{eth, veth}, nil)
+	if tp := first["eth0"]; tp.TxRate != 0 || tp.RxRate != 0 || tp.SessionTxBytes != 0 {
+		t.Errorf("first sample: %+v, want zero rates and totals", tp)
+	}
+
+	// eth0's TX counter was reset; its RX counter advanced.
+	time.Sleep(10 * time.Millisecond)
+	eth.BytesSent, eth.BytesRecv = 100, 1500
+	second := tc.Calculate([]data.Interface{eth, veth}, nil)
+	tp := second["eth0"]
+	if tp.SessionTxBytes != 100 || tp.SessionRxBytes != 500 {
+		t.Errorf("after reset: session tx %d rx %d, want 100 and 500", tp.SessionTxBytes, tp.SessionRxBytes)
+	}
+	if tp.TxRate <= 0 || tp.TxRate > 100/0.01 {
+		t.Errorf("after reset: tx rate %.0f B/s, want at most 10000", tp.TxRate)
+	}
+
+	// veth1 disappears, and its state with it.
+	tc.Calculate([]data.Interface{eth}, nil)
+	for name, m := range map[string]int{
+		"prevCounters": len(tc.prevCounters),
+		"totals":       len(tc.totals),
+		"history":      len(tc.history),
+	} {
+		if m != 1 {
+			t.Errorf("%s has %d entries after veth1 went away, want 1", name, m)
+		}
+	}
+	if _, ok := tc.prevCounters[ifaceID{name: "veth1"}]; ok {
+		t.Error("veth1 counters kept after it went away")
+	}
+}
+
+func TestThroughputNamespaceSwitch(t *testing.T) {
+	tc := NewThroughputCalculator()
+	tc.wrap32 = false
+	namespaces := []data.Namespace{{ID: 1, Name: "self", Self: true}, {ID: 2, Name: "red"}}
+	eth := data.Interface{Name: "eth0", BytesRecv: 1000}
+	red := data.Interface{Name: "eth0", Namespace: "red", BytesRecv: 50}
+
+	// Start out viewing nettui's own namespace, then every namespace.
+	tc.Calculate([]data.Interface{eth}, namespaces)
+	time.Sleep(10 * time.Millisecond)
+	eth.BytesRecv = 1500
+	selfEth := eth
+	selfEth.Namespace = "self"
+	tc.Calculate([]data.Interface{selfEth, red}, namespaces)
+
+	// Viewing red alone keeps the totals of the self namespace's eth0.
+	time.Sleep(10 * time.Millisecond)
+	red.BytesRecv = 80
+	got := tc.Calculate([]data.Interface{red}, namespaces)
+	if tp := got["red/eth0"]; tp.SessionRxBytes != 30 {
+		t.Errorf("red/eth0 session rx %d, want 30", tp.SessionRxBytes)
+	}
+
+	// Back to nettui's own namespace, which carries on from its last sample.
+	time.Sleep(10 * time.Millisecond)
+	eth.BytesRecv = 1700
+	got = tc.Calculate([]data.Interface{eth}, namespaces)
+	if tp := got["eth0"]; tp.SessionRxBytes != 700 {
+		t.Errorf("eth0 session rx %d after switching back, want 700", tp.SessionRxBytes)
+	}
+
+	// red goes away.
+	tc.Calculate([]data.Interface{eth}, namespaces[:1])
+	if _, ok := tc.totals[ifaceID{netns: 2, name: "eth0"}]; ok {
+		t.Error("red/eth0 totals kept after its namespace went away")
+	}
+}
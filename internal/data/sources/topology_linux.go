@@ -0,0 +1,79 @@
+//go:build linux
+
+package sources
+
+import (
+	"fmt"
+
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/vishvananda/netlink"
+)
+
+// collectTopology fills master, parent, VLAN and veth peer relationships
+// from an rtnetlink link dump, along with the operational state and queue
+// length of interfaces sysfs did not cover. Peers in other namespaces are
+// labelled with the names of namespaces.
+func collectTopology(ifaces []data.Interface, namespaces []data.Namespace) []data.CollectionError {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return []data.CollectionError{{Source: "topology", Error: fmt.Sprintf("LinkList(): %v", err)}}
+	}
+
+	nameByIndex := make(map[int]string, len(links))
+	for _, l := range links {
+		nameByIndex[l.Attrs().Index] = l.Attrs().Name
+	}
+	byName := make(map[string]*data.Interface, len(ifaces))
+	for i := range ifaces {
+		byName[ifaces[i].Name] = &ifaces[i]
+	}
+
+	var nsLabels map[int]string
+	for _, l := range links {
+		attrs := l.Attrs()
+		di, ok := byName[attrs.Name]
+		if !ok {
+			continue
+		}
+		if di.OperState == "" {
+			di.OperState = attrs.OperState.String()
+			di.TxQueueLen = attrs.TxQLen
+		}
+		if attrs.MasterIndex > 0 {
+			di.Master = nameByIndex[attrs.MasterIndex]
+		}
+
+		switch link := l.(type) {
+		case *netlink.Vlan:
+			di.VLANID = link.VlanId
+			di.Parent = nameByIndex[attrs.ParentIndex]
+		case *netlink.Macvlan, *netlink.Macvtap, *netlink.IPVlan:
+			di.Parent = nameByIndex[attrs.ParentIndex]
+		case *netlink.Veth:
+			di.PeerIndex = attrs.ParentIndex
+			if attrs.NetNsID < 0 {
+				di.Peer = nameByIndex[attrs.ParentIndex]
+				break
+			}
+			// The peer lives in another namespace, identified by the
+			// namespace ID this namespace assigned to it.
+			if nsLabels == nil {
+				nsLabels = namespaceLabels(namespaces)
+			}
+			di.PeerNetNS = nsLabels[attrs.NetNsID]
+			if di.PeerNetNS == "" {
+				di.PeerNetNS = fmt.Sprintf("nsid %d", attrs.NetNsID)
+			}
+		case *netlink.Tuntap:
+			di.Kind = "tun"
+			if link.Mode == netlink.TUNTAP_MODE_TAP {
+				di.Kind = "tap"
+			}
+		}
+		if di.Kind == "" || di.Kind == "virtual" {
+			di.Kind = l.Type()
+		}
+	}
+
+	return nil
+}
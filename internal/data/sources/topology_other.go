@@ -0,0 +1,12 @@
+//go:build !linux
+
+package sources
+
+import "github.com/jerryluo/nettui/internal/data"
+
+// collectTopology is a no-op outside Linux. On Darwin the bridge, bond,
+// VLAN and feth relationships come from the same ifconfig pass as the link
+// details.
+func collectTopology(ifaces []data.Interface, namespaces []data.Namespace) []data.CollectionError {
+	return nil
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Packets returns the packets seen in both directions.
+func (s FirewallState) Packets() uint64 {
+	return s.OrigPackets + s.ReplyPackets
+}
+
+// Bytes returns the bytes seen in both directions.
+func (s FirewallState) Bytes() uint64 {
+	return s.OrigBytes + s.ReplyBytes
+}
+
+// SNAT reports whether the source of the connection is translated: replies
+// go back to another address or port than the initiator sent from.
+func (s FirewallState) SNAT() bool {
+	return !sameEndpoint(s.Reply.Dst, s.Reply.DstPort, s.Orig.Src, s.Orig.SrcPort)
+}
+
+// DNAT reports whether the destination of the connection is translated:
+// replies come from another address or port than the initiator sent to.
+func (s FirewallState) DNAT() bool {
+	return !sameEndpoint(s.Reply.Src, s.Reply.SrcPort, s.Orig.Dst, s.Orig.DstPort)
+}
+
+// Translation describes the address translation applied to the
+// connection, like "SNAT 10.0.0.2:5000 → 203.0.113.1:61000", or "" if
+// there is none.
+func (s FirewallState) Translation() string {
+	var parts []string
+	if s.SNAT() {
+		parts = append(parts, fmt.Sprintf("SNAT %s → %s",
+			tupleEndpoint(s.Orig.Src, s.Orig.SrcPort), tupleEndpoint(s.Reply.Dst, s.Reply.DstPort)))
+	}
+	if s.DNAT() {
+		parts = append(parts, fmt.Sprintf("DNAT %s → %s",
+			tupleEndpoint(s.Orig.Dst, s.Orig.DstPort), tupleEndpoint(s.Reply.Src, s.Reply.SrcPort)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String renders the tuple as "src:port → dst:port".
+func (t FlowTuple) String() string {
+	return tupleEndpoint(t.Src, t.SrcPort) + " → " + tupleEndpoint(t.Dst, t.DstPort)
+}
+
+func tupleEndpoint(addr string, port uint32) string {
+	if port == 0 {
+		return addr
+	}
+	return net.JoinHostPort(addr, fmt.Sprintf("%d", port))
+}
+
+func sameEndpoint(a string, aPort uint32, b string, bPort uint32) bool {
+	return aPort == bPort && sameAddr(a, b)
+}
+
+// sameAddr compares addresses by value, so an IPv4-mapped IPv6 address
+// equals its IPv4 form.
+func sameAddr(a, b string) bool {
+	ipA, ipB := net.ParseIP(stripZone(a)), net.ParseIP(stripZone(b))
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
+
+// MatchStateSockets sets the local socket of each state entry. A socket
+// that initiated the connection is the source of the original tuple, and
+// one that accepted it the source of the reply tuple; failing both, a
+// socket bound to the local end with no peer, such as an unconnected UDP
+// socket, is taken. Wildcard binds only count for addresses of ifaces, so
+// forwarded traffic is not taken for local.
+func MatchStateSockets(states []FirewallState, sockets []Socket, ifaces []Interface) {
+	type key struct {
+		ns, proto string
+		port      uint32
+	}
+	byPort := make(map[key][]int)
+	for i, sock := range sockets {
+		k := key{sock.Namespace, strings.TrimSuffix(sock.Proto, "6"), sock.LocalPort}
+		byPort[k] = append(byPort[k], i)
+	}
+	local := make(map[string]bool)
+	for _, iface := range ifaces {
+		for _, a := range iface.Addresses {
+			if ip := net.ParseIP(stripZone(a.IP)); ip != nil {
+				local[iface.Namespace+"/"+ip.String()] = true
+			}
+		}
+	}
+	for i := range states {
+		st := &states[i]
+		st.Socket = nil
+		var bound *Socket
+	search:
+		for _, end := range []FlowTuple{st.Orig, st.Reply} {
+			for _, j := range byPort[key{st.Namespace, st.Proto, end.SrcPort}] {
+				sock := &sockets[j]
+				if !localMatch(sock.LocalAddr, end.Src, local[st.Namespace+"/"+net.ParseIP(end.Src).String()]) {
+					continue
+				}
+				if sock.RemotePort == 0 && endpointIP(sock.RemoteAddr) == nil {
+					if bound == nil {
+						bound = sock
+					}
+					continue
+				}
+				if sock.RemotePort == end.DstPort && sameAddr(sock.RemoteAddr, end.Dst) {
+					st.Socket = sock
+					break search
+				}
+			}
+		}
+		if st.Socket == nil {
+			st.Socket = bound
+		}
+	}
+}
+
+// localMatch reports whether a socket bound to local receives traffic
+// for addr, where isLocal says whether addr is assigned to the host.
+func localMatch(local, addr string, isLocal bool) bool {
+	if endpointIP(local) == nil {
+		return isLocal
+	}
+	return sameAddr(local, addr)
+}
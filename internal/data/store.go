@@ -6,22 +6,30 @@ import "sync"
 type Store struct {
 	mu sync.RWMutex
 
-	Interfaces  []Interface
-	Routes      []Route
-	Sockets     []Socket
-	UnixSockets []UnixSocket
-	Processes   []Process
-	Firewall    []FirewallRule
-	ARPEntries  []ARPEntry
-	Throughputs map[string]Throughput
-	Errors      []CollectionError
-	IsRoot      bool
+	Interfaces     []Interface
+	Routes         []Route
+	Sockets        []Socket
+	UnixSockets    []UnixSocket
+	Processes      []Process
+	Firewall       []FirewallRule
+	FirewallTables []FirewallTable
+	FirewallStates []FirewallState
+	Flows          []Flow
+	ARPEntries     []ARPEntry
+	Throughputs    map[string]Throughput
+	Errors         []CollectionError
+	IsRoot         bool
+	Namespaces     []Namespace
+	NetNS          string
 
 	// Cross-reference indices
 	SocketsByPID  map[int32][]Socket
 	ProcessByPID  map[int32]*Process
-	RoutesByIface map[string][]Route
-	IfaceByName   map[string]*Interface
+	RoutesByIface map[string][]Route    // keyed by Interface.Key()
+	IfaceByName   map[string]*Interface // keyed by Interface.Key()
+
+	// Listening services aggregated from Sockets
+	Listeners []Listener
 }
 
 // NewStore creates an empty Store.
@@ -46,10 +54,15 @@ func (s *Store) Update(result CollectionResult) {
 	s.UnixSockets = result.UnixSockets
 	s.Processes = result.Processes
 	s.Firewall = result.Firewall
+	s.FirewallTables = result.FirewallTables
+	s.FirewallStates = result.FirewallStates
+	s.Flows = result.Flows
 	s.ARPEntries = result.ARPEntries
 	s.Throughputs = result.Throughputs
 	s.Errors = result.Errors
 	s.IsRoot = result.IsRoot
+	s.Namespaces = result.Namespaces
+	s.NetNS = result.NetNS
 
 	s.rebuildIndices()
 }
@@ -70,14 +83,25 @@ func (s *Store) rebuildIndices() {
 	s.RoutesByIface = make(map[string][]Route)
 	for _, r := range s.Routes {
 		if r.Interface != "" {
-			s.RoutesByIface[r.Interface] = append(s.RoutesByIface[r.Interface], r)
+			key := Interface{Name: r.Interface, Namespace: r.Namespace}.Key()
+			s.RoutesByIface[key] = append(s.RoutesByIface[key], r)
 		}
 	}
 
 	s.IfaceByName = make(map[string]*Interface, len(s.Interfaces))
 	for i := range s.Interfaces {
-		s.IfaceByName[s.Interfaces[i].Name] = &s.Interfaces[i]
+		s.IfaceByName[s.Interfaces[i].Key()] = &s.Interfaces[i]
 	}
+
+	s.Listeners = BuildListeners(s.Sockets, s.ProcessByPID, s.Interfaces)
+	AuditListeners(s.Listeners, s.Firewall)
+	MatchStateSockets(s.FirewallStates, s.Sockets, s.Interfaces)
+}
+
+// MultiNamespace reports whether the data comes from namespaces other than
+// nettui's own, so views should say which namespace each entry is in.
+func (s *Store) MultiNamespace() bool {
+	return s.NetNS != ""
 }
 
 // Snapshot returns a read-locked copy of the current store data.
@@ -86,20 +110,26 @@ func (s *Store) Snapshot() *Store {
 	defer s.mu.RUnlock()
 
 	snap := &Store{
-		Interfaces:    make([]Interface, len(s.Interfaces)),
-		Routes:        make([]Route, len(s.Routes)),
-		Sockets:       make([]Socket, len(s.Sockets)),
-		UnixSockets:   make([]UnixSocket, len(s.UnixSockets)),
-		Processes:     make([]Process, len(s.Processes)),
-		Firewall:      make([]FirewallRule, len(s.Firewall)),
-		ARPEntries:    make([]ARPEntry, len(s.ARPEntries)),
-		Throughputs:   make(map[string]Throughput, len(s.Throughputs)),
-		Errors:        make([]CollectionError, len(s.Errors)),
-		IsRoot:        s.IsRoot,
-		SocketsByPID:  s.SocketsByPID,
-		ProcessByPID:  s.ProcessByPID,
-		RoutesByIface: s.RoutesByIface,
-		IfaceByName:   s.IfaceByName,
+		Interfaces:     make([]Interface, len(s.Interfaces)),
+		Routes:         make([]Route, len(s.Routes)),
+		Sockets:        make([]Socket, len(s.Sockets)),
+		UnixSockets:    make([]UnixSocket, len(s.UnixSockets)),
+		Processes:      make([]Process, len(s.Processes)),
+		Firewall:       make([]FirewallRule, len(s.Firewall)),
+		FirewallTables: s.FirewallTables,
+		FirewallStates: s.FirewallStates,
+		Flows:          s.Flows,
+		ARPEntries:     make([]ARPEntry, len(s.ARPEntries)),
+		Throughputs:    make(map[string]Throughput, len(s.Throughputs)),
+		Errors:         make([]CollectionError, len(s.Errors)),
+		IsRoot:         s.IsRoot,
+		Namespaces:     s.Namespaces,
+		NetNS:          s.NetNS,
+		SocketsByPID:   s.SocketsByPID,
+		ProcessByPID:   s.ProcessByPID,
+		RoutesByIface:  s.RoutesByIface,
+		IfaceByName:    s.IfaceByName,
+		Listeners:      s.Listeners,
 	}
 	copy(snap.Interfaces, s.Interfaces)
 	copy(snap.Routes, s.Routes)
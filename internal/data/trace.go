@@ -0,0 +1,289 @@
+package data
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Packet is the traffic a firewall trace evaluates. A nil address or a
+// zero port stands for any, and an empty Iface for an unknown interface.
+type Packet struct {
+	Dir     string // in or out
+	Proto   string // tcp or udp
+	Iface   string
+	Src     net.IP
+	SrcPort uint32
+	Dst     net.IP
+	DstPort uint32
+}
+
+// String renders the packet in the form ParsePacket accepts.
+func (p Packet) String() string {
+	s := p.Dir + " " + p.Proto
+	if p.Iface != "" {
+		s += " on " + p.Iface
+	}
+	return s + " " + endpoint(p.Src, p.SrcPort) + " -> " + endpoint(p.Dst, p.DstPort)
+}
+
+func endpoint(ip net.IP, port uint32) string {
+	host := "any"
+	if ip != nil {
+		host = ip.String()
+	}
+	if port == 0 {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+}
+
+// TraceStep is one rule considered by a trace.
+type TraceStep struct {
+	Rule   FirewallRule
+	Match  Match
+	Reason string // why the rule matched or not
+}
+
+// Trace is the result of walking the firewall rules for a packet.
+type Trace struct {
+	Packet  Packet
+	Steps   []TraceStep
+	Verdict Verdict
+	Rule    string // Ref of the deciding rule, or "" for the default pass
+}
+
+// TraceRules walks rules in order for pkt, recording for each rule
+// considered whether it matched and why, until a quick rule stops
+// evaluation. Addresses in <table> specs are looked up in tables.
+func TraceRules(rules []FirewallRule, tables []FirewallTable, pkt Packet) Trace {
+	t := Trace{Packet: pkt}
+	if len(rules) == 0 {
+		t.Verdict = VerdictUnknown
+		return t
+	}
+	t.Verdict, t.Rule = evaluate(rules, pkt.Dir, func(r FirewallRule) (Match, string) {
+		return matchPacket(r, pkt, tables)
+	}, func(s TraceStep) {
+		t.Steps = append(t.Steps, s)
+	})
+	return t
+}
+
+// matchPacket reports whether r applies to pkt, with the reason. Every
+// criterion that does not match is named; otherwise those that could not
+// be evaluated are.
+func matchPacket(r FirewallRule, pkt Packet, tables []FirewallTable) (Match, string) {
+	checks := []struct {
+		what  string
+		match Match
+	}{
+		{"direction " + r.Direction, matchDirection(r.Direction, pkt.Dir)},
+		{"interface " + r.Iface, matchIface(r.Iface, pkt.Iface)},
+		{"family " + r.Family, matchIPFamily(r.Family, pkt)},
+		{"proto " + r.Proto, matchProto(r.Proto, pkt.Proto)},
+		{"from " + r.From.String(), matchAddr(r.From, r.Anchor, tables, pkt.Src)},
+		{"from " + r.From.String(), r.From.Port.Match(pkt.SrcPort)},
+		{"to " + r.To.String(), matchAddr(r.To, r.Anchor, tables, pkt.Dst)},
+		{"to " + r.To.String(), r.To.Port.Match(pkt.DstPort)},
+		{"user " + r.User, unknownIf(r.User != "")},
+		{"group " + r.Group, unknownIf(r.Group != "")},
+		{"tagged " + r.Tagged, unknownIf(r.Tagged != "")},
+		{"ct state " + r.CtState, matchCtState(r.CtState)},
+		{r.Extra, unknownIf(r.Extra != "")},
+	}
+	var misses, unknowns []string
+	for _, c := range checks {
+		switch c.match {
+		case MatchNo:
+			misses = appendOnce(misses, c.what)
+		case MatchMaybe:
+			unknowns = appendOnce(unknowns, c.what)
+		}
+	}
+	switch {
+	case len(misses) > 0:
+		return MatchNo, "fails " + strings.Join(misses, ", ")
+	case len(unknowns) > 0:
+		return MatchMaybe, "cannot evaluate " + strings.Join(unknowns, ", ")
+	}
+	return MatchYes, "matches"
+}
+
+// matchAddr matches an address spec against ip, looking up <table> specs
+// in the tables of the rule's anchor.
+func matchAddr(spec AddrSpec, anchor string, tables []FirewallTable, ip net.IP) Match {
+	name := spec.Table()
+	if name == "" {
+		return spec.MatchAddr(ip)
+	}
+	for _, t := range tables {
+		if t.Name == name && t.Anchor == anchor {
+			return spec.MatchTable(t.Addrs, ip)
+		}
+	}
+	return MatchMaybe
+}
+
+func appendOnce(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// unknownIf returns MatchMaybe for a criterion a packet does not carry,
+// such as the owning user, when the rule sets it.
+func unknownIf(set bool) Match {
+	if set {
+		return MatchMaybe
+	}
+	return MatchYes
+}
+
+func matchDirection(ruleDir, dir string) Match {
+	if ruleDir == "" || ruleDir == dir {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// matchIPFamily matches a rule's address family against the packet's
+// addresses. A packet with neither address may be of either family.
+func matchIPFamily(family string, pkt Packet) Match {
+	if family == "" {
+		return MatchYes
+	}
+	ip := pkt.Src
+	if ip == nil {
+		ip = pkt.Dst
+	}
+	if ip == nil {
+		return MatchMaybe
+	}
+	if (ip.To4() == nil) == (family == "inet6") {
+		return MatchYes
+	}
+	return MatchNo
+}
+
+// ParsePacket parses a packet from text like
+//
+//	in tcp on en0 203.0.113.5:51234 -> 10.0.0.2:22
+//
+// The direction defaults to in and the protocol to tcp, the interface and
+// the arrow are optional, IPv6 endpoints with a port are bracketed, and
+// "any" or an omitted port stands for any.
+func ParsePacket(s string) (Packet, error) {
+	pkt := Packet{Dir: "in", Proto: "tcp"}
+	var endpoints []string
+	fields := strings.Fields(s)
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; f {
+		case "in", "out":
+			pkt.Dir = f
+		case "tcp", "udp":
+			pkt.Proto = f
+		case "->":
+		case "on":
+			if i+1 >= len(fields) {
+				return pkt, fmt.Errorf("missing interface after %q", f)
+			}
+			i++
+			pkt.Iface = fields[i]
+		default:
+			endpoints = append(endpoints, f)
+		}
+	}
+	if len(endpoints) != 2 {
+		return pkt, fmt.Errorf("want a source and a destination, got %d endpoints", len(endpoints))
+	}
+	var err error
+	if pkt.Src, pkt.SrcPort, err = parseEndpoint(endpoints[0]); err != nil {
+		return pkt, err
+	}
+	if pkt.Dst, pkt.DstPort, err = parseEndpoint(endpoints[1]); err != nil {
+		return pkt, err
+	}
+	return pkt, nil
+}
+
+// parseEndpoint parses "addr", "addr:port" or "[addr]:port", where addr
+// may be "any" or "*".
+func parseEndpoint(s string) (net.IP, uint32, error) {
+	host, portStr := s, ""
+	if h, p, err := net.SplitHostPort(s); err == nil {
+		host, portStr = h, p
+	}
+	host = strings.Trim(host, "[]")
+	var ip net.IP
+	if host != "any" && host != "*" {
+		if ip = net.ParseIP(host); ip == nil {
+			return nil, 0, fmt.Errorf("invalid address %q", host)
+		}
+	}
+	var port uint32
+	if portStr != "" && portStr != "any" && portStr != "*" {
+		n, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid port %q", portStr)
+		}
+		port = uint32(n)
+	}
+	return ip, port, nil
+}
+
+// SocketPacket returns the packet a trace of sock evaluates: inbound
+// traffic from the peer for listening sockets and for connections
+// accepted on a listening port, and outbound traffic to the peer
+// otherwise. Wildcard addresses stand for any.
+func (s *Store) SocketPacket(sock Socket) Packet {
+	inbound := sock.IsListening()
+	for _, l := range s.Listeners {
+		if l.Namespace == sock.Namespace && l.Proto == strings.TrimSuffix(sock.Proto, "6") && l.Port == sock.LocalPort {
+			inbound = true
+			break
+		}
+	}
+	local, remote := endpointIP(sock.LocalAddr), endpointIP(sock.RemoteAddr)
+	pkt := Packet{Proto: strings.TrimSuffix(sock.Proto, "6")}
+	if inbound {
+		pkt.Dir = "in"
+		pkt.Src, pkt.SrcPort = remote, sock.RemotePort
+		pkt.Dst, pkt.DstPort = local, sock.LocalPort
+	} else {
+		pkt.Dir = "out"
+		pkt.Src, pkt.SrcPort = local, sock.LocalPort
+		pkt.Dst, pkt.DstPort = remote, sock.RemotePort
+	}
+	return pkt
+}
+
+// endpointIP parses a socket address, returning nil for wildcards.
+func endpointIP(addr string) net.IP {
+	ip := net.ParseIP(stripZone(addr))
+	if ip == nil || ip.IsUnspecified() {
+		return nil
+	}
+	return ip
+}
+
+// TraceRules walks the firewall rules loaded in namespace ns for pkt.
+func (s *Store) TraceRules(ns string, pkt Packet) Trace {
+	var rules []FirewallRule
+	for _, r := range s.Firewall {
+		if r.Namespace == ns {
+			rules = append(rules, r)
+		}
+	}
+	var tables []FirewallTable
+	for _, t := range s.FirewallTables {
+		if t.Namespace == ns {
+			tables = append(tables, t)
+		}
+	}
+	return TraceRules(rules, tables, pkt)
+}
@@ -1,25 +1,129 @@
 package data
 
 import (
+	"fmt"
 	"net"
 	"time"
 )
 
 // Interface represents a network interface with IO stats.
 type Interface struct {
-	Name       string
-	Index      int
-	MTU        int
-	Flags      net.Flags
-	HWAddr     string
-	Addrs      []string
-	Up         bool
+	Name      string
+	Index     int
+	MTU       int
+	Flags     net.Flags
+	HWAddr    string
+	Addrs     []string
+	Addresses []Address
+	Up        bool
+	Namespace string // network namespace label when viewing other namespaces
+
+	// Link details. Fields the platform cannot report are left zero.
+	Speed          int    // link speed in Mbit/s, 0 if unknown
+	Duplex         string // full, half
+	OperState      string // up, down, dormant, lowerlayerdown, ...
+	Carrier        bool
+	CarrierChanges uint64
+	Driver         string
+	TxQueueLen     int
+	Kind           string // ethernet, loopback, bridge, bond, vlan, tun, wlan, ...
+
+	// Topology. Master is the bridge or bond this interface is a port of;
+	// Parent is the lower device of a VLAN, macvlan or ipvlan.
+	Master    string
+	Parent    string
+	VLANID    int
+	Peer      string // veth/feth peer in the same namespace
+	PeerIndex int
+	PeerNetNS string // namespace holding the peer, if not this one
+
 	BytesSent  uint64
 	BytesRecv  uint64
 	PacketSent uint64
 	PacketRecv uint64
+	ErrIn      uint64
+	ErrOut     uint64
+	DropIn     uint64
+	DropOut    uint64
+	FifoIn     uint64
+	FifoOut    uint64
 	TxRate     float64 // bytes/sec
 	RxRate     float64 // bytes/sec
+	TxPktRate  float64 // packets/sec
+	RxPktRate  float64 // packets/sec
+
+	ErrInRate   float64 // errors/sec
+	ErrOutRate  float64 // errors/sec
+	DropInRate  float64 // drops/sec
+	DropOutRate float64 // drops/sec
+	FifoInRate  float64 // FIFO errors/sec
+	FifoOutRate float64 // FIFO errors/sec
+
+	// Totals accumulated since nettui started or the totals were last reset.
+	SessionTxBytes uint64
+	SessionRxBytes uint64
+	SessionTxPkts  uint64
+	SessionRxPkts  uint64
+	SessionStart   time.Time
+
+	// History holds recent TX/RX rate samples, oldest first.
+	History []RateSample
+}
+
+// Address is an address assigned to an interface.
+type Address struct {
+	IP        string
+	Family    string // inet, inet6
+	PrefixLen int
+	Broadcast string
+	Scope     string   // host, link, site, global
+	Flags     []string // temporary, deprecated, tentative, dadfailed, ...
+
+	// Lifetimes of dynamically configured addresses. Forever marks static
+	// addresses; zero means the platform did not report a lifetime.
+	ValidLifetime     time.Duration
+	PreferredLifetime time.Duration
+}
+
+// Forever is the lifetime of an address that never expires.
+const Forever time.Duration = -1
+
+// CIDR returns the address in prefix notation.
+func (a Address) CIDR() string {
+	return fmt.Sprintf("%s/%d", a.IP, a.PrefixLen)
+}
+
+// RateSample is one point in an interface's throughput history.
+type RateSample struct {
+	Time   time.Time
+	TxRate float64 // bytes/sec
+	RxRate float64 // bytes/sec
+}
+
+// Key identifies an interface across namespaces: its name, qualified by
+// namespace when one is set.
+func (i Interface) Key() string {
+	if i.Namespace == "" {
+		return i.Name
+	}
+	return i.Namespace + "/" + i.Name
+}
+
+// Utilization returns the busier direction's rate as a percentage of the
+// link speed, or -1 if the link speed is unknown.
+func (i Interface) Utilization() float64 {
+	if i.Speed <= 0 {
+		return -1
+	}
+	rate := max(i.TxRate, i.RxRate) * 8
+	return rate / (float64(i.Speed) * 1e6) * 100
+}
+
+// Degrading reports whether the interface's error, drop or FIFO overrun
+// counters increased since the previous snapshot.
+func (i Interface) Degrading() bool {
+	return i.ErrInRate > 0 || i.ErrOutRate > 0 || i.DropInRate > 0 || i.DropOutRate > 0 ||
+		i.FifoInRate > 0 || i.FifoOutRate > 0
 }
 
 // Route represents a routing table entry.
@@ -29,6 +133,14 @@ type Route struct {
 	Netmask     string
 	Interface   string
 	Flags       string
+	Metric      int // route priority, 0 where the platform does not report it
+	Namespace   string
+}
+
+// IsDefault reports whether the route is a default route: listed as
+// "default", or with a zero netmask as the BSD routing table has it.
+func (r Route) IsDefault() bool {
+	return r.Destination == "default" || r.Netmask == "0.0.0.0" || r.Netmask == "::"
 }
 
 // Socket represents a TCP or UDP connection.
@@ -41,6 +153,24 @@ type Socket struct {
 	State      string
 	PID        int32
 	Process    string
+	Namespace  string
+}
+
+// Tuple identifies the socket by protocol and endpoints, like
+// "tcp 10.0.0.2:5000 203.0.113.5:443", with "*" for wildcards.
+func (s Socket) Tuple() string {
+	return fmt.Sprintf("%s %s %s", s.Proto,
+		wildEndpoint(s.LocalAddr, s.LocalPort), wildEndpoint(s.RemoteAddr, s.RemotePort))
+}
+
+func wildEndpoint(addr string, port uint32) string {
+	if addr == "" || addr == "0.0.0.0" || addr == "::" {
+		addr = "*"
+	}
+	if port == 0 {
+		return addr + ":*"
+	}
+	return fmt.Sprintf("%s:%d", addr, port)
 }
 
 // UnixSocket represents a Unix domain socket.
@@ -56,25 +186,217 @@ type UnixSocket struct {
 // Process represents a process with network activity.
 type Process struct {
 	PID          int32
+	PPID         int32
+	Kernel       bool // kernel thread (Linux)
 	Name         string
 	Command      string
 	User         string
 	NumConns     int
 	NumUnixSocks int
 	Connections  []Socket
+	Namespace    string    // network namespace the process lives in
+	Container    Container // container the process runs in, if any
+	Cgroup       string    // cgroup path, from the unified or systemd hierarchy
+	Unit         string    // systemd unit owning the cgroup, e.g. "nginx.service"
+	Slice        string    // slice containing the unit, e.g. "system.slice"
+
+	// Executable, working directory and resource usage. Fields the
+	// platform or our privileges cannot report are left zero.
+	Exe        string
+	Cwd        string
+	StartTime  time.Time
+	RSS        uint64  // resident set size in bytes
+	CPUTime    float64 // user + system CPU seconds used so far
+	CPUPercent float64 // CPU use over the last refresh interval, 100 per core
+	NumThreads int32
+	NumFDs     int32
+	FDLimit    uint64 // soft RLIMIT_NOFILE, 0 if unknown
+}
+
+// FDUsage returns open file descriptors as a fraction of the soft limit,
+// or -1 if either is unknown or the limit is infinite.
+func (p Process) FDUsage() float64 {
+	if p.FDLimit == 0 || p.FDLimit >= 1<<62 || p.NumFDs == 0 {
+		return -1
+	}
+	return float64(p.NumFDs) / float64(p.FDLimit)
+}
+
+// Container identifies the container a process runs in, as far as its
+// cgroup path and the runtime's on-disk state tell.
+type Container struct {
+	Runtime string // docker, podman, containerd, cri-o, systemd-nspawn, lxc
+	ID      string // container ID, or machine name for systemd-nspawn and lxc
+	Name    string // name recorded by the runtime, if found on disk
+	PodUID  string // Kubernetes pod UID, if the container belongs to a pod
+}
+
+// String renders the container as "runtime:name", using the short ID when
+// the name is unknown, or "" if the process is not in a container.
+func (c Container) String() string {
+	if c.ID == "" && c.PodUID == "" {
+		return ""
+	}
+	name := c.Name
+	if name == "" {
+		name = c.ID
+		if len(name) > 12 {
+			name = name[:12]
+		}
+	}
+	if name == "" {
+		name = "pod " + c.PodUID
+	}
+	return c.Runtime + ":" + name
 }
 
-// FirewallRule represents a pf firewall rule.
+// FirewallRule represents a pf firewall rule, or on Linux a netfilter rule
+// listed by nft or iptables-save. Netfilter chains stand in for anchors,
+// and a base chain's policy is listed as a rule after the chain's own.
 type FirewallRule struct {
-	RuleNum    int
-	Action     string // pass, block
-	Direction  string // in, out
-	Proto      string
-	Src        string
-	Dst        string
-	Packets    uint64
-	Bytes      uint64
-	RawRule    string
+	RuleNum   int
+	Action    string // pass, block, match, anchor, nat, rdr, ...
+	Direction string // in, out
+	Proto     string
+	Src       string
+	Dst       string
+	Packets   uint64
+	Bytes     uint64
+	RawRule   string
+	Namespace string
+
+	// Counter rates since the previous refresh, zero on the first
+	PacketRate float64 // packets/sec
+	ByteRate   float64 // bytes/sec
+
+	// Where the rule is loaded
+	Ruleset  string // filter or nat; the chain type or table on Linux
+	Anchor   string // anchor path, "" for the main ruleset; the chain, like "inet filter/input", on Linux
+	Hook     string // netfilter hook of the base chain the rule is in, like "input"; "" for other chains
+	Priority int    // priority of that base chain, which runs before those of higher priority
+	Policy   bool   // the rule is the base chain's policy
+
+	// Parsed match criteria, as evaluated by TraceRules
+	Quick  bool
+	Iface  string // interface after "on", "" for any
+	Family string // inet, inet6, or "" for both
+	From   AddrSpec
+	To     AddrSpec
+	User   string // user spec like "= 501", "" for any
+	Group  string // group spec, "" for any
+	Tagged string // tag the packet must carry, "! name" for must not
+
+	CtState string // conntrack states matched, like "established,related"; "" for any
+	Extra   string // other netfilter criteria, which cannot be evaluated; "" for none
+
+	// Parsed options
+	Log         string // log options, "log" or e.g. "log (all)", "" when not logging
+	Label       string
+	Tag         string   // tag set on matching packets
+	Flags       string   // TCP flags like "S/SA"
+	State       string   // e.g. "keep state (max 100)", "no state"
+	Translation string   // target after "->" for nat, rdr and binat rules
+	AnchorCall  string   // anchor evaluated by anchor rules, like "com.apple/*"
+	Tables      []string // names of tables referenced by the rule
+}
+
+// Ref identifies the rule across rulesets and anchors: "@3" in the main
+// filter ruleset, "com.apple/250.ApplicationFirewall@3" in an anchor, and
+// "nat@0" or "com.apple/nat@0" for translation rules.
+func (r FirewallRule) Ref() string {
+	ref := r.Anchor
+	if r.Ruleset == "nat" {
+		if ref != "" {
+			ref += "/"
+		}
+		ref += "nat"
+	}
+	return fmt.Sprintf("%s@%d", ref, r.RuleNum)
+}
+
+// FirewallTable is a pf table and its addresses.
+type FirewallTable struct {
+	Name      string
+	Anchor    string // anchor the table is defined in, "" for the main ruleset
+	Addrs     []string
+	Namespace string
+}
+
+// FirewallState is an entry in the firewall's connection state table: a pf
+// state on macOS, a conntrack entry on Linux.
+type FirewallState struct {
+	Proto     string // tcp, udp, icmp, ...
+	Family    string // inet, inet6
+	Direction string // in or out for pf states, "" for conntrack
+	Iface     string // interface the state is bound to, "" for all
+	Orig      FlowTuple
+	Reply     FlowTuple
+	State     string        // e.g. "ESTABLISHED", "ESTABLISHED:ESTABLISHED", "UNREPLIED"
+	Timeout   time.Duration // time left until the entry expires
+	Age       time.Duration // time since the entry was created, 0 if unknown
+
+	OrigPackets  uint64
+	OrigBytes    uint64
+	ReplyPackets uint64
+	ReplyBytes   uint64
+
+	Namespace string
+
+	// Socket is the local socket the entry belongs to, set by the store,
+	// or nil for forwarded traffic and entries without a socket.
+	Socket *Socket
+}
+
+// FlowTuple is one direction of a tracked connection.
+type FlowTuple struct {
+	Src     string
+	SrcPort uint32
+	Dst     string
+	DstPort uint32
+}
+
+// Flow is a connection recorded from conntrack events as it opened and
+// closed, so connections that live between two refreshes are seen too.
+type Flow struct {
+	Proto  string // tcp, udp, icmp, ...
+	Family string // inet, inet6
+	Orig   FlowTuple
+	Reply  FlowTuple
+	State  string    // TCP state as of the last event, "" for other protocols
+	Start  time.Time // zero if it opened before recording began
+	End    time.Time // zero while open
+
+	OrigPackets  uint64
+	OrigBytes    uint64
+	ReplyPackets uint64
+	ReplyBytes   uint64
+
+	// PID and Process own the local socket, if it was seen by a refresh
+	// while the flow was open or is a listener or bound socket.
+	PID     int32
+	Process string
+
+	Namespace string
+}
+
+// AddrSpec is the parsed form of a pf "from" or "to" clause.
+type AddrSpec struct {
+	Not  bool   // address negated with "!"
+	Addr string // "any", an address or network, a <table>, or an (interface)
+	Port PortSpec
+}
+
+// PortSpec is a pf port match. Op is "" for any port, a unary operator
+// ("=", "!=", "<", "<=", ">", ">=") comparing against Lo, or a range
+// operator (":" inclusive, "><" exclusive, "<>" outside) between Lo and
+// Hi, "{" for a netfilter list of specs in List, any of which matches, or
+// "?" when the spec could not be parsed, such as an unknown service name.
+// Name keeps the spec as printed.
+type PortSpec struct {
+	Op     string
+	Lo, Hi uint32
+	Name   string
+	List   []PortSpec
 }
 
 // ARPEntry represents an entry in the ARP table.
@@ -85,6 +407,7 @@ type ARPEntry struct {
 	Hostname  string
 	Flags     string
 	Type      string // ethernet, etc.
+	Namespace string
 }
 
 // Throughput holds per-interface throughput data.
@@ -92,21 +415,58 @@ type Throughput struct {
 	Interface string
 	TxRate    float64 // bytes/sec
 	RxRate    float64 // bytes/sec
+	TxPktRate float64 // packets/sec
+	RxPktRate float64 // packets/sec
+
+	ErrInRate   float64 // errors/sec
+	ErrOutRate  float64 // errors/sec
+	DropInRate  float64 // drops/sec
+	DropOutRate float64 // drops/sec
+	FifoInRate  float64 // FIFO errors/sec
+	FifoOutRate float64 // FIFO errors/sec
+
+	SessionTxBytes uint64
+	SessionRxBytes uint64
+	SessionTxPkts  uint64
+	SessionRxPkts  uint64
+	SessionStart   time.Time
+	History        []RateSample
 }
 
 // CollectionResult holds the result of a single data collection cycle.
 type CollectionResult struct {
-	Interfaces  []Interface
-	Routes      []Route
-	Sockets     []Socket
-	UnixSockets []UnixSocket
-	Processes   []Process
-	Firewall    []FirewallRule
-	ARPEntries  []ARPEntry
-	Throughputs map[string]Throughput
-	Errors      []CollectionError
-	Timestamp   time.Time
-	IsRoot      bool
+	Interfaces     []Interface
+	Routes         []Route
+	Sockets        []Socket
+	UnixSockets    []UnixSocket
+	Processes      []Process
+	Firewall       []FirewallRule
+	FirewallTables []FirewallTable
+	FirewallStates []FirewallState
+	Flows          []Flow
+	ARPEntries     []ARPEntry
+	Throughputs    map[string]Throughput
+	Errors         []CollectionError
+	Timestamp      time.Time
+	IsRoot         bool
+
+	// Namespaces lists the network namespaces found on the host, and
+	// NetNS is the one being viewed: "" for nettui's own namespace,
+	// AllNamespaces, or a namespace name.
+	Namespaces []Namespace
+	NetNS      string
+}
+
+// AllNamespaces selects every network namespace at once.
+const AllNamespaces = "*"
+
+// Namespace is a network namespace discovered on the host.
+type Namespace struct {
+	ID   uint64  // inode of the namespace file
+	Name string  // /run/netns name, or "pid N (comm)" of its first process
+	Path string  // file to enter it through
+	PIDs []int32 // processes living in it
+	Self bool    // nettui's own namespace
 }
 
 // CollectionError records a non-fatal error during collection.
@@ -70,6 +70,12 @@ var (
 				Background(lipgloss.Color("#374151")).
 				Foreground(FgColor)
 
+	DegradedRowStyle = lipgloss.NewStyle().
+				Foreground(ErrorColor)
+
+	ActiveRowStyle = lipgloss.NewStyle().
+			Foreground(AccentColor)
+
 	// Misc
 	ErrorStyle = lipgloss.NewStyle().
 			Foreground(ErrorColor).
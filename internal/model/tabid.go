@@ -11,10 +11,13 @@ const (
 	TabRoutes
 	TabARP
 	TabFirewall
+	TabListeners
+	TabStates
+	TabFlows
 )
 
 // TabCount is the total number of tabs.
-const TabCount = 7
+const TabCount = 10
 
 // TabName returns the display name for a tab.
 func TabName(id TabID) string {
@@ -33,6 +36,12 @@ func TabName(id TabID) string {
 		return "ARP"
 	case TabFirewall:
 		return "Firewall"
+	case TabListeners:
+		return "Listeners"
+	case TabStates:
+		return "States"
+	case TabFlows:
+		return "Flows"
 	default:
 		return "Unknown"
 	}
@@ -1,12 +1,15 @@
 package arp
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
 		table.NewColumn("ip", "IP", 18).WithFiltered(true),
 		table.NewColumn("mac", "MAC", 19).WithFiltered(true),
 		table.NewColumn("iface", "Interface", 12).WithFiltered(true),
 		table.NewFlexColumn("flags", "Flags", 1),
-	}
+	}, netns)
 }
@@ -27,6 +27,7 @@ func detailContent(rowData map[string]interface{}) string {
 		{"Interface", "iface"},
 		{"Flags", "flags"},
 		{"Type", "type"},
+		{"Namespace", "netns"},
 	}
 
 	for _, f := range fields {
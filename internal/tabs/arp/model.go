@@ -19,6 +19,7 @@ type Model struct {
 	height int
 	tabID  model.TabID
 	sort   tabs.SortState
+	netns  bool
 }
 
 var sortEntries = []tabs.SortEntry{
@@ -33,7 +34,7 @@ func New() *Model {
 	m := &Model{
 		tabID: model.TabARP,
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -51,6 +52,7 @@ func (m *Model) buildRows() []table.Row {
 	rows := make([]table.Row, 0, len(m.store.ARPEntries))
 	for _, e := range m.store.ARPEntries {
 		rows = append(rows, table.NewRow(table.RowData{
+			"netns":    e.Namespace,
 			"ip":       e.IP,
 			"mac":      e.MAC,
 			"iface":    e.Interface,
@@ -109,6 +111,10 @@ func (m *Model) View() string {
 // SetData implements Tab.
 func (m *Model) SetData(store *data.Store) {
 	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
 	rows := m.buildRows()
 	if m.sort.Active() {
 		m.sort.SortRows(rows)
@@ -140,6 +146,21 @@ func (m *Model) SelectedRow() string {
 	return fmt.Sprintf("%s at %s on %s", ip, mac, iface)
 }
 
+// SelectedEntry returns the neighbor entry of the highlighted row.
+func (m *Model) SelectedEntry() (data.ARPEntry, bool) {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return data.ARPEntry{}, false
+	}
+	e := data.ARPEntry{}
+	e.IP, _ = row.Data["ip"].(string)
+	e.MAC, _ = row.Data["mac"].(string)
+	e.Interface, _ = row.Data["iface"].(string)
+	e.Flags, _ = row.Data["flags"].(string)
+	e.Namespace, _ = row.Data["netns"].(string)
+	return e, true
+}
+
 // DetailContent implements Tab.
 func (m *Model) DetailContent() string {
 	row := m.table.HighlightedRow()
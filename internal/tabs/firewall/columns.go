@@ -1,16 +1,23 @@
 package firewall
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
 		table.NewColumn("rule", "Rule#", 7),
+		table.NewColumn("anchor", "Anchor", 18).WithFiltered(true),
 		table.NewColumn("action", "Action", 8).WithFiltered(true),
 		table.NewColumn("dir", "Direction", 11).WithFiltered(true),
+		table.NewColumn("iface", "On", 8).WithFiltered(true),
 		table.NewColumn("proto", "Proto", 8),
 		table.NewFlexColumn("src", "Src", 1).WithFiltered(true),
 		table.NewFlexColumn("dst", "Dst", 1).WithFiltered(true),
 		table.NewColumn("packets", "Packets", 10),
 		table.NewColumn("bytes", "Bytes", 10),
-	}
+		table.NewColumn("pkt_rate", "Pkts/s", 10),
+		table.NewColumn("byte_rate", "Rate", 11),
+	}, netns)
 }
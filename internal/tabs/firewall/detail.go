@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jerryluo/nettui/internal/data"
 	"github.com/jerryluo/nettui/internal/model"
 )
 
-func detailContent(rowData map[string]interface{}) string {
+// maxTableAddrs caps the addresses listed per expanded table.
+const maxTableAddrs = 200
+
+func detailContent(rowData map[string]interface{}, tables []data.FirewallTable, expand bool) string {
 	if rowData == nil {
 		return ""
 	}
@@ -20,24 +24,77 @@ func detailContent(rowData map[string]interface{}) string {
 	fields := []struct {
 		label string
 		key   string
+		omit  bool // skip when empty
 	}{
-		{"Rule #", "rule"},
-		{"Action", "action"},
-		{"Direction", "dir"},
-		{"Protocol", "proto"},
-		{"Source", "src"},
-		{"Destination", "dst"},
-		{"Packets", "packets"},
-		{"Bytes", "bytes"},
-		{"Raw Rule", "raw_rule"},
+		{"Rule #", "rule", false},
+		{"Ref", "raw_ref", false},
+		{"Anchor", "anchor", true},
+		{"Ruleset", "ruleset", false},
+		{"Action", "action", false},
+		{"Direction", "dir", false},
+		{"Quick", "quick", true},
+		{"Interface", "iface", true},
+		{"Family", "family", true},
+		{"Protocol", "proto", false},
+		{"Source", "src", false},
+		{"Destination", "dst", false},
+		{"User", "user", true},
+		{"Group", "group", true},
+		{"Flags", "flags", true},
+		{"State", "state", true},
+		{"Log", "log", true},
+		{"Label", "label", true},
+		{"Tag", "tag", true},
+		{"Tagged", "tagged", true},
+		{"Hook", "hook", true},
+		{"Conntrack", "ct_state", true},
+		{"Other Match", "extra", true},
+		{"Anchor Call", "anchor_call", true},
+		{"Translation", "translation", true},
+		{"Packets", "packets", false},
+		{"Bytes", "bytes", false},
+		{"Packet Rate", "pkt_rate", false},
+		{"Byte Rate", "byte_rate", false},
+		{"Raw Rule", "raw_rule", false},
+		{"Namespace", "netns", true},
 	}
 
 	for _, f := range fields {
 		val := fmt.Sprintf("%v", rowData[f.key])
+		if f.omit && val == "" {
+			continue
+		}
 		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-14s", f.label)))
 		b.WriteString(model.PanelValueStyle.Render(val))
 		b.WriteString("\n")
 	}
 
+	if len(tables) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n")
+	b.WriteString(model.PanelHeaderStyle.Render("Tables"))
+	if !expand {
+		b.WriteString(model.PanelLabelStyle.Render("  (space to expand)"))
+	}
+	b.WriteString("\n")
+	for _, t := range tables {
+		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("<%s>", t.Name)))
+		b.WriteString(model.PanelValueStyle.Render(fmt.Sprintf("  %d entries", len(t.Addrs))))
+		b.WriteString("\n")
+		if !expand {
+			continue
+		}
+		for i, a := range t.Addrs {
+			if i == maxTableAddrs {
+				b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("  … %d more", len(t.Addrs)-i)))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString(model.PanelValueStyle.Render("  " + a))
+			b.WriteString("\n")
+		}
+	}
+
 	return b.String()
 }
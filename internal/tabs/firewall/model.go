@@ -2,6 +2,7 @@ package firewall
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,11 +20,17 @@ type Model struct {
 	width  int
 	height int
 	tabID  model.TabID
+	navKey string
+	navVal string
 	sort   tabs.SortState
+	netns  bool
+
+	expandTables bool // list table addresses in the detail panel
 }
 
 var sortEntries = []tabs.SortEntry{
 	{Key: "r", ColKey: "rule", SortKey: "rule", Label: "Rule#"},
+	{Key: "n", ColKey: "anchor", SortKey: "anchor", Label: "Anchor"},
 	{Key: "a", ColKey: "action", SortKey: "action", Label: "Action"},
 	{Key: "i", ColKey: "dir", SortKey: "dir", Label: "Direction"},
 	{Key: "p", ColKey: "proto", SortKey: "proto", Label: "Proto"},
@@ -31,6 +38,8 @@ var sortEntries = []tabs.SortEntry{
 	{Key: "d", ColKey: "dst", SortKey: "dst", Label: "Dst"},
 	{Key: "k", ColKey: "packets", SortKey: "packets", Label: "Packets"},
 	{Key: "b", ColKey: "bytes", SortKey: "raw_bytes", Label: "Bytes"},
+	{Key: "h", ColKey: "pkt_rate", SortKey: "raw_pkt_rate", Label: "Pkts/s"},
+	{Key: "t", ColKey: "byte_rate", SortKey: "raw_byte_rate", Label: "Rate"},
 }
 
 // New creates a new Firewall tab model.
@@ -38,7 +47,7 @@ func New() *Model {
 	m := &Model{
 		tabID: model.TabFirewall,
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -55,18 +64,61 @@ func (m *Model) buildRows() []table.Row {
 	}
 	rows := make([]table.Row, 0, len(m.store.Firewall))
 	for _, r := range m.store.Firewall {
-		rows = append(rows, table.NewRow(table.RowData{
-			"rule":     fmt.Sprintf("%d", r.RuleNum),
-			"action":   r.Action,
-			"dir":      r.Direction,
-			"proto":    r.Proto,
-			"src":      r.Src,
-			"dst":      r.Dst,
-			"packets":  fmt.Sprintf("%d", r.Packets),
-			"bytes":    util.FormatBytes(r.Bytes),
-			"raw_rule":  r.RawRule,
-			"raw_bytes": r.Bytes,
-		}))
+		anchor := r.Anchor
+		if r.Ruleset == "nat" {
+			anchor = strings.TrimPrefix(anchor+" (nat)", " ")
+		}
+		quick := ""
+		if r.Quick {
+			quick = "yes"
+		}
+		row := table.NewRow(table.RowData{
+			"netns":         r.Namespace,
+			"rule":          fmt.Sprintf("%d", r.RuleNum),
+			"anchor":        anchor,
+			"ruleset":       r.Ruleset,
+			"action":        r.Action,
+			"dir":           r.Direction,
+			"iface":         r.Iface,
+			"proto":         r.Proto,
+			"src":           r.Src,
+			"dst":           r.Dst,
+			"packets":       fmt.Sprintf("%d", r.Packets),
+			"bytes":         util.FormatBytes(r.Bytes),
+			"pkt_rate":      util.FormatPacketRate(r.PacketRate),
+			"byte_rate":     util.FormatRate(r.ByteRate),
+			"quick":         quick,
+			"family":        r.Family,
+			"user":          r.User,
+			"group":         r.Group,
+			"flags":         r.Flags,
+			"state":         r.State,
+			"log":           r.Log,
+			"label":         r.Label,
+			"tag":           r.Tag,
+			"tagged":        r.Tagged,
+			"hook":          r.Hook,
+			"ct_state":      r.CtState,
+			"extra":         r.Extra,
+			"anchor_call":   r.AnchorCall,
+			"translation":   r.Translation,
+			"raw_ref":       r.Ref(),
+			"raw_anchor":    r.Anchor,
+			"raw_tables":    r.Tables,
+			"raw_rule":      r.RawRule,
+			"raw_bytes":     r.Bytes,
+			"raw_pkt_rate":  r.PacketRate,
+			"raw_byte_rate": r.ByteRate,
+		})
+		// Highlight rules matching traffic right now, block rules in red.
+		if r.Hit() {
+			if r.Action == "block" {
+				row = row.WithStyle(model.DegradedRowStyle)
+			} else {
+				row = row.WithStyle(model.ActiveRowStyle)
+			}
+		}
+		rows = append(rows, row)
 	}
 	return rows
 }
@@ -96,7 +148,14 @@ func (m *Model) View() string {
 // SetData implements Tab.
 func (m *Model) SetData(store *data.Store) {
 	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
 	rows := m.buildRows()
+	if m.navKey != "" {
+		rows = tabs.FilterNavRows(rows, "raw_ref", m.navVal)
+	}
 	if m.sort.Active() {
 		m.sort.SortRows(rows)
 	}
@@ -124,10 +183,10 @@ func (m *Model) SelectedRow() string {
 	if row.Data == nil {
 		return ""
 	}
-	rule, _ := row.Data["rule"].(string)
+	ref, _ := row.Data["raw_ref"].(string)
 	action, _ := row.Data["action"].(string)
 	dir, _ := row.Data["dir"].(string)
-	return fmt.Sprintf("Rule %s: %s %s", rule, action, dir)
+	return fmt.Sprintf("Rule %s: %s %s", ref, action, dir)
 }
 
 // DetailContent implements Tab.
@@ -139,7 +198,31 @@ func (m *Model) DetailContent() string {
 	if row.Data == nil {
 		return ""
 	}
-	return detailContent(row.Data)
+	return detailContent(row.Data, m.rowTables(row.Data), m.expandTables)
+}
+
+// rowTables returns the tables referenced by a rule, as defined in its
+// anchor and namespace.
+func (m *Model) rowTables(rowData table.RowData) []data.FirewallTable {
+	names, _ := rowData["raw_tables"].([]string)
+	anchor, _ := rowData["raw_anchor"].(string)
+	netns, _ := rowData["netns"].(string)
+	var tables []data.FirewallTable
+	for _, name := range names {
+		for _, t := range m.store.FirewallTables {
+			if t.Name == name && t.Anchor == anchor && t.Namespace == netns {
+				tables = append(tables, t)
+				break
+			}
+		}
+	}
+	return tables
+}
+
+// ToggleTables expands or collapses the addresses of the tables listed in
+// the detail panel.
+func (m *Model) ToggleTables() {
+	m.expandTables = !m.expandTables
 }
 
 // CrossRef implements Tab.
@@ -147,11 +230,28 @@ func (m *Model) CrossRef() *model.CrossRefMsg {
 	return nil
 }
 
-// NavigateTo implements Tab.
-func (m *Model) NavigateTo(key, val string) {}
+// NavigateTo implements Tab. The "rule" key takes a rule's Ref.
+func (m *Model) NavigateTo(key, val string) {
+	if key != "rule" {
+		return
+	}
+	m.navKey = key
+	m.navVal = val
+	rows := m.buildRows()
+	rows = tabs.FilterNavRows(rows, "raw_ref", val)
+	if m.sort.Active() {
+		m.sort.SortRows(rows)
+	}
+	m.table = m.table.WithRows(rows).WithHighlightedRow(0)
+}
 
 // NavFilterLabel implements Tab.
-func (m *Model) NavFilterLabel() string { return "" }
+func (m *Model) NavFilterLabel() string {
+	if m.navKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("[→%s: %s]", m.navKey, m.navVal)
+}
 
 // SortHint implements Tab.
 func (m *Model) SortHint() string {
@@ -164,6 +264,9 @@ func (m *Model) ApplySort(key string) {
 		return
 	}
 	rows := m.buildRows()
+	if m.navKey != "" {
+		rows = tabs.FilterNavRows(rows, "raw_ref", m.navVal)
+	}
 	m.sort.SortRows(rows)
 	m.table = m.table.WithRows(rows)
 }
@@ -178,7 +281,7 @@ func (m *Model) SetPanelWidth(width int) {}
 
 // YankHint implements Tab.
 func (m *Model) YankHint() string {
-	return "y→  r:Rule  s:Src  d:Dst  y:All"
+	return "y→  r:Rule  s:Src  d:Dst  l:Label  y:All"
 }
 
 // YankField implements Tab.
@@ -197,6 +300,9 @@ func (m *Model) YankField(key string) string {
 	case "d":
 		v, _ := row.Data["dst"].(string)
 		return v
+	case "l":
+		v, _ := row.Data["label"].(string)
+		return v
 	case "y":
 		return m.SelectedRow()
 	}
@@ -210,10 +316,22 @@ func (m *Model) IsFiltering() bool {
 
 // HasActiveFilter implements Tab.
 func (m *Model) HasActiveFilter() bool {
-	return m.table.GetCurrentFilter() != ""
+	return m.table.GetCurrentFilter() != "" || m.navKey != ""
 }
 
 // ClearFilter implements Tab.
 func (m *Model) ClearFilter() {
-	m.table = m.table.WithFilterInputValue("")
+	if m.table.GetCurrentFilter() != "" {
+		m.table = m.table.WithFilterInputValue("")
+		return
+	}
+	if m.navKey != "" {
+		m.navKey = ""
+		m.navVal = ""
+		rows := m.buildRows()
+		if m.sort.Active() {
+			m.sort.SortRows(rows)
+		}
+		m.table = m.table.WithRows(rows)
+	}
 }
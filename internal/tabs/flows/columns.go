@@ -0,0 +1,20 @@
+package flows
+
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
+
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
+		table.NewColumn("start", "Start", 9),
+		table.NewColumn("proto", "Proto", 6).WithFiltered(true),
+		table.NewFlexColumn("orig", "Flow", 3).WithFiltered(true),
+		table.NewColumn("state", "State", 12).WithFiltered(true),
+		table.NewColumn("duration", "Duration", 9),
+		table.NewColumn("packets", "Packets", 9),
+		table.NewColumn("bytes", "Bytes", 10),
+		table.NewColumn("pid", "PID", 7).WithFiltered(true),
+		table.NewColumn("process", "Process", 16).WithFiltered(true),
+	}, netns)
+}
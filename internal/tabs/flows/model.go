@@ -0,0 +1,275 @@
+package flows
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/tabs"
+	"github.com/jerryluo/nettui/internal/util"
+)
+
+// Model is the Flows tab model.
+type Model struct {
+	table  table.Model
+	store  *data.Store
+	width  int
+	height int
+	tabID  model.TabID
+	sort   tabs.SortState
+	netns  bool
+}
+
+var sortEntries = []tabs.SortEntry{
+	{Key: "t", ColKey: "start", SortKey: "raw_start_ns", Label: "Start"},
+	{Key: "p", ColKey: "proto", SortKey: "proto", Label: "Proto"},
+	{Key: "s", ColKey: "state", SortKey: "state", Label: "State"},
+	{Key: "d", ColKey: "duration", SortKey: "raw_duration", Label: "Duration"},
+	{Key: "k", ColKey: "packets", SortKey: "raw_packets", Label: "Packets"},
+	{Key: "b", ColKey: "bytes", SortKey: "raw_bytes", Label: "Bytes"},
+	{Key: "i", ColKey: "pid", SortKey: "raw_pid", Label: "PID"},
+	{Key: "n", ColKey: "process", SortKey: "process", Label: "Process"},
+}
+
+// New creates a new Flows tab model.
+func New() *Model {
+	m := &Model{
+		tabID: model.TabFlows,
+	}
+	m.table = table.New(columns(false)).
+		WithBaseStyle(lipgloss.NewStyle()).
+		Focused(true).
+		WithPageSize(20).
+		Filtered(true).
+		HeaderStyle(model.TableHeaderStyle).
+		HighlightStyle(model.SelectedRowStyle).
+		WithPaginationWrapping(false)
+	return m
+}
+
+// buildRows lists the flows newest first, with open ones highlighted.
+func (m *Model) buildRows() []table.Row {
+	if m.store == nil {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]table.Row, 0, len(m.store.Flows))
+	for _, f := range m.store.Flows {
+		start, startNs, opened, closed := "-", int64(0), "", ""
+		if !f.Start.IsZero() {
+			start, startNs, opened = f.Start.Format("15:04:05"), f.Start.UnixNano(), f.Start.Format(time.DateTime+".000")
+		}
+		if !f.Open() {
+			closed = f.End.Format(time.DateTime + ".000")
+		}
+		state := f.State
+		switch {
+		case !f.Open():
+			state = "closed"
+		case state == "":
+			state = "open"
+		}
+		duration := ""
+		if d := f.Duration(now); d > 0 {
+			duration = formatDuration(d)
+		}
+		row := table.NewRow(table.RowData{
+			"netns":             f.Namespace,
+			"start":             start,
+			"proto":             f.Proto,
+			"family":            f.Family,
+			"orig":              f.Orig.String(),
+			"state":             state,
+			"duration":          duration,
+			"packets":           fmt.Sprintf("%d", f.Packets()),
+			"bytes":             util.FormatBytes(f.Bytes()),
+			"pid":               util.FormatPID(f.PID),
+			"process":           util.FormatProcess(f.Process),
+			"raw_reply":         f.Reply.String(),
+			"raw_start":         opened,
+			"raw_end":           closed,
+			"raw_packets_split": fmt.Sprintf("%d orig, %d reply", f.OrigPackets, f.ReplyPackets),
+			"raw_bytes_split":   fmt.Sprintf("%s orig, %s reply", util.FormatBytes(f.OrigBytes), util.FormatBytes(f.ReplyBytes)),
+			"raw_start_ns":      startNs,
+			"raw_duration":      int64(f.Duration(now)),
+			"raw_packets":       f.Packets(),
+			"raw_bytes":         f.Bytes(),
+			"raw_pid":           f.PID,
+		})
+		if f.Open() {
+			row = row.WithStyle(model.ActiveRowStyle)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// formatDuration formats a flow's duration, in milliseconds below a
+// second since most short-lived flows are.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return util.FormatDuration(d)
+}
+
+// Init implements tea.Model.
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.table, cmd = tabs.ClampedUpdate(m.table, msg)
+	return m, cmd
+}
+
+// View implements tea.Model.
+func (m *Model) View() string {
+	return m.table.View()
+}
+
+// SetData implements Tab.
+func (m *Model) SetData(store *data.Store) {
+	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
+	rows := m.buildRows()
+	if m.sort.Active() {
+		m.sort.SortRows(rows)
+	}
+	m.table = m.table.WithRows(rows)
+}
+
+// SetSize implements Tab.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table = m.table.WithPageSize(height - 6).WithTargetWidth(width)
+}
+
+// TabID implements Tab.
+func (m *Model) TabID() model.TabID {
+	return m.tabID
+}
+
+// SelectedRow implements Tab.
+func (m *Model) SelectedRow() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	proto, _ := row.Data["proto"].(string)
+	orig, _ := row.Data["orig"].(string)
+	duration, _ := row.Data["duration"].(string)
+	bytes, _ := row.Data["bytes"].(string)
+	return fmt.Sprintf("%s %s %s %s", proto, orig, duration, bytes)
+}
+
+// DetailContent implements Tab.
+func (m *Model) DetailContent() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	return detailContent(row.Data)
+}
+
+// CrossRef implements Tab. It targets the Processes tab filtered to the
+// process the selected flow is attributed to.
+func (m *Model) CrossRef() *model.CrossRefMsg {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return nil
+	}
+	pid, _ := row.Data["raw_pid"].(int32)
+	if pid <= 0 {
+		return nil
+	}
+	return &model.CrossRefMsg{
+		TargetTab: model.TabProcesses,
+		FilterKey: "pid",
+		FilterVal: fmt.Sprintf("%d", pid),
+	}
+}
+
+// NavigateTo implements Tab.
+func (m *Model) NavigateTo(key, val string) {}
+
+// NavFilterLabel implements Tab.
+func (m *Model) NavFilterLabel() string { return "" }
+
+// SortHint implements Tab.
+func (m *Model) SortHint() string {
+	return tabs.Hint(sortEntries)
+}
+
+// ApplySort implements Tab.
+func (m *Model) ApplySort(key string) {
+	if !m.sort.Apply(sortEntries, key) {
+		return
+	}
+	rows := m.buildRows()
+	m.sort.SortRows(rows)
+	m.table = m.table.WithRows(rows)
+}
+
+// SortLabel implements Tab.
+func (m *Model) SortLabel() string {
+	return m.sort.Label()
+}
+
+// SetPanelWidth implements Tab.
+func (m *Model) SetPanelWidth(width int) {}
+
+// YankHint implements Tab.
+func (m *Model) YankHint() string {
+	return "y→  o:Flow  r:Reply  p:PID  n:Process  y:All"
+}
+
+// YankField implements Tab.
+func (m *Model) YankField(key string) string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	switch key {
+	case "o":
+		v, _ := row.Data["orig"].(string)
+		return v
+	case "r":
+		v, _ := row.Data["raw_reply"].(string)
+		return v
+	case "p":
+		v, _ := row.Data["pid"].(string)
+		return v
+	case "n":
+		v, _ := row.Data["process"].(string)
+		return v
+	case "y":
+		return m.SelectedRow()
+	}
+	return ""
+}
+
+// IsFiltering implements Tab.
+func (m *Model) IsFiltering() bool {
+	return m.table.GetIsFilterInputFocused()
+}
+
+// HasActiveFilter implements Tab.
+func (m *Model) HasActiveFilter() bool {
+	return m.table.GetCurrentFilter() != ""
+}
+
+// ClearFilter implements Tab.
+func (m *Model) ClearFilter() {
+	m.table = m.table.WithFilterInputValue("")
+}
@@ -0,0 +1,101 @@
+package tabs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+)
+
+// Grouper is implemented by tabs that can group their rows by a column.
+type Grouper interface {
+	// CycleGroup advances to the next grouping, ending with none.
+	CycleGroup()
+
+	// GroupLabel returns the current grouping indicator (e.g. "[by Container]"), or "".
+	GroupLabel() string
+}
+
+// GroupEntry describes one column a tab can group its rows by.
+type GroupEntry struct {
+	ColKey string // display column key ("container")
+	RawKey string // RowData key holding the ungrouped value ("raw_container")
+	Label  string // display name ("Container")
+}
+
+// GroupState tracks which column, if any, rows are grouped by.
+type GroupState struct {
+	entry *GroupEntry
+}
+
+var groupContinuationStyle = lipgloss.NewStyle().Faint(true)
+
+// Cycle moves to the entry after the current one, or back to no grouping
+// after the last.
+func (g *GroupState) Cycle(entries []GroupEntry) {
+	next := 0
+	if g.entry != nil {
+		for i := range entries {
+			if entries[i].ColKey == g.entry.ColKey {
+				next = i + 1
+				break
+			}
+		}
+	}
+	if next >= len(entries) {
+		g.entry = nil
+		return
+	}
+	g.entry = &entries[next]
+}
+
+// Active returns true if rows are currently grouped.
+func (g *GroupState) Active() bool {
+	return g.entry != nil
+}
+
+// Label returns a display indicator like "[by Container]", or "" if inactive.
+func (g *GroupState) Label() string {
+	if g.entry == nil {
+		return ""
+	}
+	return "[by " + g.entry.Label + "]"
+}
+
+// GroupRows orders rows so that each group is contiguous, keeping the
+// existing order within a group and listing rows without a value last.
+// The first row of a group shows the value with the group's size; the
+// rest show it faintly.
+func (g *GroupState) GroupRows(rows []table.Row) {
+	if g.entry == nil || len(rows) == 0 {
+		return
+	}
+	col, raw := g.entry.ColKey, g.entry.RawKey
+	value := func(r table.Row) string {
+		v, _ := r.Data[raw].(string)
+		return v
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := value(rows[i]), value(rows[j])
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return a < b
+	})
+
+	for start := 0; start < len(rows); {
+		v := value(rows[start])
+		end := start + 1
+		for end < len(rows) && value(rows[end]) == v {
+			end++
+		}
+		if v != "" {
+			rows[start].Data[col] = fmt.Sprintf("%s (%d)", v, end-start)
+			for _, r := range rows[start+1 : end] {
+				r.Data[col] = table.NewStyledCell(v, groupContinuationStyle)
+			}
+		}
+		start = end
+	}
+}
@@ -0,0 +1,232 @@
+package interfaces
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/util"
+)
+
+const (
+	// chartLabelWidth is the width of the y-axis label gutter.
+	chartLabelWidth = 12
+	// trendWidth is the number of samples shown in the inline sparklines.
+	trendWidth = 8
+)
+
+var chartBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var (
+	txChartStyle   = lipgloss.NewStyle().Foreground(model.SecondaryColor)
+	rxChartStyle   = lipgloss.NewStyle().Foreground(model.SuccessColor)
+	peakChartStyle = lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true)
+	avgChartStyle  = lipgloss.NewStyle().Foreground(model.MutedColor)
+	hintChartStyle = lipgloss.NewStyle().Foreground(model.AccentColor)
+)
+
+// renderChart draws stacked full-screen TX and RX graphs for one interface.
+// Without autoRefresh the header notes that samples only come from manual
+// refreshes, which also space them unevenly.
+func renderChart(iface *data.Interface, width, height int, bits, autoRefresh bool) string {
+	if iface == nil {
+		return model.HelpDescStyle.Render("No interface selected")
+	}
+
+	units := "bytes"
+	if bits {
+		units = "bits"
+	}
+	header := model.PanelHeaderStyle.Render(iface.Name) +
+		model.HelpDescStyle.Render(fmt.Sprintf("  throughput history (%s)  b:units  c/esc:close", units))
+	if !autoRefresh {
+		header += hintChartStyle.Render("  auto-refresh off: a sample per r press (run with -interval)")
+	}
+
+	// Each series gets a title line, the plot, and two axis lines.
+	plotHeight := (height - 1 - 2*3) / 2
+	if plotHeight < 2 {
+		plotHeight = 2
+	}
+	plotWidth := width - chartLabelWidth - 1
+	if plotWidth < 10 {
+		plotWidth = 10
+	}
+
+	samples := iface.History
+	if len(samples) > plotWidth {
+		samples = samples[len(samples)-plotWidth:]
+	}
+
+	tx := make([]float64, len(samples))
+	rx := make([]float64, len(samples))
+	for i, s := range samples {
+		tx[i] = s.TxRate
+		rx[i] = s.RxRate
+	}
+
+	format := util.FormatRate
+	if bits {
+		format = util.FormatBitRate
+	}
+
+	axis := renderTimeAxis(samples, plotWidth)
+	parts := []string{
+		header,
+		renderSeries("TX", tx, samples, plotWidth, plotHeight, txChartStyle, format),
+		axis,
+		renderSeries("RX", rx, samples, plotWidth, plotHeight, rxChartStyle, format),
+		axis,
+	}
+	return strings.Join(parts, "\n")
+}
+
+// renderSeries draws one titled bar graph with y-axis labels, an average
+// line and the peak sample highlighted.
+func renderSeries(title string, values []float64, samples []data.RateSample, width, height int, style lipgloss.Style, format func(float64) string) string {
+	peak, peakIdx, sum := 0.0, -1, 0.0
+	for i, v := range values {
+		sum += v
+		if v > peak {
+			peak, peakIdx = v, i
+		}
+	}
+	avg, now := 0.0, 0.0
+	if len(values) > 0 {
+		avg = sum / float64(len(values))
+		now = values[len(values)-1]
+	}
+
+	summary := fmt.Sprintf("  now %s  avg %s  peak %s", format(now), format(avg), format(peak))
+	if peakIdx >= 0 {
+		summary += " @ " + formatAgo(samples[len(samples)-1].Time.Sub(samples[peakIdx].Time))
+	}
+
+	var b strings.Builder
+	b.WriteString(style.Bold(true).Render(title))
+	b.WriteString(model.HelpDescStyle.Render(summary))
+	b.WriteString("\n")
+
+	scale := peak
+	if scale <= 0 {
+		scale = 1
+	}
+	avgRow := -1
+	if avg > 0 {
+		avgRow = height - 1 - int(avg/scale*float64(height))
+		avgRow = max(0, min(height-1, avgRow))
+	}
+	offset := width - len(values)
+
+	for r := 0; r < height; r++ {
+		label := ""
+		switch {
+		case r == 0:
+			label = format(scale)
+		case r == avgRow:
+			label = "avg"
+		case r == height-1:
+			label = "0"
+		}
+		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%*s ", chartLabelWidth-1, label)))
+		b.WriteString(model.PanelLabelStyle.Render("│"))
+
+		rowFloor := (height - 1 - r) * 8
+		var run strings.Builder
+		runStyle := style
+		flush := func() {
+			if run.Len() > 0 {
+				b.WriteString(runStyle.Render(run.String()))
+				run.Reset()
+			}
+		}
+		for c := 0; c < width; c++ {
+			ch, st := ' ', style
+			if r == avgRow {
+				ch, st = '┄', avgChartStyle
+			}
+			if i := c - offset; i >= 0 {
+				eighths := int(values[i] / scale * float64(height*8))
+				switch {
+				case eighths >= rowFloor+8:
+					ch = '█'
+				case eighths > rowFloor:
+					ch = chartBlocks[eighths-rowFloor-1]
+				}
+				if eighths > rowFloor {
+					st = style
+					if i == peakIdx {
+						st = peakChartStyle
+					}
+				}
+			}
+			if st.GetForeground() != runStyle.GetForeground() {
+				flush()
+				runStyle = st
+			}
+			run.WriteRune(ch)
+		}
+		flush()
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderTimeAxis draws the x-axis with tick labels relative to the newest sample.
+func renderTimeAxis(samples []data.RateSample, width int) string {
+	gutter := strings.Repeat(" ", chartLabelWidth)
+	line := []rune(strings.Repeat("─", width))
+	labels := []rune(strings.Repeat(" ", width+1))
+
+	offset := width - len(samples)
+	ticks := []int{0, width / 4, width / 2, 3 * width / 4, width - 1}
+	for _, c := range ticks {
+		i := c - offset
+		if i < 0 || len(samples) == 0 {
+			continue
+		}
+		line[c] = '┴'
+		text := formatAgo(samples[len(samples)-1].Time.Sub(samples[i].Time))
+		start := c - len(text)/2
+		start = max(0, min(width+1-len(text), start))
+		copy(labels[start:], []rune(text))
+	}
+
+	axis := gutter + "└" + string(line)
+	return model.PanelLabelStyle.Render(axis) + "\n" +
+		model.PanelLabelStyle.Render(gutter+string(labels))
+}
+
+// formatAgo renders a sample age like "now", "-45s" or "-2m30s".
+func formatAgo(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d <= 0:
+		return "now"
+	case d < time.Minute:
+		return fmt.Sprintf("-%ds", int(d.Seconds()))
+	default:
+		m := int(d.Minutes())
+		s := int(d.Seconds()) % 60
+		if s == 0 {
+			return fmt.Sprintf("-%dm", m)
+		}
+		return fmt.Sprintf("-%dm%ds", m, s)
+	}
+}
+
+// trend returns the inline sparkline for one direction of an interface's history.
+func trend(history []data.RateSample, tx bool) string {
+	values := make([]float64, len(history))
+	for i, s := range history {
+		if tx {
+			values[i] = s.TxRate
+		} else {
+			values[i] = s.RxRate
+		}
+	}
+	return util.Sparkline(values, trendWidth)
+}
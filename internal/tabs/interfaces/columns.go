@@ -1,17 +1,28 @@
 package interfaces
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
 		table.NewColumn("name", "Name", 10).WithFiltered(true),
 		table.NewFlexColumn("addrs", "IPs", 1).WithFiltered(true),
 		table.NewColumn("mac", "MAC", 19),
 		table.NewColumn("mtu", "MTU", 7),
 		table.NewColumn("status", "Status", 8),
+		table.NewColumn("speed", "Speed", 9),
+		table.NewColumn("util", "Util", 7),
 		table.NewColumn("tx_bytes", "TX", 10),
 		table.NewColumn("rx_bytes", "RX", 10),
 		table.NewColumn("tx_rate", "TX Rate", 12),
 		table.NewColumn("rx_rate", "RX Rate", 12),
-	}
+		table.NewColumn("tx_trend", "TX Trend", 10),
+		table.NewColumn("rx_trend", "RX Trend", 10),
+		table.NewColumn("tx_pps", "TX Pkts", 10),
+		table.NewColumn("rx_pps", "RX Pkts", 10),
+		table.NewColumn("errs", "Errs", 9),
+		table.NewColumn("drops", "Drops", 9),
+	}, netns)
 }
@@ -3,11 +3,14 @@ package interfaces
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jerryluo/nettui/internal/data"
 	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/util"
 )
 
-func detailContent(rowData map[string]interface{}) string {
+func detailContent(rowData map[string]interface{}, iface *data.Interface, panelWidth int) string {
 	if rowData == nil {
 		return ""
 	}
@@ -22,25 +25,108 @@ func detailContent(rowData map[string]interface{}) string {
 		key   string
 	}{
 		{"Name", "name"},
-		{"IPs", "addrs"},
 		{"MAC", "mac"},
 		{"MTU", "mtu"},
 		{"Status", "status"},
+		{"Oper State", "oper_state"},
+		{"Carrier", "carrier"},
+		{"Carrier Chg", "carrier_changes"},
+		{"Speed", "speed"},
+		{"Duplex", "duplex"},
+		{"Utilization", "util"},
+		{"Kind", "kind"},
+		{"Relation", "relation"},
+		{"Driver", "driver"},
+		{"TX Queue", "tx_queue_len"},
 		{"TX Bytes", "tx_bytes"},
 		{"RX Bytes", "rx_bytes"},
 		{"TX Rate", "tx_rate"},
 		{"RX Rate", "rx_rate"},
 		{"TX Packets", "tx_pkts"},
 		{"RX Packets", "rx_pkts"},
+		{"TX Pkts/s", "tx_pps"},
+		{"RX Pkts/s", "rx_pps"},
+		{"Session TX", "session_tx"},
+		{"Session RX", "session_rx"},
+		{"Since", "session_start"},
+		{"Errors In", "err_in"},
+		{"Errors Out", "err_out"},
+		{"Drops In", "drop_in"},
+		{"Drops Out", "drop_out"},
+		{"FIFO In", "fifo_in"},
+		{"FIFO Out", "fifo_out"},
 		{"Flags", "flags"},
+		{"Namespace", "netns"},
 	}
 
 	for _, f := range fields {
 		val := fmt.Sprintf("%v", rowData[f.key])
+		if f.key == "netns" && val == "" {
+			continue
+		}
 		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-12s", f.label)))
 		b.WriteString(model.PanelValueStyle.Render(val))
 		b.WriteString("\n")
 	}
 
+	if iface != nil && len(iface.Addresses) > 0 {
+		b.WriteString("\n")
+		b.WriteString(addressTable(iface.Addresses, panelWidth))
+	}
+
+	return b.String()
+}
+
+// addressTable lists an interface's addresses, numbered so they can be
+// picked with the g-chord to jump to the sockets bound to them.
+func addressTable(addrs []data.Address, panelWidth int) string {
+	var b strings.Builder
+	b.WriteString(model.PanelHeaderStyle.Render("Addresses"))
+	b.WriteString("\n")
+
+	addrWidth := len("Address")
+	for _, a := range addrs {
+		addrWidth = max(addrWidth, len(a.CIDR()))
+	}
+	row := func(n, addr, scope, valid, pref, flags string) string {
+		line := fmt.Sprintf("%-2s %-*s %-7s %-8s %-8s %s", n, addrWidth, addr, scope, valid, pref, flags)
+		// Panel border and padding take 4 columns.
+		if w := panelWidth - 4; w > 0 && len(line) > w {
+			line = line[:w-1] + "…"
+		}
+		return line
+	}
+
+	b.WriteString(model.PanelLabelStyle.Render(row("#", "Address", "Scope", "Valid", "Pref", "Flags")))
+	b.WriteString("\n")
+	for i, a := range addrs {
+		var extra []string
+		if a.Broadcast != "" {
+			extra = append(extra, "brd "+a.Broadcast)
+		}
+		extra = append(extra, a.Flags...)
+		b.WriteString(model.PanelValueStyle.Render(row(
+			fmt.Sprintf("%d", i+1),
+			a.CIDR(),
+			orDash(a.Scope),
+			formatLifetime(a.ValidLifetime),
+			formatLifetime(a.PreferredLifetime),
+			strings.Join(extra, " "),
+		)))
+		b.WriteString("\n")
+	}
 	return b.String()
 }
+
+// formatLifetime renders an address lifetime like "forever", "23h59m" or "45s".
+// Zero means the platform did not report one.
+func formatLifetime(d time.Duration) string {
+	switch {
+	case d == data.Forever:
+		return "forever"
+	case d <= 0:
+		return "-"
+	default:
+		return util.FormatDuration(d)
+	}
+}
@@ -23,6 +23,18 @@ type Model struct {
 	navKey string
 	navVal string
 	sort   tabs.SortState
+
+	chart    bool // full-screen chart for the selected interface
+	bits     bool // show rates in bits/sec instead of bytes/sec
+	topology bool // tree of bridges, bonds, VLANs and peers
+	netns    bool // namespace column shown
+
+	// autoRefresh is set when data refreshes periodically; without it the
+	// history only gains a sample when r is pressed.
+	autoRefresh bool
+
+	rows       []table.Row // rows in display order
+	panelWidth int
 }
 
 var sortEntries = []tabs.SortEntry{
@@ -31,6 +43,14 @@ var sortEntries = []tabs.SortEntry{
 	{Key: "s", ColKey: "status", SortKey: "status", Label: "Status"},
 	{Key: "t", ColKey: "tx_bytes", SortKey: "raw_tx", Label: "TX"},
 	{Key: "r", ColKey: "rx_bytes", SortKey: "raw_rx", Label: "RX"},
+	{Key: "T", ColKey: "tx_rate", SortKey: "raw_tx_rate", Label: "TX Rate"},
+	{Key: "R", ColKey: "rx_rate", SortKey: "raw_rx_rate", Label: "RX Rate"},
+	{Key: "p", ColKey: "tx_pps", SortKey: "raw_tx_pps", Label: "TX Pkts"},
+	{Key: "P", ColKey: "rx_pps", SortKey: "raw_rx_pps", Label: "RX Pkts"},
+	{Key: "S", ColKey: "speed", SortKey: "raw_speed", Label: "Speed"},
+	{Key: "u", ColKey: "util", SortKey: "raw_util", Label: "Util"},
+	{Key: "e", ColKey: "errs", SortKey: "raw_errs", Label: "Errs"},
+	{Key: "d", ColKey: "drops", SortKey: "raw_drops", Label: "Drops"},
 }
 
 // New creates a new Interfaces tab model.
@@ -38,7 +58,7 @@ func New() *Model {
 	m := &Model{
 		tabID: model.TabInterfaces,
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -53,32 +73,134 @@ func (m *Model) buildRows() []table.Row {
 	if m.store == nil {
 		return nil
 	}
+	kindByKey := make(map[string]string, len(m.store.Interfaces))
+	for _, iface := range m.store.Interfaces {
+		kindByKey[iface.Key()] = iface.Kind
+	}
 	rows := make([]table.Row, 0, len(m.store.Interfaces))
 	for _, iface := range m.store.Interfaces {
 		status := "down"
 		if iface.Up {
 			status = "up"
 		}
-		rows = append(rows, table.NewRow(table.RowData{
-			"name":    iface.Name,
-			"addrs":   strings.Join(iface.Addrs, ", "),
-			"mac":     iface.HWAddr,
-			"mtu":     fmt.Sprintf("%d", iface.MTU),
-			"status":  status,
-			"tx_bytes": util.FormatBytes(iface.BytesSent),
-			"rx_bytes": util.FormatBytes(iface.BytesRecv),
-			"tx_rate": util.FormatRate(iface.TxRate),
-			"rx_rate": util.FormatRate(iface.RxRate),
-			"tx_pkts": fmt.Sprintf("%d", iface.PacketSent),
-			"rx_pkts": fmt.Sprintf("%d", iface.PacketRecv),
-			"flags":   iface.Flags.String(),
-			"raw_tx":  iface.BytesSent,
-			"raw_rx":  iface.BytesRecv,
-		}))
+		formatRate := util.FormatRate
+		if m.bits {
+			formatRate = util.FormatBitRate
+		}
+		row := table.NewRow(table.RowData{
+			"netns":           iface.Namespace,
+			"key":             iface.Key(),
+			"name":            iface.Name,
+			"tree":            iface.Name,
+			"relation":        relation(iface, kindByKey),
+			"addrs":           strings.Join(iface.Addrs, ", "),
+			"mac":             iface.HWAddr,
+			"mtu":             fmt.Sprintf("%d", iface.MTU),
+			"status":          status,
+			"oper_state":      orDash(iface.OperState),
+			"carrier":         formatCarrier(iface),
+			"carrier_changes": fmt.Sprintf("%d", iface.CarrierChanges),
+			"speed":           formatSpeed(iface.Speed),
+			"duplex":          orDash(iface.Duplex),
+			"util":            formatUtilization(iface.Utilization()),
+			"kind":            orDash(iface.Kind),
+			"driver":          orDash(iface.Driver),
+			"tx_queue_len":    fmt.Sprintf("%d", iface.TxQueueLen),
+			"tx_bytes":        util.FormatBytes(iface.BytesSent),
+			"rx_bytes":        util.FormatBytes(iface.BytesRecv),
+			"tx_rate":         formatRate(iface.TxRate),
+			"rx_rate":         formatRate(iface.RxRate),
+			"tx_trend":        trend(iface.History, true),
+			"rx_trend":        trend(iface.History, false),
+			"tx_pkts":         fmt.Sprintf("%d", iface.PacketSent),
+			"rx_pkts":         fmt.Sprintf("%d", iface.PacketRecv),
+			"tx_pps":          util.FormatPacketRate(iface.TxPktRate),
+			"rx_pps":          util.FormatPacketRate(iface.RxPktRate),
+			"session_tx":      formatSession(iface.SessionTxBytes, iface.SessionTxPkts),
+			"session_rx":      formatSession(iface.SessionRxBytes, iface.SessionRxPkts),
+			"session_start":   iface.SessionStart.Format("15:04:05"),
+			"errs":            fmt.Sprintf("%d/%d", iface.ErrIn, iface.ErrOut),
+			"drops":           fmt.Sprintf("%d/%d", iface.DropIn, iface.DropOut),
+			"err_in":          formatCounter(iface.ErrIn, iface.ErrInRate),
+			"err_out":         formatCounter(iface.ErrOut, iface.ErrOutRate),
+			"drop_in":         formatCounter(iface.DropIn, iface.DropInRate),
+			"drop_out":        formatCounter(iface.DropOut, iface.DropOutRate),
+			"fifo_in":         formatCounter(iface.FifoIn, iface.FifoInRate),
+			"fifo_out":        formatCounter(iface.FifoOut, iface.FifoOutRate),
+			"flags":           iface.Flags.String(),
+			"raw_tx":          iface.BytesSent,
+			"raw_rx":          iface.BytesRecv,
+			"raw_tx_rate":     iface.TxRate,
+			"raw_rx_rate":     iface.RxRate,
+			"raw_tx_pps":      iface.TxPktRate,
+			"raw_rx_pps":      iface.RxPktRate,
+			"raw_speed":       iface.Speed,
+			"raw_util":        iface.Utilization(),
+			"raw_errs":        iface.ErrIn + iface.ErrOut,
+			"raw_drops":       iface.DropIn + iface.DropOut,
+		})
+		// Highlight interfaces whose error or drop counters are climbing.
+		if iface.Degrading() {
+			row = row.WithStyle(model.DegradedRowStyle)
+		}
+		rows = append(rows, row)
 	}
 	return rows
 }
 
+// formatCounter renders a cumulative counter with its current per-second rate.
+func formatCounter(total uint64, rate float64) string {
+	if rate <= 0 {
+		return fmt.Sprintf("%d", total)
+	}
+	return fmt.Sprintf("%d (+%.1f/s)", total, rate)
+}
+
+// formatSpeed renders a link speed given in Mbit/s.
+func formatSpeed(mbps int) string {
+	switch {
+	case mbps <= 0:
+		return "--"
+	case mbps >= 1000 && mbps%1000 == 0:
+		return fmt.Sprintf("%d Gb/s", mbps/1000)
+	case mbps >= 1000:
+		return fmt.Sprintf("%.1f Gb/s", float64(mbps)/1000)
+	default:
+		return fmt.Sprintf("%d Mb/s", mbps)
+	}
+}
+
+// formatUtilization renders a link utilization percentage, or "--" if the
+// link speed is unknown.
+func formatUtilization(pct float64) string {
+	if pct < 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%.1f%%", pct)
+}
+
+func formatCarrier(iface data.Interface) string {
+	if iface.OperState == "" {
+		return "--"
+	}
+	if iface.Carrier {
+		return "yes"
+	}
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "--"
+	}
+	return s
+}
+
+// formatSession renders a session byte/packet total pair.
+func formatSession(bytes, pkts uint64) string {
+	return fmt.Sprintf("%s / %d pkts", util.FormatBytes(bytes), pkts)
+}
+
 // YankHint implements Tab.
 func (m *Model) YankHint() string {
 	return "y→  n:Name  a:Addrs  m:MAC  y:All"
@@ -120,22 +242,158 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model.
 func (m *Model) View() string {
+	if m.chart {
+		return renderChart(m.selectedInterface(), m.width, m.height, m.bits, m.autoRefresh)
+	}
 	return m.table.View()
 }
 
-// SetData implements Tab.
-func (m *Model) SetData(store *data.Store) {
-	m.store = store
+func (m *Model) selectedInterface() *data.Interface {
+	row := m.table.HighlightedRow()
+	if row.Data == nil || m.store == nil {
+		return nil
+	}
+	key, _ := row.Data["key"].(string)
+	return m.store.IfaceByName[key]
+}
+
+// SelectedInterface returns the interface of the highlighted row.
+func (m *Model) SelectedInterface() (data.Interface, bool) {
+	iface := m.selectedInterface()
+	if iface == nil {
+		return data.Interface{}, false
+	}
+	return *iface, true
+}
+
+// SetAutoRefresh records whether data refreshes periodically, which the
+// chart notes when it does not.
+func (m *Model) SetAutoRefresh(on bool) {
+	m.autoRefresh = on
+}
+
+// ToggleChart switches between the table and the full-screen chart of the
+// selected interface.
+func (m *Model) ToggleChart() {
+	m.chart = !m.chart
+}
+
+// CloseChart leaves chart mode. It returns false if the chart was not open.
+func (m *Model) CloseChart() bool {
+	if !m.chart {
+		return false
+	}
+	m.chart = false
+	return true
+}
+
+// ToggleUnits switches rate display between bytes/sec and bits/sec.
+func (m *Model) ToggleUnits() {
+	m.bits = !m.bits
+	m.refreshRows()
+}
+
+// ToggleTopology switches between the flat interface list and the tree of
+// bridges, bonds, VLANs and peers.
+func (m *Model) ToggleTopology() {
+	m.topology = !m.topology
+	m.applyColumns()
+	m.refreshRows()
+}
+
+func (m *Model) applyColumns() {
+	if m.topology {
+		m.table = m.table.WithColumns(topologyColumns(m.netns))
+	} else {
+		m.table = m.table.WithColumns(columns(m.netns))
+	}
+}
+
+// TopologyLabel returns a status bar label while the topology view is shown.
+func (m *Model) TopologyLabel() string {
+	if !m.topology {
+		return ""
+	}
+	return "[topology]"
+}
+
+// GoToRelated highlights the interface related to the selected one: its
+// master ("m"), its lower device ("p"), or its veth peer ("e"), which may
+// sit in another namespace when all namespaces are shown. It returns false
+// if there is no such interface in the current view.
+func (m *Model) GoToRelated(k string) bool {
+	iface := m.selectedInterface()
+	if iface == nil {
+		return false
+	}
+	var target string
+	switch k {
+	case "m":
+		target = sameNamespace(iface, iface.Master)
+	case "p":
+		target = sameNamespace(iface, iface.Parent)
+	case "e":
+		target = sameNamespace(iface, iface.Peer)
+		if iface.PeerNetNS != "" {
+			target = m.peerInNamespace(iface)
+		}
+	}
+	if target == "" {
+		return false
+	}
+	for i, r := range m.rows {
+		if key, _ := r.Data["key"].(string); key == target {
+			m.table = m.table.WithHighlightedRow(i)
+			return true
+		}
+	}
+	return false
+}
+
+// sameNamespace returns the key of the interface called name in iface's
+// namespace, or "" if name is empty.
+func sameNamespace(iface *data.Interface, name string) string {
+	if name == "" {
+		return ""
+	}
+	return data.Interface{Name: name, Namespace: iface.Namespace}.Key()
+}
+
+// peerInNamespace returns the key of the veth peer that lives in another
+// namespace, found by its index there.
+func (m *Model) peerInNamespace(iface *data.Interface) string {
+	for _, other := range m.store.Interfaces {
+		if other.Namespace == iface.PeerNetNS && other.Index == iface.PeerIndex {
+			return other.Key()
+		}
+	}
+	return ""
+}
+
+func (m *Model) refreshRows() {
 	rows := m.buildRows()
 	if m.navKey != "" {
 		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
 	}
-	if m.sort.Active() {
+	if m.topology {
+		rows = topologyOrder(rows, m.store.Interfaces)
+	} else if m.sort.Active() {
 		m.sort.SortRows(rows)
 	}
+	m.rows = rows
 	m.table = m.table.WithRows(rows)
 }
 
+// SetData implements Tab.
+func (m *Model) SetData(store *data.Store) {
+	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.applyColumns()
+	}
+	m.refreshRows()
+}
+
 // SetSize implements Tab.
 func (m *Model) SetSize(width, height int) {
 	m.width = width
@@ -165,7 +423,7 @@ func (m *Model) DetailContent() string {
 	if row.Data == nil {
 		return ""
 	}
-	return detailContent(row.Data)
+	return detailContent(row.Data, m.selectedInterface(), m.panelWidth)
 }
 
 // CrossRef implements Tab.
@@ -185,6 +443,32 @@ func (m *Model) CrossRef() *model.CrossRefMsg {
 	}
 }
 
+// AddressCrossRef returns a cross-reference to the sockets bound to the
+// selected interface's n-th address (1-based), or to any of its addresses
+// when n is 0.
+func (m *Model) AddressCrossRef(n int) *model.CrossRefMsg {
+	iface := m.selectedInterface()
+	if iface == nil || n > len(iface.Addresses) {
+		return nil
+	}
+	var ips []string
+	if n > 0 {
+		ips = []string{iface.Addresses[n-1].IP}
+	} else {
+		for _, a := range iface.Addresses {
+			ips = append(ips, a.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil
+	}
+	return &model.CrossRefMsg{
+		TargetTab: model.TabSockets,
+		FilterKey: "laddr",
+		FilterVal: strings.Join(ips, ","),
+	}
+}
+
 // NavigateTo implements Tab.
 func (m *Model) NavigateTo(key, val string) {
 	if key != "name" {
@@ -192,12 +476,8 @@ func (m *Model) NavigateTo(key, val string) {
 	}
 	m.navKey = key
 	m.navVal = val
-	rows := m.buildRows()
-	rows = tabs.FilterNavRows(rows, key, val)
-	if m.sort.Active() {
-		m.sort.SortRows(rows)
-	}
-	m.table = m.table.WithRows(rows).WithHighlightedRow(0)
+	m.refreshRows()
+	m.table = m.table.WithHighlightedRow(0)
 }
 
 // SortHint implements Tab.
@@ -210,21 +490,21 @@ func (m *Model) ApplySort(key string) {
 	if !m.sort.Apply(sortEntries, key) {
 		return
 	}
-	rows := m.buildRows()
-	if m.navKey != "" {
-		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
-	}
-	m.sort.SortRows(rows)
-	m.table = m.table.WithRows(rows)
+	m.refreshRows()
 }
 
 // SortLabel implements Tab.
 func (m *Model) SortLabel() string {
+	if m.topology {
+		return ""
+	}
 	return m.sort.Label()
 }
 
 // SetPanelWidth implements Tab.
-func (m *Model) SetPanelWidth(width int) {}
+func (m *Model) SetPanelWidth(width int) {
+	m.panelWidth = width
+}
 
 // IsFiltering implements Tab.
 func (m *Model) IsFiltering() bool {
@@ -245,11 +525,7 @@ func (m *Model) ClearFilter() {
 	if m.navKey != "" {
 		m.navKey = ""
 		m.navVal = ""
-		rows := m.buildRows()
-		if m.sort.Active() {
-			m.sort.SortRows(rows)
-		}
-		m.table = m.table.WithRows(rows)
+		m.refreshRows()
 	}
 }
 
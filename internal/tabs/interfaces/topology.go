@@ -0,0 +1,129 @@
+package interfaces
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
+
+func topologyColumns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
+		table.NewFlexColumn("tree", "Interface", 2).WithFiltered(true),
+		table.NewColumn("kind", "Kind", 10).WithFiltered(true),
+		table.NewFlexColumn("relation", "Relation", 2).WithFiltered(true),
+		table.NewColumn("status", "Status", 8),
+		table.NewColumn("mtu", "MTU", 7),
+		table.NewColumn("tx_rate", "TX Rate", 12),
+		table.NewColumn("rx_rate", "RX Rate", 12),
+	}, netns)
+}
+
+// upperOf returns the key of the interface an interface hangs under in the
+// tree: its bridge or bond master, or else the lower device of a
+// VLAN/macvlan.
+func upperOf(iface data.Interface) string {
+	if iface.Master != "" {
+		return sameNamespace(&iface, iface.Master)
+	}
+	return sameNamespace(&iface, iface.Parent)
+}
+
+// topologyOrder arranges rows as an indented tree. Ports sit under their
+// bridge or bond, VLANs and macvlans under their lower device. The tree
+// prefix is stored under "tree" for display; "name" is left untouched so
+// navigation and yanking keep working on the bare name. Rows and tree
+// nodes are keyed by Interface.Key().
+func topologyOrder(rows []table.Row, ifaces []data.Interface) []table.Row {
+	rowByKey := make(map[string]table.Row, len(rows))
+	for _, r := range rows {
+		key, _ := r.Data["key"].(string)
+		rowByKey[key] = r
+	}
+
+	children := make(map[string][]string)
+	var roots []string
+	for _, iface := range ifaces {
+		key := iface.Key()
+		if _, ok := rowByKey[key]; !ok {
+			continue
+		}
+		upper := upperOf(iface)
+		if _, ok := rowByKey[upper]; ok && upper != key {
+			children[upper] = append(children[upper], key)
+		} else {
+			roots = append(roots, key)
+		}
+	}
+
+	ordered := make([]table.Row, 0, len(rows))
+	visited := make(map[string]bool, len(rows))
+	var walk func(key, indent, branch string)
+	walk = func(key, indent, branch string) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		r := rowByKey[key]
+		name, _ := r.Data["name"].(string)
+		r.Data["tree"] = indent + branch + name
+		ordered = append(ordered, r)
+
+		next := indent
+		switch branch {
+		case "├─ ":
+			next += "│  "
+		case "└─ ":
+			next += "   "
+		}
+		kids := children[key]
+		for i, kid := range kids {
+			b := "├─ "
+			if i == len(kids)-1 {
+				b = "└─ "
+			}
+			walk(kid, next, b)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+	// Anything left over is part of a master/parent cycle; list it flat.
+	for _, iface := range ifaces {
+		if key := iface.Key(); !visited[key] {
+			if _, ok := rowByKey[key]; ok {
+				walk(key, "", "")
+			}
+		}
+	}
+	return ordered
+}
+
+// relation describes how an interface connects to the others.
+func relation(iface data.Interface, kindByKey map[string]string) string {
+	var parts []string
+	if iface.Master != "" {
+		role := "port of"
+		if kindByKey[sameNamespace(&iface, iface.Master)] == "bond" {
+			role = "slave of"
+		}
+		parts = append(parts, role+" "+iface.Master)
+	}
+	switch {
+	case iface.VLANID > 0 && iface.Parent != "":
+		parts = append(parts, fmt.Sprintf("vlan %d on %s", iface.VLANID, iface.Parent))
+	case iface.VLANID > 0:
+		parts = append(parts, fmt.Sprintf("vlan %d", iface.VLANID))
+	case iface.Parent != "":
+		parts = append(parts, "on "+iface.Parent)
+	}
+	switch {
+	case iface.Peer != "":
+		parts = append(parts, "peer "+iface.Peer)
+	case iface.PeerNetNS != "":
+		parts = append(parts, fmt.Sprintf("peer #%d in %s", iface.PeerIndex, iface.PeerNetNS))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,21 @@
+package listeners
+
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
+
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
+		table.NewColumn("proto", "Proto", 6).WithFiltered(true),
+		table.NewColumn("port", "Port", 7).WithFiltered(true),
+		table.NewColumn("exposure", "Exposure", 15).WithFiltered(true),
+		table.NewColumn("verdict", "Firewall", 10).WithFiltered(true),
+		table.NewColumn("fw_rule", "Rule", 12),
+		table.NewFlexColumn("addrs", "Bind Addresses", 2).WithFiltered(true),
+		table.NewColumn("pids", "PIDs", 12).WithFiltered(true),
+		table.NewColumn("process", "Process", 18).WithFiltered(true),
+		table.NewFlexColumn("unit", "Unit", 1).WithFiltered(true),
+		table.NewColumn("container", "Container", 20).WithFiltered(true),
+	}, netns)
+}
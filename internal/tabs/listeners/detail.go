@@ -0,0 +1,50 @@
+package listeners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+func detailContent(rowData map[string]interface{}) string {
+	if rowData == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(model.PanelHeaderStyle.Render("Listener Details"))
+	b.WriteString("\n\n")
+
+	fields := []struct {
+		label string
+		key   string
+	}{
+		{"Protocol", "proto"},
+		{"Port", "port"},
+		{"Exposure", "raw_exposure_name"},
+		{"Firewall", "raw_verdict_name"},
+		{"Deciding Rule", "fw_rule"},
+		{"Bind", "addrs"},
+		{"Interfaces", "ifaces"},
+		{"PIDs", "pids"},
+		{"Process", "process"},
+		{"Unit", "unit"},
+		{"Container", "container"},
+		{"Sockets", "sockets"},
+		{"Namespace", "netns"},
+	}
+
+	for _, f := range fields {
+		val := fmt.Sprintf("%v", rowData[f.key])
+		if val == "" || val == "<nil>" {
+			continue
+		}
+		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-14s", f.label)))
+		b.WriteString(model.PanelValueStyle.Render(val))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,323 @@
+package listeners
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/tabs"
+	"github.com/jerryluo/nettui/internal/util"
+)
+
+// Model is the Listeners tab model.
+type Model struct {
+	table  table.Model
+	store  *data.Store
+	width  int
+	height int
+	tabID  model.TabID
+	sort   tabs.SortState
+	netns  bool
+}
+
+var sortEntries = []tabs.SortEntry{
+	{Key: "p", ColKey: "proto", SortKey: "proto", Label: "Proto"},
+	{Key: "o", ColKey: "port", SortKey: "raw_port", Label: "Port"},
+	{Key: "e", ColKey: "exposure", SortKey: "raw_exposure", Label: "Exposure"},
+	{Key: "f", ColKey: "verdict", SortKey: "raw_verdict", Label: "Firewall"},
+	{Key: "n", ColKey: "process", SortKey: "process", Label: "Process"},
+	{Key: "t", ColKey: "unit", SortKey: "unit", Label: "Unit"},
+}
+
+var (
+	exposedStyle  = lipgloss.NewStyle().Foreground(model.ErrorColor).Bold(true)
+	specificStyle = lipgloss.NewStyle().Foreground(model.AccentColor)
+	blockedStyle  = lipgloss.NewStyle().Foreground(model.SuccessColor)
+	unknownStyle  = lipgloss.NewStyle().Foreground(model.MutedColor)
+)
+
+// New creates a new Listeners tab model.
+func New() *Model {
+	m := &Model{
+		tabID: model.TabListeners,
+	}
+	m.table = table.New(columns(false)).
+		WithBaseStyle(lipgloss.NewStyle()).
+		Focused(true).
+		WithPageSize(20).
+		Filtered(true).
+		HeaderStyle(model.TableHeaderStyle).
+		HighlightStyle(model.SelectedRowStyle).
+		WithPaginationWrapping(false)
+	return m
+}
+
+func (m *Model) buildRows() []table.Row {
+	if m.store == nil {
+		return nil
+	}
+	rows := make([]table.Row, 0, len(m.store.Listeners))
+	for _, l := range m.store.Listeners {
+		pids := make([]string, len(l.PIDs))
+		for i, pid := range l.PIDs {
+			pids[i] = fmt.Sprintf("%d", pid)
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"netns":             l.Namespace,
+			"proto":             l.Proto,
+			"port":              util.FormatPort(l.Port),
+			"exposure":          exposureCell(l),
+			"verdict":           verdictCell(l),
+			"fw_rule":           l.VerdictRule,
+			"addrs":             strings.Join(l.Addrs, ", "),
+			"ifaces":            strings.Join(l.Ifaces, ", "),
+			"pids":              strings.Join(pids, ","),
+			"process":           util.FormatProcess(l.Process),
+			"unit":              l.Unit,
+			"container":         l.Container,
+			"sockets":           fmt.Sprintf("%d", len(l.Sockets)),
+			"raw_port":          l.Port,
+			"raw_exposure":      int(l.Exposure),
+			"raw_exposure_name": exposureText(l),
+			"raw_verdict":       int(l.Verdict),
+			"raw_verdict_name":  verdictText(l),
+		}))
+	}
+	return rows
+}
+
+// exposureText describes a listener's exposure, naming the interfaces of
+// specific bind addresses when they are known.
+func exposureText(l data.Listener) string {
+	if l.Exposure == data.ExposureSpecific && len(l.Ifaces) > 0 {
+		return "if: " + strings.Join(l.Ifaces, ",")
+	}
+	return l.Exposure.String()
+}
+
+// exposureCell renders the exposure, highlighting services reachable on
+// every interface.
+func exposureCell(l data.Listener) table.StyledCell {
+	switch l.Exposure {
+	case data.ExposureAll:
+		return table.NewStyledCell(exposureText(l), exposedStyle)
+	case data.ExposureSpecific:
+		return table.NewStyledCell(exposureText(l), specificStyle)
+	}
+	return table.NewStyledCell(exposureText(l), lipgloss.NewStyle())
+}
+
+// verdictText describes the firewall verdict, saying when it comes from
+// the default pass policy rather than a rule.
+func verdictText(l data.Listener) string {
+	if l.Verdict == data.VerdictAllowed && l.VerdictRule == "" {
+		return "allowed (no matching rule)"
+	}
+	return l.Verdict.String()
+}
+
+// verdictCell renders the firewall verdict, highlighting services that
+// listen on every interface with nothing filtering them.
+func verdictCell(l data.Listener) table.StyledCell {
+	switch {
+	case l.Verdict == data.VerdictAllowed && l.Exposure == data.ExposureAll:
+		return table.NewStyledCell(l.Verdict.String(), exposedStyle)
+	case l.Verdict == data.VerdictBlocked:
+		return table.NewStyledCell(l.Verdict.String(), blockedStyle)
+	case l.Verdict == data.VerdictUnknown:
+		return table.NewStyledCell(l.Verdict.String(), unknownStyle)
+	}
+	return table.NewStyledCell(l.Verdict.String(), lipgloss.NewStyle())
+}
+
+// Init implements tea.Model.
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.table, cmd = tabs.ClampedUpdate(m.table, msg)
+	return m, cmd
+}
+
+// View implements tea.Model.
+func (m *Model) View() string {
+	return m.table.View()
+}
+
+// SetData implements Tab.
+func (m *Model) SetData(store *data.Store) {
+	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
+	rows := m.buildRows()
+	if m.sort.Active() {
+		m.sort.SortRows(rows)
+	}
+	m.table = m.table.WithRows(rows)
+}
+
+// SetSize implements Tab.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table = m.table.WithPageSize(height - 6).WithTargetWidth(width)
+}
+
+// TabID implements Tab.
+func (m *Model) TabID() model.TabID {
+	return m.tabID
+}
+
+// SelectedRow implements Tab.
+func (m *Model) SelectedRow() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	proto, _ := row.Data["proto"].(string)
+	port, _ := row.Data["port"].(string)
+	addrs, _ := row.Data["addrs"].(string)
+	process, _ := row.Data["process"].(string)
+	exposure, _ := row.Data["raw_exposure_name"].(string)
+	return fmt.Sprintf("%s/%s on %s (%s) by %s", proto, port, addrs, exposure, process)
+}
+
+// DetailContent implements Tab.
+func (m *Model) DetailContent() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	return detailContent(row.Data)
+}
+
+// CrossRef implements Tab.
+func (m *Model) CrossRef() *model.CrossRefMsg {
+	return m.CrossRefTo(model.TabSockets)
+}
+
+// CrossRefTo returns a CrossRefMsg targeting the given tab: the Sockets
+// tab filtered to the selected service's sockets, the Processes tab
+// filtered to its first owning process, or the Firewall tab filtered to the
+// rule deciding its verdict.
+func (m *Model) CrossRefTo(target model.TabID) *model.CrossRefMsg {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return nil
+	}
+	switch target {
+	case model.TabSockets:
+		proto, _ := row.Data["proto"].(string)
+		port, _ := row.Data["raw_port"].(uint32)
+		return &model.CrossRefMsg{
+			TargetTab: model.TabSockets,
+			FilterKey: "listener",
+			FilterVal: fmt.Sprintf("%s/%d", proto, port),
+		}
+	case model.TabProcesses:
+		pids, _ := row.Data["pids"].(string)
+		pid, _, _ := strings.Cut(pids, ",")
+		if pid == "" {
+			return nil
+		}
+		return &model.CrossRefMsg{
+			TargetTab: model.TabProcesses,
+			FilterKey: "pid",
+			FilterVal: pid,
+		}
+	case model.TabFirewall:
+		rule, _ := row.Data["fw_rule"].(string)
+		if rule == "" {
+			return nil
+		}
+		return &model.CrossRefMsg{
+			TargetTab: model.TabFirewall,
+			FilterKey: "rule",
+			FilterVal: rule,
+		}
+	}
+	return nil
+}
+
+// NavigateTo implements Tab.
+func (m *Model) NavigateTo(key, val string) {}
+
+// NavFilterLabel implements Tab.
+func (m *Model) NavFilterLabel() string { return "" }
+
+// SortHint implements Tab.
+func (m *Model) SortHint() string {
+	return tabs.Hint(sortEntries)
+}
+
+// ApplySort implements Tab.
+func (m *Model) ApplySort(key string) {
+	if !m.sort.Apply(sortEntries, key) {
+		return
+	}
+	rows := m.buildRows()
+	m.sort.SortRows(rows)
+	m.table = m.table.WithRows(rows)
+}
+
+// SortLabel implements Tab.
+func (m *Model) SortLabel() string {
+	return m.sort.Label()
+}
+
+// SetPanelWidth implements Tab.
+func (m *Model) SetPanelWidth(width int) {}
+
+// YankHint implements Tab.
+func (m *Model) YankHint() string {
+	return "y→  o:Port  a:Addresses  p:PIDs  n:Process  y:All"
+}
+
+// YankField implements Tab.
+func (m *Model) YankField(key string) string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	switch key {
+	case "o":
+		v, _ := row.Data["port"].(string)
+		return v
+	case "a":
+		v, _ := row.Data["addrs"].(string)
+		return v
+	case "p":
+		v, _ := row.Data["pids"].(string)
+		return v
+	case "n":
+		v, _ := row.Data["process"].(string)
+		return v
+	case "y":
+		return m.SelectedRow()
+	}
+	return ""
+}
+
+// IsFiltering implements Tab.
+func (m *Model) IsFiltering() bool {
+	return m.table.GetIsFilterInputFocused()
+}
+
+// HasActiveFilter implements Tab.
+func (m *Model) HasActiveFilter() bool {
+	return m.table.GetCurrentFilter() != ""
+}
+
+// ClearFilter implements Tab.
+func (m *Model) ClearFilter() {
+	m.table = m.table.WithFilterInputValue("")
+}
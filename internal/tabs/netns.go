@@ -0,0 +1,15 @@
+package tabs
+
+import "github.com/evertras/bubble-table/table"
+
+// WithNamespaceColumn prepends a network namespace column to cols when the
+// store holds data from namespaces other than nettui's own. Rows carry the
+// namespace under "netns".
+func WithNamespaceColumn(cols []table.Column, on bool) []table.Column {
+	if !on {
+		return cols
+	}
+	return append([]table.Column{
+		table.NewColumn("netns", "NetNS", 16).WithFiltered(true),
+	}, cols...)
+}
@@ -1,14 +1,33 @@
 package processes
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns, resources bool) []table.Column {
+	return tabs.WithNamespaceColumn(withResourceColumns([]table.Column{
 		table.NewColumn("pid", "PID", 8).WithFiltered(true),
 		table.NewFlexColumn("name", "Name", 1).WithFiltered(true),
 		table.NewFlexColumn("command", "Command", 2).WithFiltered(true),
 		table.NewFlexColumn("user", "User", 1),
+		table.NewColumn("unit", "Unit", 22).WithFiltered(true),
+		table.NewColumn("container", "Container", 20).WithFiltered(true),
 		table.NewColumn("conns", "#Sockets", 10),
 		table.NewColumn("unix_socks", "#Unix", 8),
+	}, resources), netns)
+}
+
+// withResourceColumns appends the optional resource usage columns.
+func withResourceColumns(cols []table.Column, on bool) []table.Column {
+	if !on {
+		return cols
 	}
+	return append(cols,
+		table.NewColumn("cpu", "CPU%", 7),
+		table.NewColumn("rss", "RSS", 10),
+		table.NewColumn("threads", "Thr", 5),
+		table.NewColumn("fds", "FDs", 16),
+		table.NewColumn("uptime", "Uptime", 9),
+	)
 }
@@ -3,8 +3,11 @@ package processes
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jerryluo/nettui/internal/data"
 	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/util"
 )
 
 const labelWidth = 14
@@ -23,13 +26,31 @@ func detailContent(rowData map[string]interface{}, panelWidth int) string {
 		label string
 		key   string
 		wrap  bool
+		omit  bool // skip when empty
 	}{
-		{"PID", "pid", false},
-		{"Name", "name", false},
-		{"Command", "command", true},
-		{"User", "user", false},
-		{"Connections", "conns", false},
-		{"Unix Sockets", "unix_socks", false},
+		{"PID", "pid", false, false},
+		{"Parent", "parent", false, false},
+		{"Name", "name", false, false},
+		{"Command", "command", true, false},
+		{"User", "user", false, false},
+		{"Unit", "raw_unit", false, true},
+		{"Slice", "slice", false, true},
+		{"Cgroup", "cgroup", true, true},
+		{"Container", "raw_container", false, true},
+		{"Runtime", "container_runtime", false, true},
+		{"Container ID", "container_id", false, true},
+		{"Pod UID", "pod_uid", false, true},
+		{"Executable", "exe", true, true},
+		{"Cwd", "cwd", true, true},
+		{"Started", "started", false, true},
+		{"Uptime", "uptime", false, true},
+		{"CPU%", "cpu", false, false},
+		{"RSS", "rss", false, true},
+		{"Threads", "threads", false, true},
+		{"FDs", "fds", false, true},
+		{"Connections", "conns", false, false},
+		{"Unix Sockets", "unix_socks", false, false},
+		{"Namespace", "netns", false, true},
 	}
 
 	// Available width for values: panel minus border/padding (4) minus label.
@@ -40,6 +61,9 @@ func detailContent(rowData map[string]interface{}, panelWidth int) string {
 
 	for _, f := range fields {
 		val := fmt.Sprintf("%v", rowData[f.key])
+		if f.omit && val == "" {
+			continue
+		}
 		label := model.PanelLabelStyle.Render(fmt.Sprintf("%-*s", labelWidth, f.label))
 
 		if f.wrap && len(val) > valWidth {
@@ -85,3 +109,47 @@ func wrapText(s string, width int) []string {
 	}
 	return lines
 }
+
+// formatRSS renders resident memory, or "" if unknown.
+func formatRSS(rss uint64) string {
+	if rss == 0 {
+		return ""
+	}
+	return util.FormatBytes(rss)
+}
+
+// formatCount renders a count, or "" if unknown.
+func formatCount(n int32) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// formatFDs renders open file descriptors against the soft limit, like
+// "812/1024 (79%)".
+func formatFDs(p data.Process) string {
+	switch {
+	case p.NumFDs <= 0:
+		return ""
+	case p.FDUsage() < 0:
+		return fmt.Sprintf("%d", p.NumFDs)
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", p.NumFDs, p.FDLimit, p.FDUsage()*100)
+}
+
+// formatUptime renders how long a process has run, or "" if unknown.
+func formatUptime(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return util.FormatDuration(d)
+}
+
+// formatStarted renders a process start time, or "" if unknown.
+func formatStarted(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -2,6 +2,8 @@ package processes
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,24 +24,59 @@ type Model struct {
 	navKey string
 	navVal string
 	sort       tabs.SortState
+	group      tabs.GroupState
+	netns      bool
 	panelWidth int
+
+	tree      bool           // show the parent/child hierarchy
+	collapsed map[int32]bool // PIDs whose subtrees are hidden in the tree
+	resources bool           // show the resource usage columns
+
+	scope Scope
+	user  string // user the ScopeMine scope keeps
 }
 
+// Scope narrows the processes listed. Scopes combine.
+type Scope int
+
+const (
+	ScopeNetworked Scope = 1 << iota // only processes with sockets or Unix sockets
+	ScopeMine                        // only the current user's processes
+	ScopeNoKernel                    // hide kernel threads
+)
+
 var sortEntries = []tabs.SortEntry{
 	{Key: "i", ColKey: "pid", SortKey: "raw_pid", Label: "PID"},
 	{Key: "n", ColKey: "name", SortKey: "name", Label: "Name"},
 	{Key: "m", ColKey: "command", SortKey: "command", Label: "Command"},
 	{Key: "u", ColKey: "user", SortKey: "user", Label: "User"},
+	{Key: "t", ColKey: "unit", SortKey: "raw_unit", Label: "Unit"},
+	{Key: "k", ColKey: "container", SortKey: "raw_container", Label: "Container"},
 	{Key: "c", ColKey: "conns", SortKey: "conns", Label: "#Sockets"},
 	{Key: "x", ColKey: "unix_socks", SortKey: "unix_socks", Label: "#Unix"},
+	{Key: "p", ColKey: "cpu", SortKey: "raw_cpu", Label: "CPU%"},
+	{Key: "r", ColKey: "rss", SortKey: "raw_rss", Label: "RSS"},
+	{Key: "f", ColKey: "fds", SortKey: "raw_fd_usage", Label: "FD use"},
+	{Key: "a", ColKey: "uptime", SortKey: "raw_uptime", Label: "Uptime"},
+}
+
+var groupEntries = []tabs.GroupEntry{
+	{ColKey: "container", RawKey: "raw_container", Label: "Container"},
+	{ColKey: "unit", RawKey: "raw_unit", Label: "Unit"},
 }
 
+// fdWarnUsage is the fraction of RLIMIT_NOFILE in use at which a process
+// is highlighted.
+const fdWarnUsage = 0.8
+
 // New creates a new Processes tab model.
 func New() *Model {
 	m := &Model{
-		tabID: model.TabProcesses,
+		tabID:     model.TabProcesses,
+		collapsed: make(map[int32]bool),
+		user:      util.CurrentUser(),
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false, false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -54,17 +91,60 @@ func (m *Model) buildRows() []table.Row {
 	if m.store == nil {
 		return nil
 	}
+	treeConns, treeUnix := subtreeTotals(m.store.Processes)
+	now := time.Now()
 	rows := make([]table.Row, 0, len(m.store.Processes))
 	for _, p := range m.store.Processes {
-		rows = append(rows, table.NewRow(table.RowData{
-			"pid":        util.FormatPID(p.PID),
-			"name":       util.FormatProcess(p.Name),
-			"command":    p.Command,
-			"user":       p.User,
-			"conns":      fmt.Sprintf("%d", p.NumConns),
-			"unix_socks": fmt.Sprintf("%d", p.NumUnixSocks),
-			"raw_pid":    p.PID,
-		}))
+		parent := util.FormatPID(p.PPID)
+		if pp, ok := m.store.ProcessByPID[p.PPID]; ok {
+			parent = fmt.Sprintf("%d (%s)", p.PPID, pp.Name)
+		}
+		var uptime time.Duration
+		if !p.StartTime.IsZero() {
+			uptime = now.Sub(p.StartTime)
+		}
+		row := table.NewRow(table.RowData{
+			"netns":             p.Namespace,
+			"pid":               util.FormatPID(p.PID),
+			"name":              util.FormatProcess(p.Name),
+			"command":           p.Command,
+			"user":              p.User,
+			"unit":              p.Unit,
+			"container":         p.Container.String(),
+			"conns":             fmt.Sprintf("%d", p.NumConns),
+			"unix_socks":        fmt.Sprintf("%d", p.NumUnixSocks),
+			"tree_conns":        fmt.Sprintf("%d", treeConns[p.PID]),
+			"tree_unix":         fmt.Sprintf("%d", treeUnix[p.PID]),
+			"parent":            parent,
+			"raw_pid":           p.PID,
+			"raw_ppid":          p.PPID,
+			"raw_networked":     p.NumConns > 0 || p.NumUnixSocks > 0,
+			"raw_kernel":        p.Kernel,
+			"raw_unit":          p.Unit,
+			"slice":             p.Slice,
+			"cgroup":            p.Cgroup,
+			"raw_container":     p.Container.String(),
+			"container_runtime": p.Container.Runtime,
+			"container_id":      p.Container.ID,
+			"pod_uid":           p.Container.PodUID,
+			"cpu":               fmt.Sprintf("%.1f", p.CPUPercent),
+			"rss":               formatRSS(p.RSS),
+			"threads":           formatCount(p.NumThreads),
+			"fds":               formatFDs(p),
+			"uptime":            formatUptime(uptime),
+			"exe":               p.Exe,
+			"cwd":               p.Cwd,
+			"started":           formatStarted(p.StartTime),
+			"raw_cpu":           p.CPUPercent,
+			"raw_rss":           p.RSS,
+			"raw_fd_usage":      p.FDUsage(),
+			"raw_uptime":        uptime.Seconds(),
+		})
+		// Highlight processes close to running out of file descriptors.
+		if p.FDUsage() >= fdWarnUsage {
+			row = row.WithStyle(model.DegradedRowStyle)
+		}
+		rows = append(rows, row)
 	}
 	return rows
 }
@@ -89,16 +169,185 @@ func (m *Model) View() string {
 // SetData implements Tab.
 func (m *Model) SetData(store *data.Store) {
 	m.store = store
-	rows := m.buildRows()
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.applyColumns()
+	}
+	m.applyFilters()
+}
+
+// applyFilters rebuilds the rows with the scopes, cross-ref filter, sort
+// and grouping applied, in that order. In the tree view the sort orders
+// siblings instead, and filtered processes keep their ancestors.
+func (m *Model) applyFilters() {
+	all := m.buildRows()
+	rows := m.filterScope(all)
 	if m.navKey != "" {
 		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
 	}
-	if m.sort.Active() {
+	if m.tree && len(rows) < len(all) {
+		rows = withAncestors(rows, all)
+	}
+	switch {
+	case m.sort.Active():
 		m.sort.SortRows(rows)
+	case m.tree:
+		sortByPID(rows)
+	}
+	if m.tree {
+		rows = treeOrder(rows, m.collapsed)
+	} else {
+		m.group.GroupRows(rows)
 	}
 	m.table = m.table.WithRows(rows)
 }
 
+// filterScope drops the rows outside the active scopes.
+func (m *Model) filterScope(rows []table.Row) []table.Row {
+	if m.scope == 0 {
+		return rows
+	}
+	filtered := make([]table.Row, 0, len(rows))
+	for _, r := range rows {
+		networked, _ := r.Data["raw_networked"].(bool)
+		kernel, _ := r.Data["raw_kernel"].(bool)
+		user, _ := r.Data["user"].(string)
+		switch {
+		case m.scope&ScopeNetworked != 0 && !networked:
+		case m.scope&ScopeMine != 0 && user != m.user:
+		case m.scope&ScopeNoKernel != 0 && kernel:
+		default:
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// ToggleScope turns the given scope on or off.
+func (m *Model) ToggleScope(s Scope) {
+	m.scope ^= s
+	m.applyFilters()
+}
+
+// ClearScopes turns all scopes off.
+func (m *Model) ClearScopes() {
+	m.scope = 0
+	m.applyFilters()
+}
+
+// ScopeLabel returns a display label for the active scopes, or "" if none.
+func (m *Model) ScopeLabel() string {
+	var parts []string
+	if m.scope&ScopeNetworked != 0 {
+		parts = append(parts, "networked")
+	}
+	if m.scope&ScopeMine != 0 {
+		parts = append(parts, "user:"+m.user)
+	}
+	if m.scope&ScopeNoKernel != 0 {
+		parts = append(parts, "no kthreads")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, "+") + "]"
+}
+
+func (m *Model) applyColumns() {
+	if m.tree {
+		m.table = m.table.WithColumns(treeColumns(m.netns, m.resources))
+	} else {
+		m.table = m.table.WithColumns(columns(m.netns, m.resources))
+	}
+}
+
+// ToggleTree switches between the flat process list and the process tree.
+// Grouping is turned off in the tree, which has its own order.
+func (m *Model) ToggleTree() {
+	m.tree = !m.tree
+	if m.tree {
+		m.group = tabs.GroupState{}
+	}
+	m.applyColumns()
+	m.applyFilters()
+}
+
+// ToggleResources shows or hides the CPU, memory, thread, file descriptor
+// and uptime columns.
+func (m *Model) ToggleResources() {
+	m.resources = !m.resources
+	m.applyColumns()
+}
+
+// TreeLabel returns a status bar label while the tree view is shown.
+func (m *Model) TreeLabel() string {
+	if !m.tree {
+		return ""
+	}
+	return "[tree]"
+}
+
+// SelectedPID returns the PID of the highlighted process.
+func (m *Model) SelectedPID() (int32, bool) {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return 0, false
+	}
+	pid, _ := row.Data["raw_pid"].(int32)
+	return pid, true
+}
+
+// ToggleCollapse hides or shows the selected process's descendants in the
+// tree view.
+func (m *Model) ToggleCollapse() {
+	if !m.tree {
+		return
+	}
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return
+	}
+	pid, _ := row.Data["raw_pid"].(int32)
+	if m.collapsed[pid] {
+		delete(m.collapsed, pid)
+	} else if m.hasChildren(pid) {
+		m.collapsed[pid] = true
+	}
+	m.applyFilters()
+}
+
+// CollapseAll hides every subtree in the tree view, leaving the roots.
+func (m *Model) CollapseAll() {
+	if !m.tree || m.store == nil {
+		return
+	}
+	for _, p := range m.store.Processes {
+		if p.PPID != p.PID {
+			m.collapsed[p.PPID] = true
+		}
+	}
+	m.applyFilters()
+	m.table = m.table.WithHighlightedRow(0)
+}
+
+// ExpandAll shows every subtree in the tree view.
+func (m *Model) ExpandAll() {
+	if !m.tree {
+		return
+	}
+	m.collapsed = make(map[int32]bool)
+	m.applyFilters()
+}
+
+func (m *Model) hasChildren(pid int32) bool {
+	for _, p := range m.store.Processes {
+		if p.PPID == pid && p.PID != pid {
+			return true
+		}
+	}
+	return false
+}
+
 // SetSize implements Tab.
 func (m *Model) SetSize(width, height int) {
 	m.width = width
@@ -155,17 +404,55 @@ func (m *Model) CrossRefTo(target model.TabID) *model.CrossRefMsg {
 
 // NavigateTo implements Tab.
 func (m *Model) NavigateTo(key, val string) {
-	if key != "pid" {
+	if key != "pid" && key != "container" && key != "unit" {
 		return
 	}
 	m.navKey = key
 	m.navVal = val
-	rows := m.buildRows()
-	rows = tabs.FilterNavRows(rows, key, val)
-	if m.sort.Active() {
-		m.sort.SortRows(rows)
+	m.applyFilters()
+	m.table = m.table.WithHighlightedRow(m.firstMatch())
+}
+
+// firstMatch returns the index of the first row matching the cross-ref
+// filter, which in the tree view may follow the rows of its ancestors.
+func (m *Model) firstMatch() int {
+	for i, r := range m.table.GetVisibleRows() {
+		if fmt.Sprintf("%v", r.Data[m.navKey]) == m.navVal {
+			return i
+		}
 	}
-	m.table = m.table.WithRows(rows).WithHighlightedRow(0)
+	return 0
+}
+
+// FilterBySame narrows the list to processes sharing the selected
+// process's "container" or "unit". It returns false if that process has
+// none.
+func (m *Model) FilterBySame(key string) bool {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return false
+	}
+	v, _ := row.Data["raw_"+key].(string)
+	if v == "" {
+		return false
+	}
+	m.NavigateTo(key, v)
+	return true
+}
+
+// CycleGroup implements tabs.Grouper. Grouping leaves the tree view.
+func (m *Model) CycleGroup() {
+	if m.tree {
+		m.tree = false
+		m.applyColumns()
+	}
+	m.group.Cycle(groupEntries)
+	m.applyFilters()
+}
+
+// GroupLabel implements tabs.Grouper.
+func (m *Model) GroupLabel() string {
+	return m.group.Label()
 }
 
 // SortHint implements Tab.
@@ -178,12 +465,7 @@ func (m *Model) ApplySort(key string) {
 	if !m.sort.Apply(sortEntries, key) {
 		return
 	}
-	rows := m.buildRows()
-	if m.navKey != "" {
-		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
-	}
-	m.sort.SortRows(rows)
-	m.table = m.table.WithRows(rows)
+	m.applyFilters()
 }
 
 // SortLabel implements Tab.
@@ -242,11 +524,7 @@ func (m *Model) ClearFilter() {
 	if m.navKey != "" {
 		m.navKey = ""
 		m.navVal = ""
-		rows := m.buildRows()
-		if m.sort.Active() {
-			m.sort.SortRows(rows)
-		}
-		m.table = m.table.WithRows(rows)
+		m.applyFilters()
 	}
 }
 
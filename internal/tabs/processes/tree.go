@@ -0,0 +1,176 @@
+package processes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
+
+func treeColumns(netns, resources bool) []table.Column {
+	return tabs.WithNamespaceColumn(withResourceColumns([]table.Column{
+		table.NewColumn("pid", "PID", 8).WithFiltered(true),
+		table.NewFlexColumn("tree", "Process", 2).WithFiltered(true),
+		table.NewFlexColumn("command", "Command", 2).WithFiltered(true),
+		table.NewFlexColumn("user", "User", 1),
+		table.NewColumn("unit", "Unit", 22).WithFiltered(true),
+		table.NewColumn("conns", "#Sockets", 10),
+		table.NewColumn("tree_conns", "Σ#Sockets", 11),
+		table.NewColumn("unix_socks", "#Unix", 8),
+		table.NewColumn("tree_unix", "Σ#Unix", 8),
+	}, resources), netns)
+}
+
+// subtreeTotals sums socket and Unix socket counts over each process and
+// all of its descendants.
+func subtreeTotals(procs []data.Process) (conns, unix map[int32]int) {
+	children := make(map[int32][]int32, len(procs))
+	byPID := make(map[int32]*data.Process, len(procs))
+	for i := range procs {
+		p := &procs[i]
+		byPID[p.PID] = p
+		if p.PPID != p.PID {
+			children[p.PPID] = append(children[p.PPID], p.PID)
+		}
+	}
+
+	conns = make(map[int32]int, len(procs))
+	unix = make(map[int32]int, len(procs))
+	done := make(map[int32]bool, len(procs))
+	var sum func(pid int32)
+	sum = func(pid int32) {
+		if done[pid] {
+			return
+		}
+		done[pid] = true
+		p := byPID[pid]
+		conns[pid], unix[pid] = p.NumConns, p.NumUnixSocks
+		for _, kid := range children[pid] {
+			sum(kid)
+			conns[pid] += conns[kid]
+			unix[pid] += unix[kid]
+		}
+	}
+	for pid := range byPID {
+		sum(pid)
+	}
+	return conns, unix
+}
+
+// withAncestors adds to kept the rows of every ancestor of a kept process,
+// so a filtered tree still shows the chain of parents that spawned each
+// match.
+func withAncestors(kept, all []table.Row) []table.Row {
+	byPID := make(map[int32]table.Row, len(all))
+	for _, r := range all {
+		pid, _ := r.Data["raw_pid"].(int32)
+		byPID[pid] = r
+	}
+	have := make(map[int32]bool, len(kept))
+	for _, r := range kept {
+		pid, _ := r.Data["raw_pid"].(int32)
+		have[pid] = true
+	}
+	result := kept
+	for _, r := range kept {
+		ppid, _ := r.Data["raw_ppid"].(int32)
+		for !have[ppid] {
+			parent, ok := byPID[ppid]
+			if !ok {
+				break
+			}
+			have[ppid] = true
+			result = append(result, parent)
+			ppid, _ = parent.Data["raw_ppid"].(int32)
+		}
+	}
+	return result
+}
+
+// treeOrder arranges rows as an indented process tree, with children
+// under their parent in the order they appear in rows. The tree prefix is
+// stored under "tree"; "name" is left untouched. Descendants of collapsed
+// processes are left out, and the collapsed process shows how many.
+func treeOrder(rows []table.Row, collapsed map[int32]bool) []table.Row {
+	rowByPID := make(map[int32]table.Row, len(rows))
+	for _, r := range rows {
+		pid, _ := r.Data["raw_pid"].(int32)
+		rowByPID[pid] = r
+	}
+
+	children := make(map[int32][]int32)
+	var roots []int32
+	for _, r := range rows {
+		pid, _ := r.Data["raw_pid"].(int32)
+		ppid, _ := r.Data["raw_ppid"].(int32)
+		if _, ok := rowByPID[ppid]; ok && ppid != pid {
+			children[ppid] = append(children[ppid], pid)
+		} else {
+			roots = append(roots, pid)
+		}
+	}
+
+	var descendants func(pid int32) int
+	descendants = func(pid int32) int {
+		n := 0
+		for _, kid := range children[pid] {
+			n += 1 + descendants(kid)
+		}
+		return n
+	}
+
+	ordered := make([]table.Row, 0, len(rows))
+	visited := make(map[int32]bool, len(rows))
+	var walk func(pid int32, indent, branch string)
+	walk = func(pid int32, indent, branch string) {
+		if visited[pid] {
+			return
+		}
+		visited[pid] = true
+		r := rowByPID[pid]
+		name, _ := r.Data["name"].(string)
+		kids := children[pid]
+		switch {
+		case len(kids) == 0:
+			r.Data["tree"] = indent + branch + name
+		case collapsed[pid]:
+			r.Data["tree"] = fmt.Sprintf("%s%s▸ %s (+%d)", indent, branch, name, descendants(pid))
+		default:
+			r.Data["tree"] = indent + branch + "▾ " + name
+		}
+		ordered = append(ordered, r)
+		if collapsed[pid] {
+			return
+		}
+
+		next := indent
+		switch branch {
+		case "├─ ":
+			next += "│  "
+		case "└─ ":
+			next += "   "
+		}
+		for i, kid := range kids {
+			b := "├─ "
+			if i == len(kids)-1 {
+				b = "└─ "
+			}
+			walk(kid, next, b)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+	return ordered
+}
+
+// sortByPID orders rows by PID, the tree's default sibling order.
+func sortByPID(rows []table.Row) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, _ := rows[i].Data["raw_pid"].(int32)
+		b, _ := rows[j].Data["raw_pid"].(int32)
+		return a < b
+	})
+}
@@ -1,13 +1,16 @@
 package routes
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
 		table.NewFlexColumn("dest", "Destination", 1).WithFiltered(true),
 		table.NewFlexColumn("gateway", "Gateway", 1).WithFiltered(true),
 		table.NewFlexColumn("netmask", "Netmask", 1),
 		table.NewColumn("iface", "Interface", 12).WithFiltered(true),
 		table.NewColumn("flags", "Flags", 10),
-	}
+	}, netns)
 }
@@ -26,10 +26,14 @@ func detailContent(rowData map[string]interface{}) string {
 		{"Netmask", "netmask"},
 		{"Interface", "iface"},
 		{"Flags", "flags"},
+		{"Namespace", "netns"},
 	}
 
 	for _, f := range fields {
 		val := fmt.Sprintf("%v", rowData[f.key])
+		if f.key == "netns" && val == "" {
+			continue
+		}
 		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-14s", f.label)))
 		b.WriteString(model.PanelValueStyle.Render(val))
 		b.WriteString("\n")
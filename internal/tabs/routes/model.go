@@ -21,6 +21,7 @@ type Model struct {
 	navKey string
 	navVal string
 	sort   tabs.SortState
+	netns  bool
 }
 
 var sortEntries = []tabs.SortEntry{
@@ -36,7 +37,7 @@ func New() *Model {
 	m := &Model{
 		tabID: model.TabRoutes,
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -54,11 +55,13 @@ func (m *Model) buildRows() []table.Row {
 	rows := make([]table.Row, 0, len(m.store.Routes))
 	for _, r := range m.store.Routes {
 		rows = append(rows, table.NewRow(table.RowData{
-			"dest":    r.Destination,
-			"gateway": r.Gateway,
-			"netmask": r.Netmask,
-			"iface":   r.Interface,
-			"flags":   r.Flags,
+			"netns":      r.Namespace,
+			"dest":       r.Destination,
+			"gateway":    r.Gateway,
+			"netmask":    r.Netmask,
+			"iface":      r.Interface,
+			"flags":      r.Flags,
+			"raw_metric": r.Metric,
 		}))
 	}
 	return rows
@@ -111,6 +114,10 @@ func (m *Model) View() string {
 // SetData implements Tab.
 func (m *Model) SetData(store *data.Store) {
 	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
 	rows := m.buildRows()
 	if m.navKey != "" {
 		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
@@ -145,6 +152,23 @@ func (m *Model) SelectedRow() string {
 	return fmt.Sprintf("%s via %s dev %s", dest, gw, iface)
 }
 
+// SelectedRoute returns the route of the highlighted row.
+func (m *Model) SelectedRoute() (data.Route, bool) {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return data.Route{}, false
+	}
+	r := data.Route{}
+	r.Destination, _ = row.Data["dest"].(string)
+	r.Gateway, _ = row.Data["gateway"].(string)
+	r.Netmask, _ = row.Data["netmask"].(string)
+	r.Interface, _ = row.Data["iface"].(string)
+	r.Flags, _ = row.Data["flags"].(string)
+	r.Metric, _ = row.Data["raw_metric"].(int)
+	r.Namespace, _ = row.Data["netns"].(string)
+	return r, true
+}
+
 // DetailContent implements Tab.
 func (m *Model) DetailContent() string {
 	row := m.table.HighlightedRow()
@@ -1,15 +1,20 @@
 package sockets
 
-import "github.com/evertras/bubble-table/table"
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
 
-func columns() []table.Column {
-	return []table.Column{
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
 		table.NewColumn("proto", "Proto", 7),
 		table.NewFlexColumn("local", "Local Address", 1).WithFiltered(true),
 		table.NewFlexColumn("remote", "Remote Address", 1).WithFiltered(true),
 		table.NewColumn("state", "State", 14),
 		table.NewColumn("pid", "PID", 8).WithFiltered(true),
 		table.NewColumn("process", "Process", 18).WithFiltered(true),
+		table.NewColumn("unit", "Unit", 22).WithFiltered(true),
+		table.NewColumn("container", "Container", 20).WithFiltered(true),
 		table.NewFlexColumn("command", "Command", 2).WithFiltered(true),
-	}
+	}, netns)
 }
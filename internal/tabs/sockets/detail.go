@@ -23,14 +23,23 @@ func detailContent(rowData map[string]interface{}, panelWidth int) string {
 		label string
 		key   string
 		wrap  bool
+		omit  bool // skip when empty
 	}{
-		{"Protocol", "proto", false},
-		{"Local", "local", false},
-		{"Remote", "remote", false},
-		{"State", "state", false},
-		{"PID", "pid", false},
-		{"Process", "process", false},
-		{"Command", "command", true},
+		{"Protocol", "proto", false, false},
+		{"Local", "local", false, false},
+		{"Remote", "remote", false, false},
+		{"State", "state", false, false},
+		{"PID", "pid", false, false},
+		{"Process", "process", false, false},
+		{"Command", "command", true, false},
+		{"Unit", "raw_unit", false, true},
+		{"Slice", "slice", false, true},
+		{"Cgroup", "cgroup", true, true},
+		{"Container", "raw_container", false, true},
+		{"Runtime", "container_runtime", false, true},
+		{"Container ID", "container_id", false, true},
+		{"Pod UID", "pod_uid", false, true},
+		{"Namespace", "netns", false, true},
 	}
 
 	// Available width for values: panel minus border/padding (4) minus label.
@@ -41,6 +50,9 @@ func detailContent(rowData map[string]interface{}, panelWidth int) string {
 
 	for _, f := range fields {
 		val := fmt.Sprintf("%v", rowData[f.key])
+		if f.omit && val == "" {
+			continue
+		}
 		label := model.PanelLabelStyle.Render(fmt.Sprintf("%-*s", labelWidth, f.label))
 
 		if f.wrap && len(val) > valWidth {
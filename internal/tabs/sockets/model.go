@@ -48,6 +48,8 @@ type Model struct {
 	ipVersionFilter IPVersionFilter
 
 	sort       tabs.SortState
+	group      tabs.GroupState
+	netns      bool
 	panelWidth int
 }
 
@@ -58,16 +60,23 @@ var sortEntries = []tabs.SortEntry{
 	{Key: "s", ColKey: "state", SortKey: "state", Label: "State"},
 	{Key: "i", ColKey: "pid", SortKey: "raw_pid", Label: "PID"},
 	{Key: "n", ColKey: "process", SortKey: "process", Label: "Process"},
+	{Key: "t", ColKey: "unit", SortKey: "raw_unit", Label: "Unit"},
+	{Key: "k", ColKey: "container", SortKey: "raw_container", Label: "Container"},
 	{Key: "m", ColKey: "command", SortKey: "command", Label: "Command"},
 }
 
+var groupEntries = []tabs.GroupEntry{
+	{ColKey: "container", RawKey: "raw_container", Label: "Container"},
+	{ColKey: "unit", RawKey: "raw_unit", Label: "Unit"},
+}
+
 // New creates a new Sockets tab model.
 func New(dns *sources.DNSCache) *Model {
 	m := &Model{
 		tabID:    model.TabSockets,
 		dnsCache: dns,
 	}
-	m.table = table.New(columns()).
+	m.table = table.New(columns(false)).
 		WithBaseStyle(lipgloss.NewStyle()).
 		Focused(true).
 		WithPageSize(20).
@@ -91,25 +100,35 @@ func (m *Model) buildRows() []table.Row {
 		if m.dnsOn && m.dnsCache != nil && remoteAddr != "" {
 			remoteAddr = m.dnsCache.Lookup(remoteAddr)
 		}
-		command := ""
+		var proc data.Process
 		if m.store != nil && s.PID > 0 {
-			if proc, ok := m.store.ProcessByPID[s.PID]; ok {
-				command = proc.Command
+			if p, ok := m.store.ProcessByPID[s.PID]; ok {
+				proc = *p
 			}
 		}
 		rows = append(rows, table.NewRow(table.RowData{
-			"proto":           s.Proto,
-			"local":           util.FormatAddrPort(s.LocalAddr, s.LocalPort),
-			"remote":          util.FormatAddrPort(remoteAddr, s.RemotePort),
-			"state":           s.State,
-			"pid":             util.FormatPID(s.PID),
-			"process":         util.FormatProcess(s.Process),
-			"command":         command,
-			"raw_pid":         s.PID,
-			"raw_local_addr":  s.LocalAddr,
-			"raw_local_port":  s.LocalPort,
-			"raw_remote_addr": s.RemoteAddr,
-			"raw_remote_port": s.RemotePort,
+			"netns":             s.Namespace,
+			"proto":             s.Proto,
+			"local":             util.FormatAddrPort(s.LocalAddr, s.LocalPort),
+			"remote":            util.FormatAddrPort(remoteAddr, s.RemotePort),
+			"state":             s.State,
+			"pid":               util.FormatPID(s.PID),
+			"process":           util.FormatProcess(s.Process),
+			"command":           proc.Command,
+			"unit":              proc.Unit,
+			"container":         proc.Container.String(),
+			"raw_pid":           s.PID,
+			"raw_local_addr":    s.LocalAddr,
+			"raw_local_port":    s.LocalPort,
+			"raw_remote_addr":   s.RemoteAddr,
+			"raw_remote_port":   s.RemotePort,
+			"raw_unit":          proc.Unit,
+			"slice":             proc.Slice,
+			"cgroup":            proc.Cgroup,
+			"raw_container":     proc.Container.String(),
+			"container_runtime": proc.Container.Runtime,
+			"container_id":      proc.Container.ID,
+			"pod_uid":           proc.Container.PodUID,
 		}))
 	}
 	return rows
@@ -193,13 +212,11 @@ func (m *Model) ProtoFilterLabel() string {
 }
 
 func (m *Model) applyFilters() {
-	rows := m.buildRows()
-	if m.navKey != "" {
-		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
-	}
+	rows := m.filterNav(m.buildRows())
 	if m.sort.Active() {
 		m.sort.SortRows(rows)
 	}
+	m.group.GroupRows(rows)
 	m.table = m.table.WithRows(rows)
 }
 
@@ -228,6 +245,10 @@ func (m *Model) View() string {
 // SetData implements Tab.
 func (m *Model) SetData(store *data.Store) {
 	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
 	m.applyFilters()
 }
 
@@ -255,6 +276,24 @@ func (m *Model) SelectedRow() string {
 	return fmt.Sprintf("%s %s -> %s", proto, local, remote)
 }
 
+// SelectedSocket returns the socket of the highlighted row.
+func (m *Model) SelectedSocket() (data.Socket, bool) {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return data.Socket{}, false
+	}
+	s := data.Socket{}
+	s.Proto, _ = row.Data["proto"].(string)
+	s.LocalAddr, _ = row.Data["raw_local_addr"].(string)
+	s.LocalPort, _ = row.Data["raw_local_port"].(uint32)
+	s.RemoteAddr, _ = row.Data["raw_remote_addr"].(string)
+	s.RemotePort, _ = row.Data["raw_remote_port"].(uint32)
+	s.State, _ = row.Data["state"].(string)
+	s.PID, _ = row.Data["raw_pid"].(int32)
+	s.Namespace, _ = row.Data["netns"].(string)
+	return s, true
+}
+
 // DetailContent implements Tab.
 func (m *Model) DetailContent() string {
 	row := m.table.HighlightedRow()
@@ -281,9 +320,80 @@ func (m *Model) CrossRef() *model.CrossRefMsg {
 	}
 }
 
+// filterNav applies the cross-ref filter. "laddr" takes a comma-separated
+// list of addresses and also keeps wildcard-bound sockets of the same
+// family, since those accept traffic on every local address. "listener"
+// takes "proto/port" and keeps the sockets on that local port, both
+// listening and accepted ones. "socket" takes "proto local remote" and
+// keeps that one socket.
+func (m *Model) filterNav(rows []table.Row) []table.Row {
+	switch m.navKey {
+	case "listener":
+		return filterListener(rows, m.navVal)
+	case "socket":
+		return filterSocket(rows, m.navVal)
+	}
+	if m.navKey != "laddr" {
+		if m.navKey != "" {
+			rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
+		}
+		return rows
+	}
+	want := make(map[string]bool)
+	var v4, v6 bool
+	for _, a := range strings.Split(m.navVal, ",") {
+		want[a] = true
+		if strings.Contains(a, ":") {
+			v6 = true
+		} else {
+			v4 = true
+		}
+	}
+	filtered := make([]table.Row, 0, len(rows))
+	for _, r := range rows {
+		addr, _ := r.Data["raw_local_addr"].(string)
+		switch {
+		case want[addr]:
+		case addr == "0.0.0.0" && v4, addr == "::" && v6:
+		default:
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+func filterListener(rows []table.Row, val string) []table.Row {
+	proto, port, _ := strings.Cut(val, "/")
+	filtered := make([]table.Row, 0, len(rows))
+	for _, r := range rows {
+		p, _ := r.Data["proto"].(string)
+		lport, _ := r.Data["raw_local_port"].(uint32)
+		if strings.TrimSuffix(p, "6") == proto && fmt.Sprintf("%d", lport) == port {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+func filterSocket(rows []table.Row, val string) []table.Row {
+	filtered := make([]table.Row, 0, 1)
+	for _, r := range rows {
+		proto, _ := r.Data["proto"].(string)
+		laddr, _ := r.Data["raw_local_addr"].(string)
+		lport, _ := r.Data["raw_local_port"].(uint32)
+		raddr, _ := r.Data["raw_remote_addr"].(string)
+		rport, _ := r.Data["raw_remote_port"].(uint32)
+		if (data.Socket{Proto: proto, LocalAddr: laddr, LocalPort: lport, RemoteAddr: raddr, RemotePort: rport}).Tuple() == val {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
 // NavigateTo implements Tab.
 func (m *Model) NavigateTo(key, val string) {
-	if key != "pid" {
+	if key != "pid" && key != "laddr" && key != "container" && key != "unit" && key != "listener" && key != "socket" {
 		return
 	}
 	m.navKey = key
@@ -292,6 +402,33 @@ func (m *Model) NavigateTo(key, val string) {
 	m.table = m.table.WithHighlightedRow(0)
 }
 
+// FilterBySame narrows the list to sockets whose owners share the
+// selected socket owner's "container" or "unit". It returns false if the
+// owner has none.
+func (m *Model) FilterBySame(key string) bool {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return false
+	}
+	v, _ := row.Data["raw_"+key].(string)
+	if v == "" {
+		return false
+	}
+	m.NavigateTo(key, v)
+	return true
+}
+
+// CycleGroup implements tabs.Grouper.
+func (m *Model) CycleGroup() {
+	m.group.Cycle(groupEntries)
+	m.applyFilters()
+}
+
+// GroupLabel implements tabs.Grouper.
+func (m *Model) GroupLabel() string {
+	return m.group.Label()
+}
+
 // SortHint implements Tab.
 func (m *Model) SortHint() string {
 	return tabs.Hint(sortEntries)
@@ -399,13 +536,11 @@ func (m *Model) GoToRemotePeer() bool {
 	}
 
 	// Build rows in current display order
-	rows := m.buildRows()
-	if m.navKey != "" {
-		rows = tabs.FilterNavRows(rows, m.navKey, m.navVal)
-	}
+	rows := m.filterNav(m.buildRows())
 	if m.sort.Active() {
 		m.sort.SortRows(rows)
 	}
+	m.group.GroupRows(rows)
 
 	// First pass: bidirectional match — peer's local matches our remote
 	// AND peer's remote matches our local (the true connection peer).
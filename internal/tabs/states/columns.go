@@ -0,0 +1,21 @@
+package states
+
+import (
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/tabs"
+)
+
+func columns(netns bool) []table.Column {
+	return tabs.WithNamespaceColumn([]table.Column{
+		table.NewColumn("proto", "Proto", 6).WithFiltered(true),
+		table.NewFlexColumn("orig", "Original", 2).WithFiltered(true),
+		table.NewFlexColumn("reply", "Reply", 2).WithFiltered(true),
+		table.NewColumn("nat", "NAT", 9).WithFiltered(true),
+		table.NewColumn("state", "State", 23).WithFiltered(true),
+		table.NewColumn("timeout", "Expires", 8),
+		table.NewColumn("packets", "Packets", 9),
+		table.NewColumn("bytes", "Bytes", 10),
+		table.NewColumn("pid", "PID", 7).WithFiltered(true),
+		table.NewColumn("process", "Process", 16).WithFiltered(true),
+	}, netns)
+}
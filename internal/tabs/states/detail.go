@@ -0,0 +1,53 @@
+package states
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+func detailContent(rowData map[string]interface{}) string {
+	if rowData == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(model.PanelHeaderStyle.Render("State Entry Details"))
+	b.WriteString("\n\n")
+
+	fields := []struct {
+		label string
+		key   string
+	}{
+		{"Protocol", "proto"},
+		{"Family", "family"},
+		{"Direction", "dir"},
+		{"Interface", "iface"},
+		{"Original", "orig"},
+		{"Reply", "reply"},
+		{"Translation", "raw_translation"},
+		{"State", "state"},
+		{"Expires In", "timeout"},
+		{"Age", "age"},
+		{"Packets", "raw_packets_split"},
+		{"Bytes", "raw_bytes_split"},
+		{"Socket", "raw_socket"},
+		{"PID", "pid"},
+		{"Process", "process"},
+		{"Namespace", "netns"},
+	}
+
+	for _, f := range fields {
+		val := fmt.Sprintf("%v", rowData[f.key])
+		if val == "" || val == "<nil>" {
+			continue
+		}
+		b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-14s", f.label)))
+		b.WriteString(model.PanelValueStyle.Render(val))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
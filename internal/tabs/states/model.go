@@ -0,0 +1,286 @@
+package states
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+	"github.com/jerryluo/nettui/internal/tabs"
+	"github.com/jerryluo/nettui/internal/util"
+)
+
+// Model is the States tab model.
+type Model struct {
+	table  table.Model
+	store  *data.Store
+	width  int
+	height int
+	tabID  model.TabID
+	sort   tabs.SortState
+	netns  bool
+}
+
+var sortEntries = []tabs.SortEntry{
+	{Key: "p", ColKey: "proto", SortKey: "proto", Label: "Proto"},
+	{Key: "s", ColKey: "state", SortKey: "state", Label: "State"},
+	{Key: "e", ColKey: "timeout", SortKey: "raw_timeout", Label: "Expires"},
+	{Key: "k", ColKey: "packets", SortKey: "raw_packets", Label: "Packets"},
+	{Key: "b", ColKey: "bytes", SortKey: "raw_bytes", Label: "Bytes"},
+	{Key: "i", ColKey: "pid", SortKey: "raw_pid", Label: "PID"},
+	{Key: "n", ColKey: "process", SortKey: "process", Label: "Process"},
+}
+
+var natStyle = lipgloss.NewStyle().Foreground(model.AccentColor)
+
+// New creates a new States tab model.
+func New() *Model {
+	m := &Model{
+		tabID: model.TabStates,
+	}
+	m.table = table.New(columns(false)).
+		WithBaseStyle(lipgloss.NewStyle()).
+		Focused(true).
+		WithPageSize(20).
+		Filtered(true).
+		HeaderStyle(model.TableHeaderStyle).
+		HighlightStyle(model.SelectedRowStyle).
+		WithPaginationWrapping(false)
+	return m
+}
+
+func (m *Model) buildRows() []table.Row {
+	if m.store == nil {
+		return nil
+	}
+	rows := make([]table.Row, 0, len(m.store.FirewallStates))
+	for _, st := range m.store.FirewallStates {
+		var sock data.Socket
+		var socket string
+		if st.Socket != nil {
+			sock = *st.Socket
+			socket = sock.Tuple()
+		}
+		age := ""
+		if st.Age > 0 {
+			age = util.FormatDuration(st.Age)
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"netns":             st.Namespace,
+			"proto":             st.Proto,
+			"family":            st.Family,
+			"dir":               st.Direction,
+			"iface":             st.Iface,
+			"orig":              st.Orig.String(),
+			"reply":             st.Reply.String(),
+			"nat":               table.NewStyledCell(natText(st), natStyle),
+			"state":             st.State,
+			"timeout":           util.FormatDuration(st.Timeout),
+			"age":               age,
+			"packets":           fmt.Sprintf("%d", st.Packets()),
+			"bytes":             util.FormatBytes(st.Bytes()),
+			"pid":               util.FormatPID(sock.PID),
+			"process":           util.FormatProcess(sock.Process),
+			"raw_translation":   st.Translation(),
+			"raw_packets_split": fmt.Sprintf("%d orig, %d reply", st.OrigPackets, st.ReplyPackets),
+			"raw_bytes_split":   fmt.Sprintf("%s orig, %s reply", util.FormatBytes(st.OrigBytes), util.FormatBytes(st.ReplyBytes)),
+			"raw_socket":        socket,
+			"raw_timeout":       int64(st.Timeout),
+			"raw_packets":       st.Packets(),
+			"raw_bytes":         st.Bytes(),
+			"raw_pid":           sock.PID,
+		}))
+	}
+	return rows
+}
+
+// natText names the translations applied to a connection.
+func natText(st data.FirewallState) string {
+	switch snat, dnat := st.SNAT(), st.DNAT(); {
+	case snat && dnat:
+		return "SNAT+DNAT"
+	case snat:
+		return "SNAT"
+	case dnat:
+		return "DNAT"
+	}
+	return ""
+}
+
+// Init implements tea.Model.
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.table, cmd = tabs.ClampedUpdate(m.table, msg)
+	return m, cmd
+}
+
+// View implements tea.Model.
+func (m *Model) View() string {
+	return m.table.View()
+}
+
+// SetData implements Tab.
+func (m *Model) SetData(store *data.Store) {
+	m.store = store
+	if netns := store.MultiNamespace(); netns != m.netns {
+		m.netns = netns
+		m.table = m.table.WithColumns(columns(netns))
+	}
+	rows := m.buildRows()
+	if m.sort.Active() {
+		m.sort.SortRows(rows)
+	}
+	m.table = m.table.WithRows(rows)
+}
+
+// SetSize implements Tab.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table = m.table.WithPageSize(height - 6).WithTargetWidth(width)
+}
+
+// TabID implements Tab.
+func (m *Model) TabID() model.TabID {
+	return m.tabID
+}
+
+// SelectedRow implements Tab.
+func (m *Model) SelectedRow() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	proto, _ := row.Data["proto"].(string)
+	orig, _ := row.Data["orig"].(string)
+	state, _ := row.Data["state"].(string)
+	return fmt.Sprintf("%s %s %s", proto, orig, state)
+}
+
+// DetailContent implements Tab.
+func (m *Model) DetailContent() string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	return detailContent(row.Data)
+}
+
+// CrossRef implements Tab.
+func (m *Model) CrossRef() *model.CrossRefMsg {
+	return m.CrossRefTo(model.TabSockets)
+}
+
+// CrossRefTo returns a CrossRefMsg targeting the given tab: the Sockets
+// tab filtered to the local socket of the selected entry, or the
+// Processes tab filtered to the process owning it.
+func (m *Model) CrossRefTo(target model.TabID) *model.CrossRefMsg {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return nil
+	}
+	switch target {
+	case model.TabSockets:
+		socket, _ := row.Data["raw_socket"].(string)
+		if socket == "" {
+			return nil
+		}
+		return &model.CrossRefMsg{
+			TargetTab: model.TabSockets,
+			FilterKey: "socket",
+			FilterVal: socket,
+		}
+	case model.TabProcesses:
+		pid, _ := row.Data["raw_pid"].(int32)
+		if pid <= 0 {
+			return nil
+		}
+		return &model.CrossRefMsg{
+			TargetTab: model.TabProcesses,
+			FilterKey: "pid",
+			FilterVal: fmt.Sprintf("%d", pid),
+		}
+	}
+	return nil
+}
+
+// NavigateTo implements Tab.
+func (m *Model) NavigateTo(key, val string) {}
+
+// NavFilterLabel implements Tab.
+func (m *Model) NavFilterLabel() string { return "" }
+
+// SortHint implements Tab.
+func (m *Model) SortHint() string {
+	return tabs.Hint(sortEntries)
+}
+
+// ApplySort implements Tab.
+func (m *Model) ApplySort(key string) {
+	if !m.sort.Apply(sortEntries, key) {
+		return
+	}
+	rows := m.buildRows()
+	m.sort.SortRows(rows)
+	m.table = m.table.WithRows(rows)
+}
+
+// SortLabel implements Tab.
+func (m *Model) SortLabel() string {
+	return m.sort.Label()
+}
+
+// SetPanelWidth implements Tab.
+func (m *Model) SetPanelWidth(width int) {}
+
+// YankHint implements Tab.
+func (m *Model) YankHint() string {
+	return "y→  o:Original  r:Reply  p:PID  n:Process  y:All"
+}
+
+// YankField implements Tab.
+func (m *Model) YankField(key string) string {
+	row := m.table.HighlightedRow()
+	if row.Data == nil {
+		return ""
+	}
+	switch key {
+	case "o":
+		v, _ := row.Data["orig"].(string)
+		return v
+	case "r":
+		v, _ := row.Data["reply"].(string)
+		return v
+	case "p":
+		v, _ := row.Data["pid"].(string)
+		return v
+	case "n":
+		v, _ := row.Data["process"].(string)
+		return v
+	case "y":
+		return m.SelectedRow()
+	}
+	return ""
+}
+
+// IsFiltering implements Tab.
+func (m *Model) IsFiltering() bool {
+	return m.table.GetIsFilterInputFocused()
+}
+
+// HasActiveFilter implements Tab.
+func (m *Model) HasActiveFilter() bool {
+	return m.table.GetCurrentFilter() != ""
+}
+
+// ClearFilter implements Tab.
+func (m *Model) ClearFilter() {
+	m.table = m.table.WithFilterInputValue("")
+}
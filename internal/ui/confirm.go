@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+var (
+	confirmPreviewStyle = lipgloss.NewStyle().Foreground(model.SecondaryColor)
+	confirmOKStyle      = lipgloss.NewStyle().Foreground(model.SuccessColor).Bold(true)
+)
+
+// ConfirmView is a modal asking to confirm a change to the system. It
+// shows what the change affects and the exact rules or commands it
+// applies, and the outcome of a dry run.
+type ConfirmView struct {
+	visible bool
+	title   string
+	details []string
+	preview []string
+	result  string
+	failed  bool
+	dryRun  bool // offer a dry run
+	width   int
+	height  int
+}
+
+// NewConfirmView creates a hidden confirmation modal.
+func NewConfirmView() ConfirmView {
+	return ConfirmView{}
+}
+
+// SetSize updates the area the modal is centered in.
+func (c *ConfirmView) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// Show opens the modal. dryRun offers a dry run of the change.
+func (c *ConfirmView) Show(title string, details, preview []string, dryRun bool) {
+	c.visible = true
+	c.title = title
+	c.details = details
+	c.preview = preview
+	c.dryRun = dryRun
+	c.result = ""
+	c.failed = false
+}
+
+// SetResult shows the outcome of a dry run below the preview.
+func (c *ConfirmView) SetResult(msg string, failed bool) {
+	c.result = msg
+	c.failed = failed
+}
+
+// Close hides the modal.
+func (c *ConfirmView) Close() {
+	c.visible = false
+}
+
+// Visible returns whether the modal is open.
+func (c *ConfirmView) Visible() bool {
+	return c.visible
+}
+
+// View renders the modal centered in its area.
+func (c *ConfirmView) View() string {
+	if !c.visible {
+		return ""
+	}
+	width := min(c.width-4, 96)
+	var b strings.Builder
+	b.WriteString(model.PanelHeaderStyle.Render(c.title))
+	b.WriteString("\n\n")
+	for _, d := range c.details {
+		b.WriteString(model.PanelValueStyle.Render(d))
+		b.WriteString("\n")
+	}
+	if len(c.details) > 0 {
+		b.WriteString("\n")
+	}
+	for _, p := range c.preview {
+		b.WriteString(confirmPreviewStyle.Render("  " + p))
+		b.WriteString("\n")
+	}
+	if c.result != "" {
+		b.WriteString("\n")
+		if c.failed {
+			b.WriteString(model.ErrorStyle.Render(c.result))
+		} else {
+			b.WriteString(confirmOKStyle.Render(c.result))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(model.HelpKeyStyle.Render("enter/y") + model.HelpDescStyle.Render(":apply  "))
+	if c.dryRun {
+		b.WriteString(model.HelpKeyStyle.Render("d") + model.HelpDescStyle.Render(":dry run  "))
+	}
+	b.WriteString(model.HelpKeyStyle.Render("esc/n") + model.HelpDescStyle.Render(":cancel"))
+
+	box := model.PanelBorderStyle.Width(width).Render(b.String())
+	return lipgloss.Place(c.width, c.height, lipgloss.Center, lipgloss.Center, box)
+}
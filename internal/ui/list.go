@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+// ListView is a full-screen list to pick an entry from, such as the
+// firewall rules nettui added.
+type ListView struct {
+	visible bool
+	title   string
+	items   []string
+	empty   string // shown when there are no items
+	hint    string // footer, rendered as given
+	cursor  int
+	err     string
+	width   int
+	height  int
+}
+
+// NewListView creates a hidden list view.
+func NewListView() ListView {
+	return ListView{}
+}
+
+// SetSize updates the view dimensions.
+func (l *ListView) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+}
+
+// Show opens the view on items. empty is shown when there are none, and
+// hint in the footer.
+func (l *ListView) Show(title string, items []string, empty, hint string) {
+	l.visible = true
+	l.title = title
+	l.empty = empty
+	l.hint = hint
+	l.err = ""
+	l.SetItems(items)
+}
+
+// SetItems replaces the items, keeping the cursor in range.
+func (l *ListView) SetItems(items []string) {
+	l.items = items
+	l.cursor = max(0, min(l.cursor, len(items)-1))
+}
+
+// SetError shows an error above the list.
+func (l *ListView) SetError(err error) {
+	l.err = err.Error()
+}
+
+// Cursor returns the index of the selected item, or -1 if there are none.
+func (l *ListView) Cursor() int {
+	if len(l.items) == 0 {
+		return -1
+	}
+	return l.cursor
+}
+
+// Close hides the view.
+func (l *ListView) Close() {
+	l.visible = false
+	l.cursor = 0
+}
+
+// Visible returns whether the view is open.
+func (l *ListView) Visible() bool {
+	return l.visible
+}
+
+// Update moves the selection with j/k and the arrow keys.
+func (l *ListView) Update(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "j", "down":
+		if l.cursor < len(l.items)-1 {
+			l.cursor++
+		}
+	case "k", "up":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	}
+}
+
+// View renders the list view.
+func (l *ListView) View() string {
+	if !l.visible {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(model.PanelHeaderStyle.Render(l.title))
+	b.WriteString("\n\n")
+	if l.err != "" {
+		b.WriteString(model.ErrorStyle.Render(l.err))
+		b.WriteString("\n\n")
+	}
+	if len(l.items) == 0 {
+		b.WriteString(model.HelpDescStyle.Render(l.empty))
+		b.WriteString("\n")
+	}
+
+	// Scroll so the cursor stays in view.
+	rows := max(1, l.height-8)
+	start := max(0, l.cursor-rows+1)
+	for i := start; i < len(l.items) && i < start+rows; i++ {
+		line := fmt.Sprintf("  %s", l.items[i])
+		if i == l.cursor {
+			line = model.SelectedRowStyle.Render(fmt.Sprintf("▶ %s", l.items[i]))
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(l.hint)
+
+	return model.PanelBorderStyle.
+		Width(l.width - 2).
+		Height(l.height - 2).
+		Render(lipgloss.NewStyle().MaxWidth(l.width - 4).Render(b.String()))
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+// PromptView is a modal asking for a line of input, such as an address or
+// an MTU.
+type PromptView struct {
+	visible bool
+	title   string
+	input   textinput.Model
+	err     string
+	width   int
+	height  int
+}
+
+// NewPromptView creates a hidden prompt.
+func NewPromptView() PromptView {
+	in := textinput.New()
+	in.Prompt = "> "
+	return PromptView{input: in}
+}
+
+// SetSize updates the area the modal is centered in.
+func (p *PromptView) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.input.Width = min(width-8, 72) - len(p.input.Prompt)
+}
+
+// Show opens the prompt prefilled with value, showing placeholder while
+// it is empty.
+func (p *PromptView) Show(title, placeholder, value string) tea.Cmd {
+	p.visible = true
+	p.title = title
+	p.err = ""
+	p.input.Placeholder = placeholder
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	return p.input.Focus()
+}
+
+// SetError reports input that was not accepted, keeping the prompt open.
+func (p *PromptView) SetError(err error) {
+	p.err = err.Error()
+}
+
+// Value returns the input.
+func (p *PromptView) Value() string {
+	return p.input.Value()
+}
+
+// Close hides the prompt.
+func (p *PromptView) Close() {
+	p.visible = false
+	p.input.Blur()
+}
+
+// Visible returns whether the prompt is open.
+func (p *PromptView) Visible() bool {
+	return p.visible
+}
+
+// Update passes keys to the input.
+func (p *PromptView) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+// View renders the prompt centered in its area.
+func (p *PromptView) View() string {
+	if !p.visible {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(model.PanelHeaderStyle.Render(p.title))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+	if p.err != "" {
+		b.WriteString("\n")
+		b.WriteString(model.ErrorStyle.Render(p.err))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(model.HelpKeyStyle.Render("enter") + model.HelpDescStyle.Render(":continue  "))
+	b.WriteString(model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":cancel"))
+
+	box := model.PanelBorderStyle.Width(min(p.width-4, 80)).Render(b.String())
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -16,8 +16,11 @@ type StatusBarState struct {
 	Message     string
 	ChordHint   string
 	ProtoFilter string
+	Scope       string
+	ViewLabel   string
 	SortLabel   string
 	NavFilter   string
+	NetNS       string
 }
 
 // RenderStatusBar renders the bottom status bar.
@@ -51,6 +54,18 @@ func RenderStatusBar(state StatusBarState, width int) string {
 		right = append(right, lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true).Render(state.ProtoFilter))
 	}
 
+	if state.Scope != "" {
+		right = append(right, lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true).Render(state.Scope))
+	}
+
+	if state.ViewLabel != "" {
+		right = append(right, lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true).Render(state.ViewLabel))
+	}
+
+	if state.NetNS != "" {
+		right = append(right, lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true).Render(fmt.Sprintf("[netns: %s]", state.NetNS)))
+	}
+
 	if !state.IsRoot {
 		right = append(right, model.StatusBadgeStyle.Render("[no root]"))
 	}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jerryluo/nettui/internal/data"
+	"github.com/jerryluo/nettui/internal/model"
+)
+
+var (
+	traceMatchStyle = lipgloss.NewStyle().Foreground(model.AccentColor).Bold(true)
+	traceMaybeStyle = lipgloss.NewStyle().Foreground(model.AccentColor)
+	traceMissStyle  = lipgloss.NewStyle().Foreground(model.MutedColor)
+	traceAllowStyle = lipgloss.NewStyle().Foreground(model.SuccessColor).Bold(true)
+	traceBlockStyle = lipgloss.NewStyle().Foreground(model.ErrorColor).Bold(true)
+)
+
+// TraceView is a full-screen firewall trace: the rules considered for a
+// packet, whether each matched and why, and the final verdict. It also
+// prompts for the packet to trace.
+type TraceView struct {
+	viewport viewport.Model
+	input    textinput.Model
+	visible  bool
+	editing  bool
+	traced   bool // a trace is shown behind the prompt
+	err      string
+	width    int
+	height   int
+}
+
+// NewTraceView creates a hidden trace view.
+func NewTraceView() TraceView {
+	in := textinput.New()
+	in.Prompt = "Trace packet: "
+	in.Placeholder = "in tcp 203.0.113.5:51234 -> 10.0.0.2:22"
+	return TraceView{
+		viewport: viewport.New(30, 10),
+		input:    in,
+	}
+}
+
+// SetSize updates the view dimensions.
+func (t *TraceView) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+	t.viewport.Width = width - 4
+	t.viewport.Height = height - 5 // border, prompt and footer
+	t.input.Width = width - 4 - len(t.input.Prompt)
+}
+
+// Show opens the view on a trace.
+func (t *TraceView) Show(tr data.Trace) {
+	t.visible = true
+	t.editing = false
+	t.traced = true
+	t.err = ""
+	t.input.Blur()
+	t.input.SetValue(tr.Packet.String())
+	t.viewport.SetContent(renderTrace(tr))
+	t.viewport.GotoTop()
+}
+
+// Prompt opens the view with the packet prompt focused, prefilled with the
+// packet of the trace shown, if any.
+func (t *TraceView) Prompt() tea.Cmd {
+	t.visible = true
+	t.editing = true
+	t.input.CursorEnd()
+	return t.input.Focus()
+}
+
+// SetError reports a packet that could not be parsed, keeping the prompt
+// open.
+func (t *TraceView) SetError(err error) {
+	t.err = err.Error()
+}
+
+// Input returns the packet typed at the prompt.
+func (t *TraceView) Input() string {
+	return t.input.Value()
+}
+
+// Close hides the view, or leaves the prompt if it is being edited. It
+// returns false if the view was not open.
+func (t *TraceView) Close() bool {
+	switch {
+	case !t.visible:
+		return false
+	case t.editing && t.traced:
+		t.editing = false
+		t.err = ""
+		t.input.Blur()
+	default:
+		t.visible = false
+		t.editing = false
+		t.traced = false
+		t.err = ""
+		t.input.Blur()
+		t.viewport.SetContent("")
+	}
+	return true
+}
+
+// Visible returns whether the view is open.
+func (t *TraceView) Visible() bool {
+	return t.visible
+}
+
+// Editing returns whether the packet prompt has focus.
+func (t *TraceView) Editing() bool {
+	return t.editing
+}
+
+// Update passes keys to the prompt while editing, and scrolls otherwise.
+func (t *TraceView) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if t.editing {
+		t.input, cmd = t.input.Update(msg)
+		t.err = ""
+		return cmd
+	}
+	t.viewport, cmd = t.viewport.Update(msg)
+	return cmd
+}
+
+// View renders the trace view.
+func (t *TraceView) View() string {
+	if !t.visible {
+		return ""
+	}
+	prompt := t.input.View()
+	if t.err != "" {
+		prompt += "  " + model.ErrorStyle.Render(t.err)
+	}
+	footer := model.HelpKeyStyle.Render("e") + model.HelpDescStyle.Render(":edit packet  ") +
+		model.HelpKeyStyle.Render("j/k") + model.HelpDescStyle.Render(":scroll  ") +
+		model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":close")
+	if t.editing {
+		footer = model.HelpKeyStyle.Render("enter") + model.HelpDescStyle.Render(":trace  ") +
+			model.HelpKeyStyle.Render("esc") + model.HelpDescStyle.Render(":cancel")
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left, prompt, "", t.viewport.View(), footer)
+	return model.PanelBorderStyle.
+		Width(t.width - 2).
+		Height(t.height - 2).
+		Render(body)
+}
+
+// renderTrace lists the rules a trace considered, marking the deciding
+// one, followed by the verdict.
+func renderTrace(tr data.Trace) string {
+	var b strings.Builder
+	b.WriteString(model.PanelHeaderStyle.Render("Firewall Trace"))
+	b.WriteString("\n\n")
+
+	b.WriteString(model.PanelLabelStyle.Render(fmt.Sprintf("%-10s", "Verdict")))
+	b.WriteString(traceVerdict(tr))
+	b.WriteString("\n\n")
+
+	for _, s := range tr.Steps {
+		marker := "  "
+		if s.Rule.Ref() == tr.Rule {
+			marker = "▶ "
+		}
+		var status string
+		switch s.Match {
+		case data.MatchYes:
+			status = traceMatchStyle.Render(fmt.Sprintf("%-9s", s.Match))
+		case data.MatchMaybe:
+			status = traceMaybeStyle.Render(fmt.Sprintf("%-9s", s.Match))
+		default:
+			status = traceMissStyle.Render(fmt.Sprintf("%-9s", s.Match))
+		}
+		fmt.Fprintf(&b, "%s%-6s %s %s\n", marker, s.Rule.Ref(), status, s.Rule.RawRule)
+		b.WriteString(traceMissStyle.Render("        " + s.Reason))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func traceVerdict(tr data.Trace) string {
+	switch {
+	case len(tr.Steps) == 0:
+		return traceMissStyle.Render("unknown — no firewall rules loaded")
+	case tr.Verdict == data.VerdictUnknown:
+		return traceMaybeStyle.Render(fmt.Sprintf("unknown — rule %s depends on what cannot be evaluated", tr.Rule))
+	case tr.Rule == "":
+		return traceAllowStyle.Render("allowed — no rule matched, default pass")
+	case tr.Verdict == data.VerdictBlocked:
+		return traceBlockStyle.Render(fmt.Sprintf("blocked by rule %s", tr.Rule))
+	}
+	return traceAllowStyle.Render(fmt.Sprintf("allowed by rule %s", tr.Rule))
+}
@@ -1,6 +1,9 @@
 package util
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // FormatBytes formats bytes into a human-readable string.
 func FormatBytes(b uint64) string {
@@ -46,6 +49,39 @@ func FormatRate(bytesPerSec float64) string {
 	}
 }
 
+// FormatBitRate formats bytes/sec as a bits/sec throughput string.
+func FormatBitRate(bytesPerSec float64) string {
+	bps := bytesPerSec * 8
+	if bps < 0 {
+		bps = 0
+	}
+	switch {
+	case bps >= 1e9:
+		return fmt.Sprintf("%.1f Gb/s", bps/1e9)
+	case bps >= 1e6:
+		return fmt.Sprintf("%.1f Mb/s", bps/1e6)
+	case bps >= 1e3:
+		return fmt.Sprintf("%.1f Kb/s", bps/1e3)
+	default:
+		return fmt.Sprintf("%.0f b/s", bps)
+	}
+}
+
+// FormatPacketRate formats packets/sec into a compact string.
+func FormatPacketRate(pps float64) string {
+	if pps < 0 {
+		pps = 0
+	}
+	switch {
+	case pps >= 1e6:
+		return fmt.Sprintf("%.1fM p/s", pps/1e6)
+	case pps >= 1e3:
+		return fmt.Sprintf("%.1fk p/s", pps/1e3)
+	default:
+		return fmt.Sprintf("%.0f p/s", pps)
+	}
+}
+
 // FormatPort returns the port as a string, or "*" if 0.
 func FormatPort(port uint32) string {
 	if port == 0 {
@@ -82,3 +118,18 @@ func FormatProcess(name string) string {
 	}
 	return name
 }
+
+// FormatDuration renders a duration compactly, like "45s", "3m12s",
+// "23h59m" or "4d2h".
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
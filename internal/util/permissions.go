@@ -1,8 +1,24 @@
 package util
 
-import "os"
+import (
+	"os"
+	"os/user"
+)
 
 // IsRoot returns true if the process is running as root.
 func IsRoot() bool {
 	return os.Geteuid() == 0
 }
+
+// CurrentUser returns the name of the user running nettui, or of the user
+// who invoked it through sudo, or "" if it cannot be determined.
+func CurrentUser() string {
+	if name := os.Getenv("SUDO_USER"); name != "" && IsRoot() {
+		return name
+	}
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
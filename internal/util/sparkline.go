@@ -0,0 +1,37 @@
+package util
+
+import "strings"
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as a row of block characters scaled
+// to the largest value shown. Missing history is padded with spaces on the left.
+func Sparkline(values []float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	peak := 0.0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		idx := 0
+		if peak > 0 && v > 0 {
+			idx = int(v / peak * float64(len(sparkBlocks)-1))
+			if idx >= len(sparkBlocks) {
+				idx = len(sparkBlocks) - 1
+			}
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
@@ -1,24 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jerryluo/nettui/internal/app"
+	"github.com/jerryluo/nettui/internal/data"
 	"github.com/jerryluo/nettui/internal/data/sources"
 	"github.com/jerryluo/nettui/internal/tabs"
 	"github.com/jerryluo/nettui/internal/tabs/arp"
 	"github.com/jerryluo/nettui/internal/tabs/firewall"
+	"github.com/jerryluo/nettui/internal/tabs/flows"
 	"github.com/jerryluo/nettui/internal/tabs/interfaces"
+	"github.com/jerryluo/nettui/internal/tabs/listeners"
 	"github.com/jerryluo/nettui/internal/tabs/processes"
 	"github.com/jerryluo/nettui/internal/tabs/routes"
 	"github.com/jerryluo/nettui/internal/tabs/sockets"
+	"github.com/jerryluo/nettui/internal/tabs/states"
 	"github.com/jerryluo/nettui/internal/tabs/unixsockets"
 )
 
 func main() {
+	smooth := flag.Duration("smooth", 0, "EWMA smoothing window for interface rates (e.g. 10s); 0 disables")
+	interval := flag.Duration("interval", 2*time.Second, "auto-refresh interval; 0 refreshes only on r")
+	netns := flag.String("netns", "", "network namespace to show (Linux): a name from /run/netns, \"pid N (comm)\", or \"all\"")
+	flag.Parse()
+
 	collector := sources.NewCollector()
+	collector.SetSmoothingWindow(*smooth)
 
 	tabModels := []tabs.Tab{
 		sockets.New(collector.DNSCache()),
@@ -28,9 +40,18 @@ func main() {
 		routes.New(),
 		arp.New(),
 		firewall.New(),
+		listeners.New(),
+		states.New(),
+		flows.New(),
+	}
+
+	if *netns == "all" {
+		*netns = data.AllNamespaces
 	}
 
-	model := app.New(tabModels, collector)
+	model := app.New(tabModels, collector).
+		WithRefreshInterval(*interval).
+		WithNamespace(*netns)
 
 	p := tea.NewProgram(
 		model,